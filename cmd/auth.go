@@ -0,0 +1,180 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+	"github.com/spf13/cobra"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect and manage the Whoop authentication credentials.",
+	Long:  "Inspect and manage the Whoop authentication credentials stored in the credentials file.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Display the status of the Whoop authentication token.",
+	Long:  "Display the validity, expiration, granted scopes, and the associated Whoop profile of the authentication token.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return authStatus(cmd.Context())
+	},
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Revoke the Whoop authentication token and delete the credentials file.",
+	Long:  "Revoke the Whoop authentication token with the Whoop API and securely delete the local credentials file.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return authLogout(cmd.Context())
+	},
+}
+
+var (
+	// forceLogout is a flag to delete the local credentials file even if the token revocation fails.
+	forceLogout bool
+)
+
+func init() {
+	authLogoutCmd.PersistentFlags().BoolVar(&forceLogout, "force", false, "Delete the local credentials file even if the token cannot be revoked with the Whoop API.")
+	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	rootCmd.AddCommand(authCmd)
+}
+
+// authStatus displays the status of the Whoop authentication token and the associated Whoop profile.
+func authStatus(ctx context.Context) error {
+
+	err := InitLogger(&Configuration)
+	if err != nil {
+		return err
+	}
+
+	cfg := Configuration
+	credentialsFile := cfg.Credentials.CredentialsFile
+	client := internal.CreateHTTPClient()
+
+	token, err := internal.ReadTokenFromFile(credentialsFile)
+	if err != nil {
+		slog.Error("unable to read the credentials file", "path", credentialsFile, "error", err)
+		return err
+	}
+
+	scopes, err := internal.ReadTokenScopes(credentialsFile)
+	if err != nil {
+		slog.Debug("unable to read the token scopes", "error", err)
+	}
+
+	slog.Info("Credentials file", "path", credentialsFile)
+	slog.Info("Token status",
+		"valid", token.Valid(),
+		"expiry", token.Expiry.Local().Format(time.RFC1123),
+		"expires_in", formatTokenExpiry(token.Expiry, time.Now()),
+		"refresh_token", token.RefreshToken != "",
+	)
+	slog.Info("Granted scopes", "scopes", formatScopes(scopes))
+
+	if !token.Valid() {
+		slog.Warn("The authentication token is invalid or expired. Use the login command to generate a new token or start the server to refresh it.")
+		return nil
+	}
+
+	var user internal.User
+	profile, err := user.GetUserProfileData(ctx, client, internal.DEFAULT_WHOOP_API_USER_DATA_URL, token.AccessToken, UserAgent)
+	if err != nil {
+		slog.Error("unable to retrieve the Whoop profile associated with the token", "error", err)
+		return err
+	}
+
+	slog.Info("Whoop profile",
+		"user_id", profile.UserID,
+		"name", strings.TrimSpace(profile.FirstName+" "+profile.LastName),
+		"email", profile.Email,
+	)
+
+	return nil
+}
+
+// authLogout revokes the Whoop authentication token and securely deletes the credentials file.
+func authLogout(ctx context.Context) error {
+
+	err := InitLogger(&Configuration)
+	if err != nil {
+		return err
+	}
+
+	cfg := Configuration
+	credentialsFile := cfg.Credentials.CredentialsFile
+	client := internal.CreateHTTPClient()
+
+	token, err := internal.ReadTokenFromFile(credentialsFile)
+	if err != nil {
+		slog.Error("unable to read the credentials file", "path", credentialsFile, "error", err)
+		return err
+	}
+
+	switch {
+	case token.AccessToken == "":
+		err = errors.New("the credentials file does not contain an access token")
+	case !token.Valid():
+		err = errors.New("the authentication token is expired and cannot be revoked")
+	default:
+		err = internal.RevokeToken(ctx, client, internal.DEFAULT_WHOOP_API_REVOKE_ACCESS_URL, token.AccessToken, UserAgent)
+	}
+
+	if err != nil {
+		if !forceLogout {
+			slog.Error("unable to revoke the authentication token. Use the --force flag to delete the credentials file anyway", "error", err)
+			return err
+		}
+		slog.Warn("unable to revoke the authentication token. Deleting the credentials file due to the --force flag", "error", err)
+	} else {
+		slog.Info("🔒 Authentication token revoked")
+	}
+
+	err = internal.DeleteLocalToken(credentialsFile)
+	if err != nil {
+		slog.Error("unable to delete the credentials file", "path", credentialsFile, "error", err)
+		return err
+	}
+
+	slog.Info("🗑️ Credentials file deleted", "path", credentialsFile)
+
+	return nil
+}
+
+// formatScopes returns a human readable list of scopes.
+func formatScopes(scopes []string) string {
+
+	if len(scopes) == 0 {
+		return "unknown"
+	}
+
+	return strings.Join(scopes, ", ")
+}
+
+// formatTokenExpiry returns a human readable duration until the token expires relative to now.
+func formatTokenExpiry(expiry, now time.Time) string {
+
+	if expiry.IsZero() {
+		return "never"
+	}
+
+	remaining := expiry.Sub(now).Round(time.Second)
+	if remaining <= 0 {
+		return "expired " + (-remaining).String() + " ago"
+	}
+
+	return remaining.String()
+}
@@ -0,0 +1,74 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatScopes(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		scopes   []string
+		expected string
+	}{
+		{
+			name:     "Test No Scopes",
+			scopes:   []string{},
+			expected: "unknown",
+		},
+		{
+			name:     "Test Multiple Scopes",
+			scopes:   []string{"offline", "read:profile"},
+			expected: "offline, read:profile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatScopes(tt.scopes)
+			if result != tt.expected {
+				t.Errorf("Expected %v, got: %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatTokenExpiry(t *testing.T) {
+
+	now := time.Date(2024, 7, 6, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expiry   time.Time
+		expected string
+	}{
+		{
+			name:     "Test No Expiry",
+			expiry:   time.Time{},
+			expected: "never",
+		},
+		{
+			name:     "Test Valid Token",
+			expiry:   now.Add(30 * time.Minute),
+			expected: "30m0s",
+		},
+		{
+			name:     "Test Expired Token",
+			expiry:   now.Add(-5 * time.Minute),
+			expected: "expired 5m0s ago",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatTokenExpiry(tt.expiry, now)
+			if result != tt.expected {
+				t.Errorf("Expected %v, got: %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+)
+
+// dataCollection collects the data of an account on schedule and catches up the scheduled runs missed while the server was not running.
+// The time of the last successful collection is stored in the state file.
+type dataCollection struct {
+	// mu serializes the scheduled collections and the catch-up collections.
+	mu sync.Mutex
+	// job is the supervised data collection job.
+	job *supervisedJob
+	// crontab is the schedule of the data collection job.
+	crontab string
+	// window is the lookback window of a scheduled collection.
+	window time.Duration
+	// stateFile is the file the time of the last successful collection is stored in.
+	stateFile string
+	// now returns the current time.
+	now func() time.Time
+	// download downloads and exports the data of the window.
+	download func(ctx context.Context, window internal.Window, run *internal.RunRecord) error
+	// exporter is the exporter of the collected data.
+	exporter internal.Export
+	// closed is true once the exporter is cleaned up. The collections requested after the exporter is cleaned up are skipped.
+	closed bool
+}
+
+// newDataCollection returns the data collection of the job scheduled with the crontab. The download function collects the data of a window.
+func newDataCollection(job *supervisedJob, crontab string, window time.Duration, cfg internal.CatchUp, exporter internal.Export, download func(ctx context.Context, window internal.Window, run *internal.RunRecord) error) *dataCollection {
+	return &dataCollection{
+		job:       job,
+		crontab:   crontab,
+		window:    window,
+		stateFile: internal.StateFile(cfg),
+		now:       time.Now,
+		download:  download,
+		exporter:  exporter,
+	}
+}
+
+// scheduled collects the data of the lookback window of the job.
+func (d *dataCollection) scheduled(ctx context.Context) error {
+
+	now := d.now()
+
+	return d.collect(ctx, internal.CatchUpRun{Scheduled: now, Window: internal.LastWindow(now, d.window)}, false)
+}
+
+// collect runs the data collection of the window and stores the time of the run in the state file on success.
+// The data of a catch-up run is named after the date of the missed run.
+func (d *dataCollection) collect(ctx context.Context, catchUp internal.CatchUpRun, missed bool) error {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// A catch-up started before the job was replaced by a reload may still be running
+	if d.closed {
+		slog.Debug("data collection skipped as the job was replaced", "job", d.job.name, "account", d.job.account)
+		return nil
+	}
+
+	dated, ok := d.exporter.(internal.DatedExport)
+	if ok && missed {
+		dated.SetDate(catchUp.Scheduled)
+		defer dated.SetDate(time.Time{})
+	}
+
+	err := d.job.run(ctx, func(ctx context.Context, run *internal.RunRecord) error {
+		run.CatchUp = missed
+		return d.download(ctx, catchUp.Window, run)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = internal.WriteJobState(ctx, d.stateFile, d.job.name, internal.JobState{
+		LastSuccess: catchUp.Scheduled,
+		WindowEnd:   catchUp.Window.End,
+	})
+	if err != nil {
+		slog.Error("unable to store the last successful data collection in the state file", "job", d.job.name, "account", d.job.account, "file", d.stateFile, "error", err)
+	}
+
+	return nil
+}
+
+// close waits for the running collection to complete and cleans up the exporter. The collections requested afterwards are skipped.
+func (d *dataCollection) close() error {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+
+	return d.exporter.CleanUp()
+}
+
+// catchUp collects the data of the scheduled runs missed since the last successful collection using the policy.
+// Nothing is collected on the first start of the server, when the state file contains no collection of the job.
+// The catch-up runs are collected in order and stop at the first failed run. The failed window is collected again on the next start.
+func (d *dataCollection) catchUp(ctx context.Context, policy string) error {
+
+	state, ok, err := internal.ReadJobState(d.stateFile, d.job.name)
+	if err != nil {
+		slog.Error("unable to read the state file. Missed runs are not collected", "job", d.job.name, "account", d.job.account, "file", d.stateFile, "error", err)
+		return err
+	}
+
+	if !ok {
+		slog.Debug("no previous data collection found. Missed runs are not collected", "job", d.job.name, "account", d.job.account)
+		return nil
+	}
+
+	now := d.now()
+	missed, err := internal.MissedRuns(d.crontab, state.LastSuccess, now)
+	if err != nil {
+		return err
+	}
+
+	if len(missed) == 0 {
+		return nil
+	}
+
+	if policy == "" {
+		policy = internal.DEFAULT_CATCH_UP_POLICY
+	}
+
+	if policy == internal.CatchUpSkip {
+		slog.Warn("scheduled data collections were missed. The missed runs are skipped", "job", d.job.name, "account", d.job.account, "missed_runs", len(missed), "last_success", state.LastSuccess.Format(time.RFC3339))
+		return nil
+	}
+
+	start := state.WindowEnd
+	if start.IsZero() || start.Before(now.Add(-internal.DEFAULT_CATCH_UP_MAX_AGE)) {
+		start = missed[0].Add(-d.window)
+	}
+
+	runs := internal.CatchUpRuns(policy, start, missed, now)
+	slog.Info("Catching up missed data collections", "job", d.job.name, "account", d.job.account, "missed_runs", len(missed), "catch_up_runs", len(runs), "policy", policy, "start", start.Format(time.RFC3339))
+
+	for _, run := range runs {
+		err = d.collect(ctx, run, true)
+		if err != nil {
+			slog.Error("catch-up data collection failed", "job", d.job.name, "account", d.job.account, "scheduled", run.Scheduled.Format(time.RFC3339), "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
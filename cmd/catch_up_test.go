@@ -0,0 +1,260 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/karl-cardenas-coding/mywhoop/export"
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+)
+
+// newTestDataCollection returns a data collection recording the collected windows. The collections fail while fail returns true.
+func newTestDataCollection(t *testing.T, now time.Time, fail func() bool) (*dataCollection, *[]internal.Window, *[]bool) {
+
+	t.Helper()
+
+	var windows []internal.Window
+	var catchUps []bool
+
+	job := newSupervisedJob("mywhoop_data_collection_job", "", "Error running the server job.", internal.RetryPolicy{MaxAttempts: 1}, internal.CircuitBreaker{FailureThreshold: -1}, &http.Client{}, nil, nil, nil)
+	exporter := export.NewFileExport(t.TempDir(), "json", "user", "", true)
+
+	collection := newDataCollection(job, internal.DEFAULT_SERVER_CRON_SCHEDULE, 24*time.Hour, internal.CatchUp{StateFile: filepath.Join(t.TempDir(), "mywhoop_state.json")}, exporter, func(ctx context.Context, window internal.Window, run *internal.RunRecord) error {
+		windows = append(windows, window)
+		catchUps = append(catchUps, run.CatchUp)
+		if fail != nil && fail() {
+			return errors.New("failed to get data from the Whoop API")
+		}
+		return nil
+	})
+	collection.now = func() time.Time { return now }
+
+	return collection, &windows, &catchUps
+}
+
+func TestDataCollectionCatchUp(t *testing.T) {
+
+	last := time.Date(2024, 6, 1, 13, 0, 0, 0, time.Local)
+	now := time.Date(2024, 6, 4, 9, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		policy   string
+		expected []internal.Window
+	}{
+		{
+			name:   "once",
+			policy: internal.CatchUpOnce,
+			expected: []internal.Window{
+				{Start: last, End: now},
+			},
+		},
+		{
+			name:   "each",
+			policy: internal.CatchUpEach,
+			expected: []internal.Window{
+				{Start: last, End: time.Date(2024, 6, 2, 13, 0, 0, 0, time.Local)},
+				{Start: time.Date(2024, 6, 2, 13, 0, 0, 0, time.Local), End: time.Date(2024, 6, 3, 13, 0, 0, 0, time.Local)},
+			},
+		},
+		{
+			name:   "skip",
+			policy: internal.CatchUpSkip,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			collection, windows, catchUps := newTestDataCollection(t, now, nil)
+
+			err := internal.WriteJobState(context.Background(), collection.stateFile, collection.job.name, internal.JobState{LastSuccess: last, WindowEnd: last})
+			if err != nil {
+				t.Fatalf("unable to write the state: %v", err)
+			}
+
+			err = collection.catchUp(context.Background(), tc.policy)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(*windows) != len(tc.expected) {
+				t.Fatalf("expected %d collections, got %v", len(tc.expected), *windows)
+			}
+
+			for i, window := range tc.expected {
+				got := (*windows)[i]
+				if !got.Start.Equal(window.Start) || !got.End.Equal(window.End) || !(*catchUps)[i] {
+					t.Errorf("expected the catch-up window %+v, got %+v", window, got)
+				}
+			}
+
+			state, _, err := internal.ReadJobState(collection.stateFile, collection.job.name)
+			if err != nil {
+				t.Fatalf("unable to read the state: %v", err)
+			}
+
+			if len(tc.expected) > 0 && !state.LastSuccess.Equal(time.Date(2024, 6, 3, 13, 0, 0, 0, time.Local)) {
+				t.Errorf("expected the last missed run to be stored, got %+v", state)
+			}
+
+			// A second start has no missed run to collect
+			err = collection.catchUp(context.Background(), tc.policy)
+			if err != nil || (len(tc.expected) > 0 && len(*windows) != len(tc.expected)) {
+				t.Errorf("expected no catch-up on the second start, got %v, %v", *windows, err)
+			}
+		})
+	}
+}
+
+func TestDataCollectionFirstStart(t *testing.T) {
+
+	collection, windows, _ := newTestDataCollection(t, time.Now(), nil)
+
+	err := collection.catchUp(context.Background(), internal.CatchUpOnce)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(*windows) != 0 {
+		t.Errorf("expected no catch-up without a previous collection, got %v", *windows)
+	}
+}
+
+func TestDataCollectionScheduled(t *testing.T) {
+
+	now := time.Date(2024, 6, 4, 13, 0, 0, 0, time.Local)
+	fail := true
+	collection, windows, catchUps := newTestDataCollection(t, now, func() bool { return fail })
+
+	err := collection.scheduled(context.Background())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	_, ok, _ := internal.ReadJobState(collection.stateFile, collection.job.name)
+	if ok {
+		t.Error("expected a failed collection to leave the state unchanged")
+	}
+
+	fail = false
+	err = collection.scheduled(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	last := (*windows)[len(*windows)-1]
+	if !last.End.Equal(now) || last.End.Sub(last.Start) != 24*time.Hour || (*catchUps)[len(*catchUps)-1] {
+		t.Errorf("expected a scheduled window of the last 24 hours, got %+v", last)
+	}
+
+	state, ok, err := internal.ReadJobState(collection.stateFile, collection.job.name)
+	if err != nil || !ok || !state.LastSuccess.Equal(now) {
+		t.Errorf("expected the scheduled run to be stored, got %+v, %v, %v", state, ok, err)
+	}
+}
+
+func TestDataCollectionClose(t *testing.T) {
+
+	now := time.Date(2024, 6, 4, 13, 0, 0, 0, time.Local)
+	collection, windows, _ := newTestDataCollection(t, now, nil)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	download := collection.download
+	collection.download = func(ctx context.Context, window internal.Window, run *internal.RunRecord) error {
+		close(started)
+		<-release
+		return download(ctx, window, run)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- collection.scheduled(context.Background()) }()
+	<-started
+
+	closed := make(chan error, 1)
+	go func() { closed <- collection.close() }()
+
+	// The exporter is cleaned up once the running collection completes
+	select {
+	case <-closed:
+		t.Fatal("expected close to wait for the running collection")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := <-closed; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The collections requested after close are skipped
+	err := collection.scheduled(context.Background())
+	if err != nil || len(*windows) != 1 {
+		t.Errorf("expected the collection to be skipped after close, got %d windows, %v", len(*windows), err)
+	}
+}
+
+func TestDataCollectionCatchUpFailure(t *testing.T) {
+
+	last := time.Date(2024, 6, 1, 13, 0, 0, 0, time.Local)
+	collection, windows, _ := newTestDataCollection(t, time.Date(2024, 6, 4, 9, 0, 0, 0, time.Local), func() bool { return true })
+
+	err := internal.WriteJobState(context.Background(), collection.stateFile, collection.job.name, internal.JobState{LastSuccess: last, WindowEnd: last})
+	if err != nil {
+		t.Fatalf("unable to write the state: %v", err)
+	}
+
+	err = collection.catchUp(context.Background(), internal.CatchUpEach)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// The catch-up stops at the first failed run and the state is unchanged
+	if len(*windows) != 1 {
+		t.Errorf("expected a single collection, got %v", *windows)
+	}
+
+	state, _, _ := internal.ReadJobState(collection.stateFile, collection.job.name)
+	if !state.LastSuccess.Equal(last) {
+		t.Errorf("expected the state to be unchanged, got %+v", state)
+	}
+}
+
+func TestDataCollectionScheduledWindow(t *testing.T) {
+
+	now := time.Date(2024, 6, 4, 13, 0, 0, 0, time.Local)
+	collection, windows, _ := newTestDataCollection(t, now, nil)
+	collection.crontab = "0 * * * *"
+	collection.window = 6 * time.Hour
+
+	err := collection.scheduled(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	last := (*windows)[0]
+	if !last.End.Equal(now) || last.End.Sub(last.Start) != 6*time.Hour {
+		t.Errorf("expected a scheduled window of the last 6 hours, got %+v", last)
+	}
+
+	// The missed hourly runs are caught up in a single window starting at the end of the last window
+	collection.now = func() time.Time { return now.Add(3*time.Hour + 30*time.Minute) }
+
+	err = collection.catchUp(context.Background(), internal.CatchUpOnce)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(*windows) != 2 || !(*windows)[1].Start.Equal(now) || !(*windows)[1].End.Equal(now.Add(3*time.Hour+30*time.Minute)) {
+		t.Errorf("expected a catch-up window since the last run, got %v", *windows)
+	}
+}
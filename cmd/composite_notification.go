@@ -0,0 +1,128 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+	"github.com/karl-cardenas-coding/mywhoop/notifications"
+)
+
+// notificationChannel is a notification method of a list of notification channels.
+type notificationChannel struct {
+	// name identifies the channel in logs and errors.
+	name string
+	// notification is the notification method of the channel.
+	notification internal.Notification
+}
+
+// channelResult is the result of publishing a notification to a notification channel.
+type channelResult struct {
+	// channel is the name of the notification channel.
+	channel string
+	// err is the error returned by the notification channel.
+	err error
+}
+
+// compositeNotification publishes every notification to a list of notification channels.
+// The channels are published to concurrently. Each channel applies its own events filter.
+type compositeNotification struct {
+	// channels are the notification channels.
+	channels []notificationChannel
+	// timeout is the maximum time to wait for the channels to publish a notification.
+	timeout time.Duration
+}
+
+// newCompositeNotification returns a notification that publishes to every channel.
+func newCompositeNotification(channels []notificationChannel) *compositeNotification {
+	return &compositeNotification{
+		channels: channels,
+		timeout:  internal.DEFAULT_NOTIFICATION_PUBLISH_TIMEOUT,
+	}
+}
+
+// SetUp sets up every notification channel. The errors of all the channels are returned.
+func (c *compositeNotification) SetUp() error {
+
+	var errs []error
+	for _, channel := range c.channels {
+		err := channel.notification.SetUp()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notification channel %s: %w", channel.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Publish sends the message to every notification channel.
+func (c *compositeNotification) Publish(client *http.Client, data []byte, event string) error {
+
+	if data == nil {
+		return errors.New("no data provided for external notification")
+	}
+
+	return c.PublishMessage(client, notifications.NewMessage(data, event))
+}
+
+// PublishMessage sends the structured message to every notification channel concurrently.
+// The errors of the channels are aggregated. Channels that do not complete within the timeout are reported as errors
+// and left to complete in the background so that the caller, such as the data collection job, is not blocked.
+func (c *compositeNotification) PublishMessage(client *http.Client, msg notifications.Message) error {
+
+	results := make(chan channelResult, len(c.channels))
+	for _, channel := range c.channels {
+		go func(channel notificationChannel) {
+			results <- channelResult{
+				channel: channel.name,
+				err:     publishMessage(client, channel.notification, msg),
+			}
+		}(channel)
+	}
+
+	var errs []error
+	pending := make(map[string]bool, len(c.channels))
+	for _, channel := range c.channels {
+		pending[channel.name] = true
+	}
+
+	timeout := time.After(c.timeout)
+	for len(pending) > 0 {
+		select {
+		case r := <-results:
+			delete(pending, r.channel)
+			if r.err != nil {
+				errs = append(errs, fmt.Errorf("notification channel %s: %w", r.channel, r.err))
+			}
+
+		case <-timeout:
+			for channel := range pending {
+				slog.Warn("notification channel did not complete in time. The notification continues in the background", "channel", channel, "timeout", c.timeout)
+				errs = append(errs, fmt.Errorf("notification channel %s did not complete within %s", channel, c.timeout))
+			}
+			go logLateResults(results, len(pending))
+			return errors.Join(errs...)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// logLateResults logs the results of the notification channels that completed after the timeout.
+func logLateResults(results <-chan channelResult, count int) {
+
+	for i := 0; i < count; i++ {
+		r := <-results
+		if r.err != nil {
+			slog.Error("unable to send notification", "channel", r.channel, "error", r.err)
+			continue
+		}
+		slog.Info("notification channel completed after the timeout", "channel", r.channel)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+	"github.com/karl-cardenas-coding/mywhoop/notifications"
+)
+
+// blockingNotification is a notification method that waits until it is released and returns the configured error.
+type blockingNotification struct {
+	mu       sync.Mutex
+	release  chan struct{}
+	err      error
+	messages []string
+}
+
+func (b *blockingNotification) SetUp() error {
+	return nil
+}
+
+func (b *blockingNotification) Publish(client *http.Client, data []byte, event string) error {
+	if b.release != nil {
+		<-b.release
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.messages = append(b.messages, string(data))
+
+	return b.err
+}
+
+func TestCompositeNotification(t *testing.T) {
+
+	ok := &blockingNotification{}
+	failing := &blockingNotification{err: errors.New("connection refused")}
+	structured := &mockMessagePublisher{}
+
+	composite := newCompositeNotification([]notificationChannel{
+		{name: "1-ntfy", notification: ok},
+		{name: "2-webhook", notification: failing},
+		{name: "3-slack", notification: structured},
+	})
+
+	err := composite.Publish(nil, []byte("Failed to export data. Additional context below: \n access denied"), internal.EventErrors.String())
+	if err == nil {
+		t.Fatalf("expected the error of the failing channel, got nil")
+	}
+
+	if !strings.Contains(err.Error(), "2-webhook") || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected the error to name the failing channel, got: %v", err)
+	}
+
+	if len(ok.messages) != 1 || len(failing.messages) != 1 {
+		t.Errorf("expected every channel to receive the message, got: %v %v", ok.messages, failing.messages)
+	}
+
+	if len(structured.structured) != 1 || structured.structured[0].Error != "access denied" {
+		t.Errorf("expected the structured message to be preserved, got: %+v", structured.structured)
+	}
+}
+
+func TestCompositeNotificationTimeout(t *testing.T) {
+
+	slow := &blockingNotification{release: make(chan struct{})}
+	fast := &blockingNotification{}
+
+	composite := newCompositeNotification([]notificationChannel{
+		{name: "1-webhook", notification: slow},
+		{name: "2-ntfy", notification: fast},
+	})
+	composite.timeout = 50 * time.Millisecond
+
+	start := time.Now()
+	err := composite.Publish(nil, []byte("Daily data collection complete."), internal.EventSuccess.String())
+	if err == nil || !strings.Contains(err.Error(), "1-webhook did not complete") {
+		t.Errorf("expected a timeout error for the slow channel, got: %v", err)
+	}
+
+	if time.Since(start) > time.Second {
+		t.Errorf("expected the publish to return after the timeout, took: %s", time.Since(start))
+	}
+
+	if len(fast.messages) != 1 {
+		t.Errorf("expected the fast channel to receive the message, got: %v", fast.messages)
+	}
+
+	close(slow.release)
+}
+
+func TestDetermineNotificationExtensionChannels(t *testing.T) {
+
+	t.Setenv("NOTIFICATION_SLACK_WEBHOOK_URL", "https://hooks.slack.com/services/T000/B000/XXXX")
+	t.Setenv("NOTIFICATION_NTFY_AUTH_TOKEN", "1234")
+
+	cfg := internal.ConfigurationData{
+		Notification: internal.NotificationConfig{
+			Channels: []internal.NotificationConfig{
+				{Method: "slack", Events: "success", Slack: notifications.Slack{Events: "errors"}},
+				{Method: "ntfy", Ntfy: notifications.Ntfy{ServerEndpoint: "http://localhost:8080", SubscriptionID: "1234", Events: "errors"}},
+				{},
+			},
+		},
+	}
+
+	notification, err := determineNotificationExtension(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	composite, ok := notification.(*compositeNotification)
+	if !ok {
+		t.Fatalf("expected a composite notification, got: %T", notification)
+	}
+
+	if len(composite.channels) != 3 {
+		t.Fatalf("expected 3 channels, got: %d", len(composite.channels))
+	}
+
+	slack, ok := composite.channels[0].notification.(*notifications.Slack)
+	if !ok {
+		t.Fatalf("expected the first channel to be Slack, got: %T", composite.channels[0].notification)
+	}
+
+	if slack.Events != "success" {
+		t.Errorf("expected the channel events filter to take precedence, got: %s", slack.Events)
+	}
+
+	ntfy := composite.channels[1].notification.(*notifications.Ntfy)
+	if ntfy.Events != "errors" {
+		t.Errorf("expected the method events filter, got: %s", ntfy.Events)
+	}
+
+	if _, ok := composite.channels[2].notification.(*notifications.Stdout); !ok || composite.channels[2].name != "3-stdout" {
+		t.Errorf("expected the third channel to be stdout, got: %s %T", composite.channels[2].name, composite.channels[2].notification)
+	}
+
+	cfg.Notification.Channels = append(cfg.Notification.Channels, internal.NotificationConfig{Method: "discord"})
+	_, err = determineNotificationExtension(cfg)
+	if err == nil || !strings.Contains(err.Error(), "channel 4 (discord)") {
+		t.Errorf("expected an error naming the invalid channel, got: %v", err)
+	}
+}
@@ -0,0 +1,435 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Validate, generate, and display the MyWhoop configuration.",
+	Long:  "Validate, generate, and display the MyWhoop configuration file and the effective configuration.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Validate a MyWhoop configuration file.",
+	Long:  "Validate a MyWhoop configuration file and display every invalid field with its line number. The file provided through the --config flag or the default configuration file is used if no file is specified.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filePath := cfgFile
+		if len(args) == 1 {
+			filePath = args[0]
+		}
+		return configValidate(cmd.OutOrStdout(), filePath)
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Generate a starter MyWhoop configuration file.",
+	Long:  "Generate a starter MyWhoop configuration file using the provided flags. Use the --interactive flag to be prompted for each value.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return configInit(cmd.InOrStdin(), cmd.OutOrStdout(), initOptions)
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Display the effective MyWhoop configuration.",
+	Long:  "Display the effective MyWhoop configuration after merging the configuration file, environment variables, and CLI flags. Secrets are redacted.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return configShow(cmd.OutOrStdout(), showFormat)
+	},
+}
+
+// configInitOptions are the values used to generate a starter configuration file.
+type configInitOptions struct {
+	// output is the file path of the generated configuration file.
+	output string
+	// interactive prompts for each value instead of using the flags.
+	interactive bool
+	// force overwrites an existing configuration file.
+	force bool
+	// exportMethod is the export method. Allowed values are file and s3.
+	exportMethod string
+	// exportPath is the directory or bucket prefix the data is exported to.
+	exportPath string
+	// exportType is the file type of the exported data.
+	exportType string
+	// s3Bucket is the name of the S3 bucket.
+	s3Bucket string
+	// s3Region is the region of the S3 bucket.
+	s3Region string
+	// notificationMethod is the notification method. Allowed values are ntfy, slack, discord, or an empty string.
+	notificationMethod string
+	// ntfyServer is the endpoint of the ntfy server.
+	ntfyServer string
+	// ntfyTopic is the ntfy subscription ID.
+	ntfyTopic string
+	// server enables server mode.
+	server bool
+	// crontab is the server mode schedule.
+	crontab string
+}
+
+var (
+	// initOptions contains the flags of the config init command.
+	initOptions configInitOptions
+	// showFormat is the output format of the config show command.
+	showFormat string
+)
+
+func init() {
+	configInitCmd.PersistentFlags().StringVarP(&initOptions.output, "output", "o", "", "The file path of the generated configuration file. Default is $HOME/.mywhoop.yaml. Use a .json extension to generate a JSON file.")
+	configInitCmd.PersistentFlags().BoolVarP(&initOptions.interactive, "interactive", "i", false, "Prompt for each configuration value.")
+	configInitCmd.PersistentFlags().BoolVar(&initOptions.force, "force", false, "Overwrite the configuration file if it already exists.")
+	configInitCmd.PersistentFlags().StringVar(&initOptions.exportMethod, "export-method", "file", "The export method. Allowed values are file and s3.")
+	configInitCmd.PersistentFlags().StringVar(&initOptions.exportPath, "export-path", "data/", "The directory or the S3 key prefix the data is exported to.")
+	configInitCmd.PersistentFlags().StringVar(&initOptions.exportType, "export-type", "json", "The file type of the exported data.")
+	configInitCmd.PersistentFlags().StringVar(&initOptions.s3Bucket, "s3-bucket", "", "The name of the S3 bucket. Required if the export method is s3.")
+	configInitCmd.PersistentFlags().StringVar(&initOptions.s3Region, "s3-region", "", "The region of the S3 bucket.")
+	configInitCmd.PersistentFlags().StringVar(&initOptions.notificationMethod, "notification-method", "", "The notification method. Allowed values are ntfy, slack, discord, or an empty value to log notifications.")
+	configInitCmd.PersistentFlags().StringVar(&initOptions.ntfyServer, "ntfy-server", "", "The endpoint of the ntfy server.")
+	configInitCmd.PersistentFlags().StringVar(&initOptions.ntfyTopic, "ntfy-topic", "", "The ntfy subscription ID.")
+	configInitCmd.PersistentFlags().BoolVar(&initOptions.server, "server", false, "Enable server mode.")
+	configInitCmd.PersistentFlags().StringVar(&initOptions.crontab, "crontab", internal.DEFAULT_SERVER_CRON_SCHEDULE, "The server mode schedule in the crontab format.")
+	configShowCmd.PersistentFlags().StringVarP(&showFormat, "format", "f", "yaml", "The output format. Supported formats are yaml and json.")
+	configCmd.AddCommand(configValidateCmd)
+	configCmd.AddCommand(configInitCmd)
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// configValidate validates the configuration file and writes a human readable error for every invalid field.
+func configValidate(out io.Writer, filePath string) error {
+
+	ok, configFilePath := internal.CheckConfigFile(filePath)
+	if !ok {
+		return errors.New("no configuration file found. Provide the file path of the configuration file to validate")
+	}
+
+	configErrors, err := internal.ValidateConfigFile(configFilePath)
+	if err != nil {
+		slog.Error("unable to validate the configuration file", "config", configFilePath, "error", err)
+		return err
+	}
+
+	if len(configErrors) == 0 {
+		slog.Info("✅ The configuration file is valid", "config", configFilePath)
+		return nil
+	}
+
+	for _, configErr := range configErrors {
+		fmt.Fprintf(out, "%s: %s\n", configFilePath, configErr.Error())
+	}
+
+	return fmt.Errorf("the configuration file %s contains %d error(s)", configFilePath, len(configErrors))
+}
+
+// configInit generates a starter configuration file.
+func configInit(in io.Reader, out io.Writer, opts configInitOptions) error {
+
+	if opts.output == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			slog.Error("unable to get user home directory", "error", err)
+			return err
+		}
+		opts.output = filepath.Join(home, internal.DEFAULT_CONFIG_FILE)
+	}
+
+	if _, err := os.Stat(opts.output); err == nil && !opts.force {
+		return fmt.Errorf("the configuration file %s already exists. Use the --force flag to overwrite it", opts.output)
+	}
+
+	if opts.interactive {
+		err := promptConfigInit(in, out, &opts)
+		if err != nil {
+			return err
+		}
+	}
+
+	cfg := starterConfig(opts)
+
+	err := internal.ValidateConfiguration(cfg)
+	if err != nil {
+		return fmt.Errorf("the provided values do not generate a valid configuration: %w", err)
+	}
+
+	content, err := marshalConfig(cfg, opts.output)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(opts.output), 0755)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(opts.output, content, 0600)
+	if err != nil {
+		slog.Error("unable to write the configuration file", "config", opts.output, "error", err)
+		return err
+	}
+
+	slog.Info("✅ Configuration file generated", "config", opts.output)
+
+	return nil
+}
+
+// configShow writes the effective configuration with the secrets redacted.
+func configShow(out io.Writer, format string) error {
+
+	// The secret references are displayed as is
+	keepSecretReferences = true
+
+	err := InitLogger(&Configuration)
+	if err != nil {
+		return err
+	}
+
+	cfg := internal.RedactSecrets(Configuration)
+
+	var content []byte
+	switch strings.ToLower(format) {
+	case "yaml", "yml":
+		content, err = yaml.Marshal(cfg)
+	case "json":
+		content, err = json.MarshalIndent(cfg, "", "  ")
+		content = append(content, '\n')
+	default:
+		return fmt.Errorf("unsupported output format %s. Supported formats are yaml and json", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(content)
+
+	return err
+}
+
+// starterConfig returns the configuration generated from the config init options.
+func starterConfig(opts configInitOptions) internal.ConfigurationData {
+
+	var cfg internal.ConfigurationData
+
+	cfg.Export.Method = opts.exportMethod
+	switch opts.exportMethod {
+	case "s3":
+		cfg.Export.AWSS3.Bucket = opts.s3Bucket
+		cfg.Export.AWSS3.Region = opts.s3Region
+		cfg.Export.AWSS3.FileConfig.FilePath = opts.exportPath
+		cfg.Export.AWSS3.FileConfig.FileType = opts.exportType
+		cfg.Export.AWSS3.FileConfig.FileName = "user"
+	default:
+		cfg.Export.FileExport.FilePath = opts.exportPath
+		cfg.Export.FileExport.FileType = opts.exportType
+		cfg.Export.FileExport.FileName = "user"
+	}
+
+	cfg.Notification.Method = opts.notificationMethod
+	if opts.notificationMethod == "ntfy" {
+		cfg.Notification.Ntfy.ServerEndpoint = opts.ntfyServer
+		cfg.Notification.Ntfy.SubscriptionID = opts.ntfyTopic
+		cfg.Notification.Ntfy.Events = internal.EventErrors.String()
+	}
+	if opts.notificationMethod == "slack" {
+		cfg.Notification.Slack.Events = internal.EventErrors.String()
+	}
+	if opts.notificationMethod == "discord" {
+		cfg.Notification.Discord.Events = internal.EventErrors.String()
+	}
+
+	cfg.Server.Enabled = opts.server
+	if opts.server {
+		cfg.Server.Crontab = opts.crontab
+		cfg.Server.JWTRefreshDuration = int(internal.DEFAULT_SERVER_TOKEN_REFRESH_CRON_SCHEDULE.Minutes())
+	}
+
+	cfg.Credentials.CredentialsFile = internal.DEFAULT_CREDENTIALS_FILE
+	cfg.Debug = "INFO"
+
+	return cfg
+}
+
+// marshalConfig returns the content of the configuration file. JSON is used for files with a .json extension, YAML otherwise.
+// Only the fields set by the config init options are written, as the other fields use their default values.
+func marshalConfig(cfg internal.ConfigurationData, filePath string) ([]byte, error) {
+
+	var node yaml.Node
+	err := node.Encode(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	pruneZeroValues(&node)
+
+	if strings.HasSuffix(filePath, ".json") {
+		var value any
+		err := node.Decode(&value)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(content, '\n'), nil
+	}
+
+	content, err := yaml.Marshal(&node)
+	if err != nil {
+		return nil, err
+	}
+
+	header := fmt.Sprintf("# MyWhoop configuration file generated by mywhoop config init.\n# yaml-language-server: $schema=%s\n\n", internal.JSON_SCHEMA_ID)
+
+	return append([]byte(header), content...), nil
+}
+
+// pruneZeroValues removes the mapping entries holding a zero value or an empty mapping from the node tree.
+// The starter configuration does not set pointer fields, so a removed false or zero value is equivalent to the default value.
+// Returns true if the node is empty after pruning.
+func pruneZeroValues(node *yaml.Node) bool {
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		var content []*yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if !pruneZeroValues(node.Content[i+1]) {
+				content = append(content, node.Content[i], node.Content[i+1])
+			}
+		}
+		node.Content = content
+		return len(content) == 0
+
+	case yaml.SequenceNode:
+		return len(node.Content) == 0
+
+	case yaml.ScalarNode:
+		switch node.Tag {
+		case "!!null":
+			return true
+		case "!!str":
+			return node.Value == ""
+		case "!!int", "!!float":
+			return node.Value == "0"
+		case "!!bool":
+			return node.Value == "false"
+		}
+	}
+
+	return false
+}
+
+// promptConfigInit prompts for each configuration value. The current option values are used as the defaults.
+func promptConfigInit(in io.Reader, out io.Writer, opts *configInitOptions) error {
+
+	scanner := bufio.NewScanner(in)
+
+	prompt := func(question, defaultValue string) (string, error) {
+		fmt.Fprintf(out, "%s [%s]: ", question, defaultValue)
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return defaultValue, nil
+		}
+
+		answer := strings.TrimSpace(scanner.Text())
+		if answer == "" {
+			return defaultValue, nil
+		}
+
+		return answer, nil
+	}
+
+	var err error
+
+	if opts.exportMethod, err = prompt("Export method (file, s3)", opts.exportMethod); err != nil {
+		return err
+	}
+
+	if opts.exportMethod == "s3" {
+		if opts.s3Bucket, err = prompt("S3 bucket", opts.s3Bucket); err != nil {
+			return err
+		}
+		if opts.s3Region, err = prompt("S3 region", opts.s3Region); err != nil {
+			return err
+		}
+	}
+
+	if opts.exportPath, err = prompt("Export path", opts.exportPath); err != nil {
+		return err
+	}
+
+	if opts.exportType, err = prompt("Export file type (json, xlsx)", opts.exportType); err != nil {
+		return err
+	}
+
+	if opts.notificationMethod, err = prompt("Notification method (ntfy, slack, discord, none)", orDefault(opts.notificationMethod, "none")); err != nil {
+		return err
+	}
+
+	if opts.notificationMethod == "none" {
+		opts.notificationMethod = ""
+	}
+
+	if opts.notificationMethod == "ntfy" {
+		if opts.ntfyServer, err = prompt("Ntfy server endpoint", opts.ntfyServer); err != nil {
+			return err
+		}
+		if opts.ntfyTopic, err = prompt("Ntfy subscription ID", opts.ntfyTopic); err != nil {
+			return err
+		}
+	}
+
+	server, err := prompt("Enable server mode (true, false)", strconv.FormatBool(opts.server))
+	if err != nil {
+		return err
+	}
+
+	opts.server, err = strconv.ParseBool(server)
+	if err != nil {
+		return fmt.Errorf("invalid value for server mode: %w", err)
+	}
+
+	if opts.server {
+		if opts.crontab, err = prompt("Server schedule (crontab)", opts.crontab); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// orDefault returns the value or the default value if the value is empty.
+func orDefault(value, defaultValue string) string {
+
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
@@ -0,0 +1,219 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+)
+
+func TestConfigInit(t *testing.T) {
+
+	tests := []struct {
+		description string
+		fileName    string
+		opts        configInitOptions
+		input       string
+		expected    func(cfg internal.ConfigurationData) bool
+	}{
+		{
+			description: "Flag driven file export",
+			fileName:    ".mywhoop.yaml",
+			opts: configInitOptions{
+				exportMethod: "file",
+				exportPath:   "/opt/mywhoop/data/",
+				exportType:   "json",
+			},
+			expected: func(cfg internal.ConfigurationData) bool {
+				return cfg.Export.Method == "file" && cfg.Export.FileExport.FilePath == "/opt/mywhoop/data/" && !cfg.Server.Enabled
+			},
+		},
+		{
+			description: "Flag driven JSON file with server mode",
+			fileName:    ".mywhoop.json",
+			opts: configInitOptions{
+				exportMethod: "file",
+				exportPath:   "data/",
+				exportType:   "xlsx",
+				server:       true,
+				crontab:      "0 6 * * *",
+			},
+			expected: func(cfg internal.ConfigurationData) bool {
+				return cfg.Server.Enabled && cfg.Server.Crontab == "0 6 * * *" && cfg.Server.JWTRefreshDuration == 45
+			},
+		},
+		{
+			description: "Interactive s3 export with ntfy",
+			fileName:    ".mywhoop.yaml",
+			opts: configInitOptions{
+				interactive:  true,
+				exportMethod: "file",
+				exportPath:   "data/",
+				exportType:   "json",
+				crontab:      internal.DEFAULT_SERVER_CRON_SCHEDULE,
+			},
+			input: "s3\nmy-bucket\nus-east-1\n\n\nntfy\nhttps://ntfy.sh\nmywhoop\ntrue\n\n",
+			expected: func(cfg internal.ConfigurationData) bool {
+				return cfg.Export.Method == "s3" &&
+					cfg.Export.AWSS3.Bucket == "my-bucket" &&
+					cfg.Export.AWSS3.Region == "us-east-1" &&
+					cfg.Notification.Ntfy.ServerEndpoint == "https://ntfy.sh" &&
+					cfg.Server.Crontab == internal.DEFAULT_SERVER_CRON_SCHEDULE
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			test.opts.output = filepath.Join(t.TempDir(), test.fileName)
+
+			var out bytes.Buffer
+			err := configInit(strings.NewReader(test.input), &out, test.opts)
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+
+			cfg, err := internal.GenerateConfigStruct(test.opts.output)
+			if err != nil {
+				t.Fatalf("Expected the generated configuration file to be valid but got %v", err)
+			}
+
+			if !test.expected(cfg) {
+				t.Errorf("Unexpected generated configuration: %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestConfigInitOnlyWritesSetFields(t *testing.T) {
+
+	tests := []struct {
+		fileName string
+		expected string
+	}{
+		{fileName: ".mywhoop.yaml", expected: "server:\n    enabled: true\n    crontab: 0 6 * * *\n    jwtRefreshDuration: 45\n"},
+		{fileName: ".mywhoop.json", expected: "\"crontab\": \"0 6 * * *\""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fileName, func(t *testing.T) {
+			opts := configInitOptions{
+				output:       filepath.Join(t.TempDir(), test.fileName),
+				exportMethod: "file",
+				exportPath:   "data/",
+				exportType:   "json",
+				server:       true,
+				crontab:      "0 6 * * *",
+			}
+
+			err := configInit(strings.NewReader(""), &bytes.Buffer{}, opts)
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+
+			content, err := os.ReadFile(opts.output)
+			if err != nil {
+				t.Fatalf("Failed to read the generated configuration file: %v", err)
+			}
+
+			if !strings.Contains(string(content), test.expected) {
+				t.Errorf("Expected the generated configuration file to contain %q but got:\n%s", test.expected, content)
+			}
+
+			for _, field := range []string{"awsS3", "notification", "accounts", "webhook"} {
+				if strings.Contains(string(content), field) {
+					t.Errorf("Expected the unset field %s to be omitted but got:\n%s", field, content)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigInitInvalidCrontab(t *testing.T) {
+
+	opts := configInitOptions{
+		output:       filepath.Join(t.TempDir(), ".mywhoop.yaml"),
+		exportMethod: "file",
+		exportPath:   "data/",
+		server:       true,
+		crontab:      "every hour",
+	}
+
+	err := configInit(strings.NewReader(""), &bytes.Buffer{}, opts)
+	if err == nil || !strings.Contains(err.Error(), "invalid server crontab") {
+		t.Fatalf("Expected an error due to the invalid crontab but got %v", err)
+	}
+}
+
+func TestConfigInitExistingFile(t *testing.T) {
+
+	output := filepath.Join(t.TempDir(), ".mywhoop.yaml")
+	err := os.WriteFile(output, []byte("debug: info\n"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to create the configuration file: %v", err)
+	}
+
+	opts := configInitOptions{
+		output:       output,
+		exportMethod: "file",
+	}
+
+	err = configInit(strings.NewReader(""), &bytes.Buffer{}, opts)
+	if err == nil {
+		t.Fatalf("Expected an error as the configuration file already exists")
+	}
+
+	opts.force = true
+	err = configInit(strings.NewReader(""), &bytes.Buffer{}, opts)
+	if err != nil {
+		t.Fatalf("Expected no error with the force flag but got %v", err)
+	}
+}
+
+func TestConfigInitInvalidValues(t *testing.T) {
+
+	opts := configInitOptions{
+		output:       filepath.Join(t.TempDir(), ".mywhoop.yaml"),
+		exportMethod: "ftp",
+	}
+
+	err := configInit(strings.NewReader(""), &bytes.Buffer{}, opts)
+	if err == nil {
+		t.Fatalf("Expected an error due to the invalid export method")
+	}
+
+	if _, err := os.Stat(opts.output); !os.IsNotExist(err) {
+		t.Errorf("Expected no configuration file to be generated")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+
+	var out bytes.Buffer
+	err := configValidate(&out, "../tests/valid_config.yaml")
+	if err != nil {
+		t.Errorf("Expected no error but got %v", err)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "config.yaml")
+	err = os.WriteFile(invalid, []byte("export:\n  method: ftp\n"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to create the configuration file: %v", err)
+	}
+
+	out.Reset()
+	err = configValidate(&out, invalid)
+	if err == nil {
+		t.Fatalf("Expected an error due to the invalid configuration file")
+	}
+
+	if !strings.Contains(out.String(), "line 2: export.method") {
+		t.Errorf("Expected the invalid field and its line number in the output but got %s", out.String())
+	}
+}
@@ -0,0 +1,420 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+	"github.com/karl-cardenas-coding/mywhoop/notifications"
+)
+
+// spoolMu serializes the access to the spool files. Notification channels with the same configuration share a spool file.
+// The lock is not held while the stored notifications are sent.
+var spoolMu sync.Mutex
+
+// eventFilter is implemented by the notification methods with an events filter.
+type eventFilter interface {
+	// Accepts returns true if the events filter accepts the event.
+	Accepts(event string) bool
+}
+
+// deliveryNotification delivers the notifications of a notification channel.
+// Failed notifications are retried with an exponential backoff, identical error and alert notifications are de-duplicated within a window,
+// the number of notifications per hour is limited, and undelivered notifications are stored in a spool file until they can be delivered.
+type deliveryNotification struct {
+	// name is the name of the notification channel used in logs.
+	name string
+	// notification is the notification method of the channel.
+	notification internal.Notification
+	// maxRetries is the number of times a failed notification is retried.
+	maxRetries int
+	// retryInterval is the initial interval between retries.
+	retryInterval time.Duration
+	// dedupWindow is the window during which identical error and alert notifications are only sent once. Zero disables the de-duplication.
+	dedupWindow time.Duration
+	// rateLimit is the maximum number of notifications per hour. Zero disables the rate limit.
+	rateLimit int
+	// spoolFile is the file where undelivered notifications are stored. Empty if undelivered notifications are dropped.
+	spoolFile string
+	// now returns the current time.
+	now func() time.Time
+
+	// mu protects sent and history.
+	mu sync.Mutex
+	// sent contains the time the de-duplicated notifications were last sent.
+	sent map[string]time.Time
+	// history contains the times of the notifications sent within the last hour.
+	history []time.Time
+}
+
+// newDeliveryNotification returns the notification method of the channel wrapped with the delivery settings.
+// The spool file of the channel is named after the channel and a fingerprint of the channel configuration.
+func newDeliveryNotification(name string, notification internal.Notification, cfg internal.NotificationDelivery, channelCfg internal.NotificationConfig) *deliveryNotification {
+
+	d := &deliveryNotification{
+		name:          name,
+		notification:  notification,
+		maxRetries:    internal.DEFAULT_NOTIFICATION_MAX_RETRIES,
+		retryInterval: internal.DEFAULT_NOTIFICATION_RETRY_INTERVAL,
+		dedupWindow:   internal.DEFAULT_NOTIFICATION_DEDUP_WINDOW,
+		rateLimit:     cfg.RateLimit,
+		now:           time.Now,
+		sent:          make(map[string]time.Time),
+	}
+
+	switch {
+	case cfg.MaxRetries < 0:
+		d.maxRetries = 0
+	case cfg.MaxRetries > 0:
+		d.maxRetries = cfg.MaxRetries
+	}
+
+	switch {
+	case cfg.DedupWindow < 0:
+		d.dedupWindow = 0
+	case cfg.DedupWindow > 0:
+		d.dedupWindow = time.Duration(cfg.DedupWindow) * time.Minute
+	}
+
+	if cfg.SpoolDir != "" {
+		d.spoolFile = filepath.Join(cfg.SpoolDir, spoolFileName(name, channelCfg))
+	}
+
+	// The retries of the channel replace the retries of the webhook, so that a failed request is not retried by both.
+	// The webhook maxRetries setting is used as the number of retries of the channel.
+	if webhook, ok := notification.(*notifications.Webhook); ok {
+		if channelCfg.Webhook.MaxRetries != 0 {
+			d.maxRetries = max(channelCfg.Webhook.MaxRetries, 0)
+		}
+		webhook.MaxRetries = -1
+	}
+
+	return d
+}
+
+// withDelivery wraps the notification channels of the notification method with the delivery settings of the configuration.
+// Each channel of a list of channels is wrapped separately, so that the rate limit and the spool apply per channel.
+func withDelivery(notification internal.Notification, cfg internal.ConfigurationData) internal.Notification {
+
+	if notification == nil {
+		return nil
+	}
+
+	if composite, ok := notification.(*compositeNotification); ok {
+		for i := range composite.channels {
+			channelCfg := internal.NotificationConfig{}
+			if i < len(cfg.Notification.Channels) {
+				channelCfg = cfg.Notification.Channels[i]
+			}
+			composite.channels[i].notification = newDeliveryNotification(composite.channels[i].name, composite.channels[i].notification, cfg.NotificationDelivery, channelCfg)
+		}
+		return composite
+	}
+
+	return newDeliveryNotification(channelName(cfg.Notification), notification, cfg.NotificationDelivery, cfg.Notification)
+}
+
+// flushUndelivered sends the undelivered notifications stored in the spool files of the notification channels.
+// The spool files are flushed upon startup and periodically by the notification flush job of the server.
+func flushUndelivered(client *http.Client, notification internal.Notification) {
+
+	switch n := notification.(type) {
+	case *deliveryNotification:
+		n.Flush(client)
+	case *compositeNotification:
+		for _, channel := range n.channels {
+			flushUndelivered(client, channel.notification)
+		}
+	case *accountNotification:
+		flushUndelivered(client, n.notification)
+	}
+}
+
+// SetUp sets up the notification method of the channel.
+func (d *deliveryNotification) SetUp() error {
+	return d.notification.SetUp()
+}
+
+// Publish sends the message using the delivery settings of the channel.
+func (d *deliveryNotification) Publish(client *http.Client, data []byte, event string) error {
+
+	if data == nil {
+		return d.notification.Publish(client, data, event)
+	}
+
+	return d.PublishMessage(client, notifications.NewMessage(data, event))
+}
+
+// PublishMessage sends the structured message using the delivery settings of the channel.
+// The events filter of the channel is applied first, so that the filtered out notifications are not de-duplicated and do not count towards the rate limit.
+// The notification is stored in the spool file if it can't be delivered after the retries.
+func (d *deliveryNotification) PublishMessage(client *http.Client, msg notifications.Message) error {
+
+	if filter, ok := d.notification.(eventFilter); ok && !filter.Accepts(msg.Event) {
+		msg.Logger().Debug("event is not eligible for the notification channel. Notification suppressed", "channel", d.name, "event", msg.Event)
+		return nil
+	}
+
+	if !d.accept(msg) {
+		return nil
+	}
+
+	err := d.send(client, msg)
+	if err != nil {
+		if d.spoolFile != "" {
+			spoolErr := d.spool(msg)
+			if spoolErr != nil {
+				slog.Error("unable to store the undelivered notification", "channel", d.name, "error", spoolErr)
+			} else {
+				slog.Warn("notification stored for a later delivery", "channel", d.name, "spool", d.spoolFile)
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// accept returns false if the notification is a duplicate of a notification sent within the de-duplication window, or if the rate limit of the channel is reached.
+func (d *deliveryNotification) accept(msg notifications.Message) bool {
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+
+	dedup := d.dedupWindow > 0 && (msg.Event == internal.EventErrors.String() || msg.Event == internal.EventAlerts.String())
+	key := msg.Event + "\x00" + msg.Account + "\x00" + msg.Body + "\x00" + msg.Error
+
+	if dedup {
+		for k, sent := range d.sent {
+			if now.Sub(sent) >= d.dedupWindow {
+				delete(d.sent, k)
+			}
+		}
+
+		if _, ok := d.sent[key]; ok {
+			slog.Info("duplicate notification suppressed", "channel", d.name, "event", msg.Event, "window", d.dedupWindow)
+			return false
+		}
+	}
+
+	if d.rateLimit > 0 {
+		recent := d.history[:0]
+		for _, sent := range d.history {
+			if now.Sub(sent) < time.Hour {
+				recent = append(recent, sent)
+			}
+		}
+		d.history = recent
+
+		if len(d.history) >= d.rateLimit {
+			slog.Warn("notification rate limit reached. Notification dropped", "channel", d.name, "event", msg.Event, "limit", d.rateLimit)
+			return false
+		}
+		d.history = append(d.history, now)
+	}
+
+	if dedup {
+		d.sent[key] = now
+	}
+
+	return true
+}
+
+// send sends the message with the notification method of the channel and retries failed attempts with an exponential backoff.
+func (d *deliveryNotification) send(client *http.Client, msg notifications.Message) error {
+
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = d.retryInterval
+	bo.MaxElapsedTime = 0
+
+	op := func() error {
+		return publishMessage(client, d.notification, msg)
+	}
+
+	notify := func(err error, wait time.Duration) {
+		slog.Warn("notification failed. Retrying", "channel", d.name, "error", err, "retry_in", wait)
+	}
+
+	return backoff.RetryNotify(op, backoff.WithMaxRetries(bo, uint64(d.maxRetries)), notify)
+}
+
+// Flush sends the notifications stored in the spool file. Notifications that still can't be delivered are stored again
+// in front of the notifications stored during the flush.
+func (d *deliveryNotification) Flush(client *http.Client) {
+
+	if d.spoolFile == "" {
+		return
+	}
+
+	pending, err := d.takeSpool()
+	if err != nil {
+		slog.Error("unable to read the notification spool", "channel", d.name, "spool", d.spoolFile, "error", err)
+		return
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	var remaining []notifications.Message
+	for _, msg := range pending {
+		err := publishMessage(client, d.notification, msg)
+		if err != nil {
+			remaining = append(remaining, msg)
+		}
+	}
+
+	slog.Info("undelivered notifications sent", "channel", d.name, "sent", len(pending)-len(remaining), "remaining", len(remaining))
+
+	if len(remaining) == 0 {
+		return
+	}
+
+	err = d.updateSpool(func(stored []notifications.Message) []notifications.Message {
+		return append(remaining, stored...)
+	})
+	if err != nil {
+		slog.Error("unable to update the notification spool", "channel", d.name, "spool", d.spoolFile, "error", err)
+	}
+}
+
+// takeSpool removes the notifications from the spool file and returns them.
+func (d *deliveryNotification) takeSpool() ([]notifications.Message, error) {
+
+	spoolMu.Lock()
+	defer spoolMu.Unlock()
+
+	pending, err := readSpool(d.spoolFile)
+	if err != nil || len(pending) == 0 {
+		return nil, err
+	}
+
+	return pending, writeSpool(d.spoolFile, nil)
+}
+
+// spool stores the message in the spool file. Attachments are not stored.
+func (d *deliveryNotification) spool(msg notifications.Message) error {
+
+	msg.Attachment = nil
+
+	return d.updateSpool(func(stored []notifications.Message) []notifications.Message {
+		return append(stored, msg)
+	})
+}
+
+// updateSpool replaces the notifications of the spool file with the notifications returned by update. The oldest notifications are dropped if the spool is full.
+func (d *deliveryNotification) updateSpool(update func(stored []notifications.Message) []notifications.Message) error {
+
+	spoolMu.Lock()
+	defer spoolMu.Unlock()
+
+	stored, err := readSpool(d.spoolFile)
+	if err != nil {
+		return err
+	}
+
+	pending := update(stored)
+
+	if len(pending) > internal.DEFAULT_NOTIFICATION_SPOOL_SIZE {
+		slog.Warn("notification spool is full. The oldest notifications are dropped", "channel", d.name, "dropped", len(pending)-internal.DEFAULT_NOTIFICATION_SPOOL_SIZE)
+		pending = pending[len(pending)-internal.DEFAULT_NOTIFICATION_SPOOL_SIZE:]
+	}
+
+	return writeSpool(d.spoolFile, pending)
+}
+
+// readSpool reads the messages of the spool file. One JSON encoded message is stored per line. A missing spool file contains no messages.
+func readSpool(path string) ([]notifications.Message, error) {
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var messages []notifications.Message
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var msg notifications.Message
+		err := json.Unmarshal(scanner.Bytes(), &msg)
+		if err != nil {
+			slog.Warn("invalid notification in the spool file skipped", "spool", path, "error", err)
+			continue
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, scanner.Err()
+}
+
+// writeSpool replaces the content of the spool file with the messages. The spool file is removed if no message is left.
+func writeSpool(path string, messages []notifications.Message) error {
+
+	if len(messages) == 0 {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, msg := range messages {
+		line, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, buf.Bytes(), 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// spoolFileName returns the name of the spool file of the notification channel.
+// The name contains a fingerprint of the channel configuration, so that the notifications are only sent to the channel they were published to.
+func spoolFileName(name string, channelCfg internal.NotificationConfig) string {
+
+	content, err := json.Marshal(channelCfg)
+	if err != nil {
+		return name + ".jsonl"
+	}
+
+	sum := sha256.Sum256(content)
+
+	return name + "-" + hex.EncodeToString(sum[:4]) + ".jsonl"
+}
@@ -0,0 +1,377 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+	"github.com/karl-cardenas-coding/mywhoop/notifications"
+)
+
+type failingPublisher struct {
+	failures int
+	attempts int
+	sent     []notifications.Message
+}
+
+func (f *failingPublisher) SetUp() error {
+	return nil
+}
+
+func (f *failingPublisher) Publish(client *http.Client, data []byte, event string) error {
+	return f.PublishMessage(client, notifications.NewMessage(data, event))
+}
+
+func (f *failingPublisher) PublishMessage(client *http.Client, msg notifications.Message) error {
+	f.attempts++
+	if f.failures != 0 {
+		f.failures--
+		return errors.New("notification service unavailable")
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func TestNewDeliveryNotification(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		cfg         internal.NotificationDelivery
+		maxRetries  int
+		dedupWindow time.Duration
+		spool       bool
+	}{
+		{
+			name:        "defaults",
+			cfg:         internal.NotificationDelivery{},
+			maxRetries:  internal.DEFAULT_NOTIFICATION_MAX_RETRIES,
+			dedupWindow: internal.DEFAULT_NOTIFICATION_DEDUP_WINDOW,
+		},
+		{
+			name:        "disabled",
+			cfg:         internal.NotificationDelivery{MaxRetries: -1, DedupWindow: -1},
+			maxRetries:  0,
+			dedupWindow: 0,
+		},
+		{
+			name:        "custom",
+			cfg:         internal.NotificationDelivery{MaxRetries: 5, DedupWindow: 10, SpoolDir: "spool"},
+			maxRetries:  5,
+			dedupWindow: 10 * time.Minute,
+			spool:       true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := newDeliveryNotification("ntfy", &failingPublisher{}, tc.cfg, internal.NotificationConfig{Method: "ntfy"})
+
+			if d.maxRetries != tc.maxRetries {
+				t.Errorf("expected %d retries, got %d", tc.maxRetries, d.maxRetries)
+			}
+
+			if d.dedupWindow != tc.dedupWindow {
+				t.Errorf("expected a de-duplication window of %s, got %s", tc.dedupWindow, d.dedupWindow)
+			}
+
+			if (d.spoolFile != "") != tc.spool {
+				t.Errorf("unexpected spool file %q", d.spoolFile)
+			}
+		})
+	}
+}
+
+func TestDeliveryNotificationRetry(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		failures   int
+		maxRetries int
+		attempts   int
+		wantErr    bool
+	}{
+		{name: "first attempt", failures: 0, maxRetries: 3, attempts: 1},
+		{name: "retried", failures: 2, maxRetries: 3, attempts: 3},
+		{name: "retries exhausted", failures: 10, maxRetries: 3, attempts: 4, wantErr: true},
+		{name: "retries disabled", failures: 1, maxRetries: -1, attempts: 1, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			publisher := &failingPublisher{failures: tc.failures}
+			d := newDeliveryNotification("test", publisher, internal.NotificationDelivery{MaxRetries: tc.maxRetries}, internal.NotificationConfig{})
+			d.retryInterval = time.Millisecond
+
+			err := d.Publish(&http.Client{}, []byte("Daily data collection complete."), internal.EventSuccess.String())
+			if (err != nil) != tc.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if publisher.attempts != tc.attempts {
+				t.Errorf("expected %d attempts, got %d", tc.attempts, publisher.attempts)
+			}
+		})
+	}
+}
+
+func TestDeliveryNotificationDedup(t *testing.T) {
+
+	publisher := &failingPublisher{}
+	d := newDeliveryNotification("test", publisher, internal.NotificationDelivery{DedupWindow: 30}, internal.NotificationConfig{})
+
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	publish := func(body, event string) {
+		t.Helper()
+		err := d.PublishMessage(&http.Client{}, notifications.Message{Event: event, Body: body})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	publish("Error running the server job.", internal.EventErrors.String())
+	publish("Error running the server job.", internal.EventErrors.String())
+	publish("Unable to refresh the token.", internal.EventErrors.String())
+	publish("Daily data collection complete.", internal.EventSuccess.String())
+	publish("Daily data collection complete.", internal.EventSuccess.String())
+
+	if len(publisher.sent) != 4 {
+		t.Fatalf("expected 4 notifications, got %d", len(publisher.sent))
+	}
+
+	now = now.Add(31 * time.Minute)
+	publish("Error running the server job.", internal.EventErrors.String())
+
+	if len(publisher.sent) != 5 {
+		t.Errorf("expected the error to be sent again after the window, got %d notifications", len(publisher.sent))
+	}
+}
+
+func TestDeliveryNotificationRateLimit(t *testing.T) {
+
+	publisher := &failingPublisher{}
+	d := newDeliveryNotification("test", publisher, internal.NotificationDelivery{DedupWindow: -1, RateLimit: 2}, internal.NotificationConfig{})
+
+	now := time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		err := d.Publish(&http.Client{}, []byte("Error running the server job."), internal.EventErrors.String())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(publisher.sent) != 2 {
+		t.Fatalf("expected 2 notifications, got %d", len(publisher.sent))
+	}
+
+	now = now.Add(time.Hour)
+	err := d.Publish(&http.Client{}, []byte("Error running the server job."), internal.EventErrors.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(publisher.sent) != 3 {
+		t.Errorf("expected the notification to be sent after an hour, got %d notifications", len(publisher.sent))
+	}
+}
+
+func TestDeliveryNotificationEventsFilter(t *testing.T) {
+
+	stdout := notifications.NewStdout()
+	stdout.Events = internal.EventErrors.String()
+	d := newDeliveryNotification("stdout", stdout, internal.NotificationDelivery{RateLimit: 1}, internal.NotificationConfig{})
+
+	// The filtered out notifications do not count towards the rate limit
+	for i := 0; i < 3; i++ {
+		err := d.Publish(&http.Client{}, []byte("Daily data collection complete."), internal.EventSuccess.String())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(d.history) != 0 {
+		t.Fatalf("expected the filtered out notifications to be ignored by the rate limit, got %d", len(d.history))
+	}
+
+	err := d.Publish(&http.Client{}, []byte("Error running the server job."), internal.EventErrors.String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(d.history) != 1 {
+		t.Errorf("expected the error notification to be sent, got %d", len(d.history))
+	}
+}
+
+func TestDeliveryNotificationWebhookRetries(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		maxRetries int
+		expected   int
+	}{
+		{name: "default", maxRetries: 0, expected: internal.DEFAULT_NOTIFICATION_MAX_RETRIES},
+		{name: "custom", maxRetries: 5, expected: 5},
+		{name: "disabled", maxRetries: -1, expected: 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			webhook := notifications.NewWebhook()
+			d := newDeliveryNotification("webhook", webhook, internal.NotificationDelivery{}, internal.NotificationConfig{Method: "webhook", Webhook: notifications.Webhook{MaxRetries: tc.maxRetries}})
+
+			if webhook.MaxRetries != -1 {
+				t.Errorf("expected the webhook retries to be disabled, got %d", webhook.MaxRetries)
+			}
+
+			if d.maxRetries != tc.expected {
+				t.Errorf("expected %d channel retries, got %d", tc.expected, d.maxRetries)
+			}
+		})
+	}
+}
+
+func TestDeliveryNotificationFlushOrder(t *testing.T) {
+
+	cfg := internal.NotificationDelivery{MaxRetries: -1, SpoolDir: t.TempDir()}
+	publisher := &failingPublisher{failures: 1}
+	d := newDeliveryNotification("ntfy", publisher, cfg, internal.NotificationConfig{Method: "ntfy"})
+
+	for _, body := range []string{"first", "second"} {
+		err := d.spool(notifications.Message{Event: internal.EventErrors.String(), Body: body})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// The notification that still can't be delivered is stored again
+	d.Flush(&http.Client{})
+
+	spooled, err := readSpool(d.spoolFile)
+	if err != nil {
+		t.Fatalf("unable to read the spool: %v", err)
+	}
+
+	if len(spooled) != 1 || spooled[0].Body != "first" || len(publisher.sent) != 1 || publisher.sent[0].Body != "second" {
+		t.Errorf("expected the first notification to stay in the spool, got %+v and sent %+v", spooled, publisher.sent)
+	}
+}
+
+func TestDeliveryNotificationSpool(t *testing.T) {
+
+	dir := t.TempDir()
+	cfg := internal.NotificationDelivery{MaxRetries: -1, SpoolDir: dir}
+	channelCfg := internal.NotificationConfig{Method: "ntfy"}
+
+	publisher := &failingPublisher{failures: 1}
+	d := newDeliveryNotification("ntfy", publisher, cfg, channelCfg)
+
+	msg := notifications.Message{
+		Event:      internal.EventSuccess.String(),
+		Body:       "Daily data collection complete.",
+		Attachment: &notifications.Attachment{Name: "user.json", Data: []byte("{}")},
+	}
+
+	err := d.PublishMessage(&http.Client{}, msg)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	spooled, err := readSpool(d.spoolFile)
+	if err != nil {
+		t.Fatalf("unable to read the spool: %v", err)
+	}
+
+	if len(spooled) != 1 || spooled[0].Body != msg.Body || spooled[0].Attachment != nil {
+		t.Fatalf("unexpected spooled notifications: %+v", spooled)
+	}
+
+	// A new delivery layer with the same configuration, such as after a restart, sends the spooled notification.
+	restarted := &failingPublisher{}
+	flushUndelivered(&http.Client{}, newAccountNotification("alice", newDeliveryNotification("ntfy", restarted, cfg, channelCfg)))
+
+	if len(restarted.sent) != 1 || restarted.sent[0].Body != msg.Body {
+		t.Errorf("expected the spooled notification to be sent, got %+v", restarted.sent)
+	}
+
+	_, err = os.Stat(d.spoolFile)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected the spool file to be removed, got %v", err)
+	}
+}
+
+func TestDeliveryNotificationSpoolSize(t *testing.T) {
+
+	d := newDeliveryNotification("ntfy", &failingPublisher{}, internal.NotificationDelivery{SpoolDir: t.TempDir()}, internal.NotificationConfig{})
+
+	for i := 0; i < internal.DEFAULT_NOTIFICATION_SPOOL_SIZE+5; i++ {
+		err := d.spool(notifications.Message{Event: internal.EventErrors.String(), Body: time.Duration(i).String()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	spooled, err := readSpool(d.spoolFile)
+	if err != nil {
+		t.Fatalf("unable to read the spool: %v", err)
+	}
+
+	if len(spooled) != internal.DEFAULT_NOTIFICATION_SPOOL_SIZE {
+		t.Fatalf("expected %d notifications, got %d", internal.DEFAULT_NOTIFICATION_SPOOL_SIZE, len(spooled))
+	}
+
+	if spooled[0].Body != time.Duration(5).String() {
+		t.Errorf("expected the oldest notifications to be dropped, got %q", spooled[0].Body)
+	}
+}
+
+func TestWithDelivery(t *testing.T) {
+
+	composite := newCompositeNotification([]notificationChannel{
+		{name: "0-ntfy", notification: &mockNotification{}},
+		{name: "1-slack", notification: &mockNotification{}},
+	})
+
+	cfg := internal.ConfigurationData{
+		Notification: internal.NotificationConfig{
+			Channels: []internal.NotificationConfig{{Method: "ntfy"}, {Method: "slack"}},
+		},
+		NotificationDelivery: internal.NotificationDelivery{SpoolDir: "spool"},
+	}
+
+	withDelivery(composite, cfg)
+
+	files := map[string]bool{}
+	for _, channel := range composite.channels {
+		d, ok := channel.notification.(*deliveryNotification)
+		if !ok {
+			t.Fatalf("expected channel %s to be wrapped, got %T", channel.name, channel.notification)
+		}
+		files[d.spoolFile] = true
+		if filepath.Dir(d.spoolFile) != "spool" {
+			t.Errorf("unexpected spool file %q", d.spoolFile)
+		}
+	}
+
+	if len(files) != 2 {
+		t.Errorf("expected a spool file per channel, got %v", files)
+	}
+
+	if withDelivery(nil, cfg) != nil {
+		t.Error("expected no notification method")
+	}
+
+	if _, ok := withDelivery(&mockNotification{}, cfg).(*deliveryNotification); !ok {
+		t.Error("expected the notification method to be wrapped")
+	}
+}
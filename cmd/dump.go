@@ -83,6 +83,8 @@ func dump(ctx context.Context) error {
 		return err
 	}
 
+	notificationMethod = withDelivery(notificationMethod, cfg)
+
 	data, err := user.GetUserProfileData(ctx, client, internal.DEFAULT_WHOOP_API_USER_DATA_URL, token.AccessToken, ua)
 	if err != nil {
 		internal.LogError(err)
@@ -217,7 +219,8 @@ func dump(ctx context.Context) error {
 
 	slog.Info("All Whoop data downloaded and exported successfully")
 	if notificationMethod != nil {
-		err = notificationMethod.Publish(client, []byte("Successfully downloaded all Whoop data."), internal.EventSuccess.String())
+		msg := withExportedData(newJobMessage(ctx, "", internal.EventSuccess, "Successfully downloaded all Whoop data.", nil), exporterMethod, finalDataRaw, output)
+		err = publishMessage(client, notificationMethod, msg)
 		if err != nil {
 			slog.Error("unable to send notification", "error", err)
 		}
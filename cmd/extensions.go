@@ -5,6 +5,7 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 
@@ -14,17 +15,59 @@ import (
 )
 
 // determineExtension determines the notification extension to use and returns the appropriate notification.
+// If the notification block is a list of channels, a notification publishing to every channel is returned.
 func determineNotificationExtension(cfg internal.ConfigurationData) (internal.Notification, error) {
 
+	if len(cfg.Notification.Channels) == 0 {
+		return newNotificationChannel(cfg.Notification)
+	}
+
+	var channels []notificationChannel
+	for i, channelCfg := range cfg.Notification.Channels {
+		notification, err := newNotificationChannel(channelCfg)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set up the notification channel %d (%s): %w", i+1, channelName(channelCfg), err)
+		}
+
+		channels = append(channels, notificationChannel{
+			name:         fmt.Sprintf("%d-%s", i+1, channelName(channelCfg)),
+			notification: notification,
+		})
+	}
+
+	slog.Info("Notification channels configured", "channels", len(channels))
+
+	return newCompositeNotification(channels), nil
+}
+
+// newNotificationChannel returns the notification of a single notification channel.
+// The events filter of the channel takes precedence over the events filter of the notification method.
+func newNotificationChannel(cfg internal.NotificationConfig) (internal.Notification, error) {
+
 	var notificationMethod internal.Notification
 
-	switch cfg.Notification.Method {
+	switch cfg.Method {
 	case "ntfy":
 		ntfy := notifications.NewNtfy()
-		ntfy.ServerEndpoint = cfg.Notification.Ntfy.ServerEndpoint
-		ntfy.SubscriptionID = cfg.Notification.Ntfy.SubscriptionID
-		ntfy.UserName = cfg.Notification.Ntfy.UserName
-		ntfy.Events = cfg.Notification.Ntfy.Events
+		ntfy.ServerEndpoint = cfg.Ntfy.ServerEndpoint
+		ntfy.SubscriptionID = cfg.Ntfy.SubscriptionID
+		ntfy.UserName = cfg.Ntfy.UserName
+		ntfy.Events = channelEvents(cfg, cfg.Ntfy.Events)
+		ntfy.Title = cfg.Ntfy.Title
+		ntfy.Click = cfg.Ntfy.Click
+		ntfy.Markdown = cfg.Ntfy.Markdown
+		ntfy.AttachExport = cfg.Ntfy.AttachExport
+		ntfy.CACertFile = cfg.Ntfy.CACertFile
+		ntfy.InsecureSkipVerify = cfg.Ntfy.InsecureSkipVerify
+		if cfg.Ntfy.Priorities.Errors != "" {
+			ntfy.Priorities.Errors = cfg.Ntfy.Priorities.Errors
+		}
+		if cfg.Ntfy.Priorities.Success != "" {
+			ntfy.Priorities.Success = cfg.Ntfy.Priorities.Success
+		}
+		if cfg.Ntfy.Priorities.Alerts != "" {
+			ntfy.Priorities.Alerts = cfg.Ntfy.Priorities.Alerts
+		}
 		err := ntfy.SetUp()
 		if err != nil {
 			return notificationMethod, err
@@ -32,9 +75,147 @@ func determineNotificationExtension(cfg internal.ConfigurationData) (internal.No
 		slog.Info("Ntfy notification method configured")
 		notificationMethod = ntfy
 
+	case "slack":
+		slack := notifications.NewSlack()
+		url, err := internal.ResolveSecretReference(cfg.Slack.WebhookURL)
+		if err != nil {
+			return notificationMethod, fmt.Errorf("unable to resolve the Slack webhook URL: %w", err)
+		}
+		slack.WebhookURL = url
+		slack.Events = channelEvents(cfg, cfg.Slack.Events)
+		err = slack.SetUp()
+		if err != nil {
+			return notificationMethod, err
+		}
+		slog.Info("Slack notification method configured")
+		notificationMethod = slack
+
+	case "discord":
+		discord := notifications.NewDiscord()
+		url, err := internal.ResolveSecretReference(cfg.Discord.WebhookURL)
+		if err != nil {
+			return notificationMethod, fmt.Errorf("unable to resolve the Discord webhook URL: %w", err)
+		}
+		discord.WebhookURL = url
+		discord.Events = channelEvents(cfg, cfg.Discord.Events)
+		if cfg.Discord.UserName != "" {
+			discord.UserName = cfg.Discord.UserName
+		}
+		err = discord.SetUp()
+		if err != nil {
+			return notificationMethod, err
+		}
+		slog.Info("Discord notification method configured")
+		notificationMethod = discord
+
+	case "email":
+		email := notifications.NewEmail()
+		email.Host = cfg.Email.Host
+		email.Port = cfg.Email.Port
+		email.TLS = cfg.Email.TLS
+		email.From = cfg.Email.From
+		email.To = cfg.Email.To
+		email.HTMLTemplate = cfg.Email.HTMLTemplate
+		email.TextTemplate = cfg.Email.TextTemplate
+		email.Events = channelEvents(cfg, cfg.Email.Events)
+		if cfg.Email.Subject != "" {
+			email.Subject = cfg.Email.Subject
+		}
+		err := email.SetUp()
+		if err != nil {
+			return notificationMethod, err
+		}
+		slog.Info("Email notification method configured")
+		notificationMethod = email
+
+	case "webhook":
+		webhook := notifications.NewWebhook()
+		url, err := internal.ResolveSecretReference(cfg.Webhook.URL)
+		if err != nil {
+			return notificationMethod, fmt.Errorf("unable to resolve the webhook URL: %w", err)
+		}
+		webhook.URL = url
+		headers, err := resolveWebhookHeaders(cfg.Webhook.Headers)
+		if err != nil {
+			return notificationMethod, err
+		}
+		webhook.Headers = headers
+		webhook.Body = cfg.Webhook.Body
+		webhook.Events = channelEvents(cfg, cfg.Webhook.Events)
+		if cfg.Webhook.Method != "" {
+			webhook.Method = cfg.Webhook.Method
+		}
+		if cfg.Webhook.MaxRetries != 0 {
+			webhook.MaxRetries = cfg.Webhook.MaxRetries
+		}
+		if cfg.Webhook.SignatureHeader != "" {
+			webhook.SignatureHeader = cfg.Webhook.SignatureHeader
+		}
+		err = webhook.SetUp()
+		if err != nil {
+			return notificationMethod, err
+		}
+		slog.Info("Webhook notification method configured")
+		notificationMethod = webhook
+
+	case "gotify":
+		gotify := notifications.NewGotify()
+		gotify.ServerEndpoint = cfg.Gotify.ServerEndpoint
+		gotify.Events = channelEvents(cfg, cfg.Gotify.Events)
+		err := gotify.SetUp()
+		if err != nil {
+			return notificationMethod, err
+		}
+		slog.Info("Gotify notification method configured")
+		notificationMethod = gotify
+
+	case "pushover":
+		pushover := notifications.NewPushover()
+		pushover.Device = cfg.Pushover.Device
+		pushover.Events = channelEvents(cfg, cfg.Pushover.Events)
+		err := pushover.SetUp()
+		if err != nil {
+			return notificationMethod, err
+		}
+		slog.Info("Pushover notification method configured")
+		notificationMethod = pushover
+
+	case "telegram":
+		telegram := notifications.NewTelegram()
+		telegram.ChatID = cfg.Telegram.ChatID
+		telegram.Events = channelEvents(cfg, cfg.Telegram.Events)
+		err := telegram.SetUp()
+		if err != nil {
+			return notificationMethod, err
+		}
+		slog.Info("Telegram notification method configured")
+		notificationMethod = telegram
+
+	case "matrix":
+		matrix := notifications.NewMatrix()
+		matrix.HomeServer = cfg.Matrix.HomeServer
+		matrix.RoomID = cfg.Matrix.RoomID
+		matrix.Events = channelEvents(cfg, cfg.Matrix.Events)
+		err := matrix.SetUp()
+		if err != nil {
+			return notificationMethod, err
+		}
+		slog.Info("Matrix notification method configured")
+		notificationMethod = matrix
+
 	default:
-		slog.Info("no notification method specified. Defaulting to stdout.")
+		if cfg.Method == "" {
+			slog.Info("no notification method specified. Defaulting to stdout.")
+		}
 		std := notifications.NewStdout()
+		if cfg.Stdout.Format != "" {
+			std.Format = cfg.Stdout.Format
+		}
+		std.Events = channelEvents(cfg, cfg.Stdout.Events)
+		err := std.SetUp()
+		if err != nil {
+			return notificationMethod, err
+		}
 		notificationMethod = std
 	}
 
@@ -42,6 +223,98 @@ func determineNotificationExtension(cfg internal.ConfigurationData) (internal.No
 
 }
 
+// channelEvents returns the events filter of the notification channel. The events filter of the notification method is used if the channel does not override it.
+func channelEvents(cfg internal.NotificationConfig, methodEvents string) string {
+
+	if cfg.Events != "" {
+		return cfg.Events
+	}
+
+	return methodEvents
+}
+
+// channelName returns the name of the notification channel used in logs and errors.
+func channelName(cfg internal.NotificationConfig) string {
+
+	if cfg.Method == "" {
+		return "stdout"
+	}
+
+	return cfg.Method
+}
+
+// resolveWebhookHeaders resolves the env: and file: secret references of the webhook header values.
+func resolveWebhookHeaders(headers map[string]string) (map[string]string, error) {
+
+	resolved := make(map[string]string, len(headers))
+	for key, value := range headers {
+		v, err := internal.ResolveSecretReference(value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve the webhook header %s: %w", key, err)
+		}
+		resolved[key] = v
+	}
+
+	return resolved, nil
+}
+
+// accountNotification labels the notifications of an account with the account name.
+type accountNotification struct {
+	// account is the name of the account.
+	account string
+	// notification is the notification method of the account.
+	notification internal.Notification
+}
+
+// newAccountNotification returns a notification method that prefixes every message with the account name.
+func newAccountNotification(account string, notification internal.Notification) *accountNotification {
+	return &accountNotification{
+		account:      account,
+		notification: notification,
+	}
+}
+
+// SetUp sets up the underlying notification method.
+func (a *accountNotification) SetUp() error {
+	return a.notification.SetUp()
+}
+
+// Publish sends the message labelled with the account name using the underlying notification method.
+func (a *accountNotification) Publish(client *http.Client, data []byte, event string) error {
+
+	if data == nil {
+		return a.notification.Publish(client, data, event)
+	}
+
+	if publisher, ok := a.notification.(notifications.MessagePublisher); ok {
+		msg := notifications.NewMessage(data, event)
+		msg.Account = a.account
+		return publisher.PublishMessage(client, msg)
+	}
+
+	labelled := append([]byte("["+a.account+"] "), data...)
+	return a.notification.Publish(client, labelled, event)
+}
+
+// PublishMessage sends the structured message labelled with the account name using the underlying notification method.
+func (a *accountNotification) PublishMessage(client *http.Client, msg notifications.Message) error {
+
+	msg.Account = a.account
+
+	return publishMessage(client, a.notification, msg)
+}
+
+// publishMessage sends the structured message using the notification method.
+// Notification methods without support for structured messages receive the plain text representation of the message.
+func publishMessage(client *http.Client, notification internal.Notification, msg notifications.Message) error {
+
+	if publisher, ok := notification.(notifications.MessagePublisher); ok {
+		return publisher.PublishMessage(client, msg)
+	}
+
+	return notification.Publish(client, []byte(msg.String()), msg.Event)
+}
+
 // determineExporterExtension determines the export extension to use and returns the appropriate export.
 // The parameter isServerMode is used to determine if the exporter is being used in server mode. Use this flag to set server mode defaults.
 func determineExporterExtension(cfg internal.ConfigurationData, client *http.Client, cFlags cliFlags) (internal.Export, error) {
@@ -6,6 +6,7 @@ package cmd
 import (
 	"net/http"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/karl-cardenas-coding/mywhoop/export"
@@ -163,6 +164,7 @@ func TestDetermineNotificationExtension(t *testing.T) {
 		setEnvCreds   bool
 		setToken      bool
 		setPassword   bool
+		env           map[string]string
 		expectedType  interface{}
 	}{
 		{
@@ -197,6 +199,213 @@ func TestDetermineNotificationExtension(t *testing.T) {
 			setToken:      true,
 			expectedType:  &notifications.Ntfy{},
 		},
+		{
+			name: "slack",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "slack",
+					Slack: notifications.Slack{
+						Events: "all",
+					},
+				},
+			},
+			expextedError: false,
+			env:           map[string]string{"NOTIFICATION_SLACK_WEBHOOK_URL": "https://hooks.slack.com/services/T000/B000/XXXX"},
+			expectedType:  &notifications.Slack{},
+		},
+		{
+			name: "slack with webhook URL reference",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "slack",
+					Slack: notifications.Slack{
+						WebhookURL: "env:MYWHOOP_TEST_SLACK_URL",
+					},
+				},
+			},
+			expextedError: false,
+			env: map[string]string{
+				"MYWHOOP_TEST_SLACK_URL":         "https://hooks.slack.com/services/T000/B000/YYYY",
+				"NOTIFICATION_SLACK_WEBHOOK_URL": "https://hooks.slack.com/services/T000/B000/XXXX",
+			},
+			expectedType: &notifications.Slack{},
+		},
+		{
+			name: "slack with missing webhook URL reference",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "slack",
+					Slack: notifications.Slack{
+						WebhookURL: "env:MYWHOOP_TEST_MISSING_SLACK_URL",
+					},
+				},
+			},
+			expextedError: true,
+			expectedType:  &notifications.Slack{},
+		},
+		{
+			name: "slack without webhook URL",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "slack",
+				},
+			},
+			expextedError: true,
+			expectedType:  &notifications.Slack{},
+		},
+		{
+			name: "discord",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "discord",
+					Discord: notifications.Discord{
+						UserName: "Whoop Bot",
+						Events:   "errors",
+					},
+				},
+			},
+			expextedError: false,
+			env:           map[string]string{"NOTIFICATION_DISCORD_WEBHOOK_URL": "https://discord.com/api/webhooks/1234/abcd"},
+			expectedType:  &notifications.Discord{},
+		},
+		{
+			name: "discord with webhook URL reference",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "discord",
+					Discord: notifications.Discord{
+						WebhookURL: "env:MYWHOOP_TEST_DISCORD_URL",
+					},
+				},
+			},
+			expextedError: false,
+			env:           map[string]string{"MYWHOOP_TEST_DISCORD_URL": "https://discord.com/api/webhooks/5678/efgh"},
+			expectedType:  &notifications.Discord{},
+		},
+		{
+			name: "discord without webhook URL",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "discord",
+				},
+			},
+			expextedError: true,
+			expectedType:  &notifications.Discord{},
+		},
+		{
+			name: "email",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "email",
+					Email: notifications.Email{
+						Host: "smtp.example.com",
+						From: "mywhoop@example.com",
+						To:   []string{"alice@example.com"},
+					},
+				},
+			},
+			expextedError: false,
+			expectedType:  &notifications.Email{},
+		},
+		{
+			name: "email without recipients",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "email",
+					Email: notifications.Email{
+						Host: "smtp.example.com",
+						From: "mywhoop@example.com",
+					},
+				},
+			},
+			expextedError: true,
+			expectedType:  &notifications.Email{},
+		},
+		{
+			name: "webhook",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "webhook",
+					Webhook: notifications.Webhook{
+						URL:     "env:MYWHOOP_TEST_WEBHOOK_URL",
+						Headers: map[string]string{"Authorization": "env:MYWHOOP_TEST_WEBHOOK_TOKEN"},
+					},
+				},
+			},
+			expextedError: false,
+			env: map[string]string{
+				"MYWHOOP_TEST_WEBHOOK_URL":   "http://localhost:8123/api/webhook/mywhoop",
+				"MYWHOOP_TEST_WEBHOOK_TOKEN": "Bearer token",
+			},
+			expectedType: &notifications.Webhook{},
+		},
+		{
+			name: "webhook with a missing header secret",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "webhook",
+					Webhook: notifications.Webhook{
+						URL:     "http://localhost:8123/api/webhook/mywhoop",
+						Headers: map[string]string{"Authorization": "env:MYWHOOP_TEST_MISSING_TOKEN"},
+					},
+				},
+			},
+			expextedError: true,
+			expectedType:  &notifications.Webhook{},
+		},
+		{
+			name: "gotify",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "gotify",
+					Gotify: notifications.Gotify{
+						ServerEndpoint: "http://localhost:8080",
+					},
+				},
+			},
+			expextedError: false,
+			env:           map[string]string{"NOTIFICATION_GOTIFY_TOKEN": "AbCdEf"},
+			expectedType:  &notifications.Gotify{},
+		},
+		{
+			name: "pushover without credentials",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "pushover",
+				},
+			},
+			expextedError: true,
+			expectedType:  &notifications.Pushover{},
+		},
+		{
+			name: "telegram",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "telegram",
+					Telegram: notifications.Telegram{
+						ChatID: "-1001234567890",
+					},
+				},
+			},
+			expextedError: false,
+			env:           map[string]string{"NOTIFICATION_TELEGRAM_BOT_TOKEN": "123456:ABC-DEF"},
+			expectedType:  &notifications.Telegram{},
+		},
+		{
+			name: "matrix",
+			cfg: internal.ConfigurationData{
+				Notification: internal.NotificationConfig{
+					Method: "matrix",
+					Matrix: notifications.Matrix{
+						HomeServer: "https://matrix.org",
+						RoomID:     "!abcdefg:matrix.org",
+					},
+				},
+			},
+			expextedError: false,
+			env:           map[string]string{"NOTIFICATION_MATRIX_ACCESS_TOKEN": "syt_token"},
+			expectedType:  &notifications.Matrix{},
+		},
 		{
 			name: "no notification method specified",
 			cfg: internal.ConfigurationData{
@@ -212,6 +421,9 @@ func TestDetermineNotificationExtension(t *testing.T) {
 			if test.setEnvCreds {
 				setEnvCreds(test.setPassword, test.setToken, false)
 			}
+			for key, value := range test.env {
+				t.Setenv(key, value)
+			}
 			notificationMethod, err := determineNotificationExtension(test.cfg)
 			if (err != nil) != test.expextedError {
 				t.Errorf("expected error: %v, got: %v", test.expextedError, err)
@@ -230,6 +442,46 @@ func TestDetermineNotificationExtension(t *testing.T) {
 						t.Errorf("expected type: %T, got: %T", test.expectedType, notificationMethod)
 					}
 				}
+
+				if discord, ok := notificationMethod.(*notifications.Discord); ok {
+					if _, ok := test.expectedType.(*notifications.Discord); !ok {
+						t.Errorf("expected type: %T, got: %T", test.expectedType, notificationMethod)
+					}
+					if discord.UserName != test.cfg.Notification.Discord.UserName && test.cfg.Notification.Discord.UserName != "" {
+						t.Errorf("expected user name: %s, got: %s", test.cfg.Notification.Discord.UserName, discord.UserName)
+					}
+					if url, ok := test.env["MYWHOOP_TEST_DISCORD_URL"]; ok && discord.WebhookURL != url {
+						t.Errorf("expected the webhook URL reference to be resolved, got: %s", discord.WebhookURL)
+					}
+				}
+
+				if webhook, ok := notificationMethod.(*notifications.Webhook); ok {
+					if _, ok := test.expectedType.(*notifications.Webhook); !ok {
+						t.Errorf("expected type: %T, got: %T", test.expectedType, notificationMethod)
+					}
+					if webhook.URL != test.env["MYWHOOP_TEST_WEBHOOK_URL"] || webhook.Headers["Authorization"] != test.env["MYWHOOP_TEST_WEBHOOK_TOKEN"] {
+						t.Errorf("expected the secret references to be resolved, got: %s %v", webhook.URL, webhook.Headers)
+					}
+				}
+
+				if _, ok := notificationMethod.(*notifications.Email); ok {
+					if _, ok := test.expectedType.(*notifications.Email); !ok {
+						t.Errorf("expected type: %T, got: %T", test.expectedType, notificationMethod)
+					}
+				}
+
+				if reflect.TypeOf(notificationMethod) != reflect.TypeOf(test.expectedType) && notificationMethod != nil && !test.expextedError {
+					t.Errorf("expected type: %T, got: %T", test.expectedType, notificationMethod)
+				}
+
+				if slack, ok := notificationMethod.(*notifications.Slack); ok {
+					if _, ok := test.expectedType.(*notifications.Slack); !ok {
+						t.Errorf("expected type: %T, got: %T", test.expectedType, notificationMethod)
+					}
+					if url, ok := test.env["MYWHOOP_TEST_SLACK_URL"]; ok && slack.WebhookURL != url {
+						t.Errorf("expected the webhook URL reference to take precedence over the environment variable, got: %s", slack.WebhookURL)
+					}
+				}
 			}
 
 		})
@@ -345,3 +597,99 @@ func TestGetFileType(t *testing.T) {
 		})
 	}
 }
+
+// mockNotification is a notification method that records the published messages.
+type mockNotification struct {
+	messages []string
+	events   []string
+}
+
+func (m *mockNotification) SetUp() error {
+	return nil
+}
+
+func (m *mockNotification) Publish(client *http.Client, data []byte, event string) error {
+	m.messages = append(m.messages, string(data))
+	m.events = append(m.events, event)
+	return nil
+}
+
+func TestAccountNotification(t *testing.T) {
+
+	mock := &mockNotification{}
+	n := newAccountNotification("alice", mock)
+
+	err := n.SetUp()
+	if err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	err = n.Publish(nil, []byte("Daily data collection complete."), internal.EventSuccess.String())
+	if err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	expected := "[alice] Daily data collection complete."
+	if len(mock.messages) != 1 || mock.messages[0] != expected {
+		t.Errorf("expected: %s, got: %v", expected, mock.messages)
+	}
+
+	if mock.events[0] != internal.EventSuccess.String() {
+		t.Errorf("expected: %s, got: %s", internal.EventSuccess.String(), mock.events[0])
+	}
+}
+
+// mockMessagePublisher is a notification method that records the published structured messages.
+type mockMessagePublisher struct {
+	mockNotification
+	structured []notifications.Message
+}
+
+func (m *mockMessagePublisher) PublishMessage(client *http.Client, msg notifications.Message) error {
+	m.structured = append(m.structured, msg)
+	return nil
+}
+
+func TestPublishMessage(t *testing.T) {
+
+	msg := notifications.Message{
+		Event: internal.EventErrors.String(),
+		Job:   "mywhoop_data_collection_job",
+		Body:  "Failed to export data.",
+		Error: "access denied",
+	}
+
+	t.Run("structured", func(t *testing.T) {
+		mock := &mockMessagePublisher{}
+		err := publishMessage(nil, newAccountNotification("alice", mock), msg)
+		if err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+
+		if len(mock.structured) != 1 || len(mock.messages) != 0 {
+			t.Fatalf("expected one structured message, got: %d structured and %d plain messages", len(mock.structured), len(mock.messages))
+		}
+
+		got := mock.structured[0]
+		if got.Account != "alice" || got.Job != msg.Job || got.Error != msg.Error {
+			t.Errorf("expected the account, job, and error context to be preserved, got: %+v", got)
+		}
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		mock := &mockNotification{}
+		err := publishMessage(nil, newAccountNotification("alice", mock), msg)
+		if err != nil {
+			t.Errorf("expected no error, got: %v", err)
+		}
+
+		expected := "[alice] Failed to export data. Additional context below: \n access denied"
+		if len(mock.messages) != 1 || mock.messages[0] != expected {
+			t.Errorf("expected: %q, got: %q", expected, mock.messages)
+		}
+
+		if mock.events[0] != internal.EventErrors.String() {
+			t.Errorf("expected: %s, got: %s", internal.EventErrors.String(), mock.events[0])
+		}
+	})
+}
@@ -0,0 +1,255 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the past runs of the server jobs.",
+	Long:  "List the past runs of the server jobs recorded in the run history file. The most recent runs are displayed first. Use the flags to filter the runs by job, account, status, or start time.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return history(cmd.OutOrStdout(), historyOpts)
+	},
+}
+
+// historyOptions are the flags of the history command.
+type historyOptions struct {
+	// file is the path of the history file. The file of the configuration is used if empty.
+	file string
+	// job filters the runs by job name.
+	job string
+	// account filters the runs by account name.
+	account string
+	// status filters the runs by status.
+	status string
+	// since filters the runs started after a duration ago, such as 72h, or after a date, such as 2024-06-01.
+	since string
+	// limit is the maximum number of runs displayed.
+	limit int
+	// format is the output format. Supported formats are table and json.
+	format string
+}
+
+var (
+	// historyOpts contains the flags of the history command.
+	historyOpts historyOptions
+)
+
+func init() {
+	historyCmd.PersistentFlags().StringVar(&historyOpts.file, "file", "", "The path of the history file. Default is the history file of the configuration.")
+	historyCmd.PersistentFlags().StringVar(&historyOpts.job, "job", "", "Only display the runs of the job, such as mywhoop_data_collection_job.")
+	historyCmd.PersistentFlags().StringVar(&historyOpts.account, "account", "", "Only display the runs of the account.")
+	historyCmd.PersistentFlags().StringVar(&historyOpts.status, "status", "", "Only display the runs with the status. Allowed values are success, failed, and skipped.")
+	historyCmd.PersistentFlags().StringVar(&historyOpts.since, "since", "", "Only display the runs started after a duration ago, such as 72h, or after a date, such as 2024-06-01.")
+	historyCmd.PersistentFlags().IntVarP(&historyOpts.limit, "limit", "n", 20, "The maximum number of runs to display. Use 0 to display every run.")
+	historyCmd.PersistentFlags().StringVarP(&historyOpts.format, "format", "f", "table", "The output format. Supported formats are table and json.")
+	rootCmd.AddCommand(historyCmd)
+}
+
+// history displays the runs of the history file matching the filter options.
+func history(out io.Writer, opts historyOptions) error {
+
+	filePath := opts.file
+	if filePath == "" {
+		err := InitLogger(&Configuration)
+		if err != nil {
+			return err
+		}
+		filePath = internal.HistoryFile(Configuration.History)
+	}
+
+	filter, err := historyFilter(opts, time.Now())
+	if err != nil {
+		return err
+	}
+
+	records, err := internal.ReadHistory(filePath)
+	if err != nil {
+		return err
+	}
+
+	runs := internal.FilterRuns(records, filter)
+
+	switch strings.ToLower(opts.format) {
+	case "table", "":
+		return writeHistoryTable(out, runs)
+	case "json":
+		if runs == nil {
+			runs = []internal.RunRecord{}
+		}
+		content, err := json.MarshalIndent(runs, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(append(content, '\n'))
+		return err
+	default:
+		return fmt.Errorf("unsupported output format %s. Supported formats are table and json", opts.format)
+	}
+}
+
+// historyFilter returns the history filter of the options. The since option accepts a duration before now, a date, or an RFC 3339 time.
+func historyFilter(opts historyOptions, now time.Time) (internal.HistoryFilter, error) {
+
+	filter := internal.HistoryFilter{
+		Job:     opts.job,
+		Account: opts.account,
+		Status:  opts.status,
+		Limit:   opts.limit,
+	}
+
+	switch opts.status {
+	case "", internal.RunStatusSuccess, internal.RunStatusFailed, internal.RunStatusSkipped:
+	default:
+		return filter, fmt.Errorf("invalid status %s. Allowed values are success, failed, and skipped", opts.status)
+	}
+
+	if opts.limit < 0 {
+		return filter, fmt.Errorf("invalid limit %d. The limit must be 0 or greater", opts.limit)
+	}
+
+	if opts.since == "" {
+		return filter, nil
+	}
+
+	duration, err := time.ParseDuration(opts.since)
+	if err == nil {
+		filter.Since = now.Add(-duration)
+		return filter, nil
+	}
+
+	since, err := time.ParseInLocation(time.DateOnly, opts.since, time.Local)
+	if err == nil {
+		filter.Since = since
+		return filter, nil
+	}
+
+	since, err = time.Parse(time.RFC3339, opts.since)
+	if err != nil {
+		return filter, fmt.Errorf("invalid since value %s. Use a duration, such as 72h, a date, such as 2024-06-01, or an RFC 3339 time", opts.since)
+	}
+	filter.Since = since
+
+	return filter, nil
+}
+
+// writeHistoryTable writes the runs as a table.
+func writeHistoryTable(out io.Writer, runs []internal.RunRecord) error {
+
+	if len(runs) == 0 {
+		_, err := fmt.Fprintln(out, "No runs found.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "START\tJOB\tACCOUNT\tSTATUS\tDURATION\tATTEMPTS\tRECORDS\tEXPORT\tNOTIFICATIONS\tERROR")
+
+	for _, run := range runs {
+		account := run.Account
+		if account == "" {
+			account = "-"
+		}
+
+		status := run.Status
+		if run.CatchUp {
+			status += " (catch-up)"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			run.Start.Local().Format(time.DateTime),
+			run.Job,
+			account,
+			status,
+			run.Duration().Round(time.Millisecond),
+			run.Attempts,
+			formatRecordCounts(run.Records),
+			formatRunExport(run.Export),
+			formatRunNotifications(run.Notifications),
+			formatRunError(run.Error),
+		)
+	}
+
+	return w.Flush()
+}
+
+// formatRecordCounts returns the record counts sorted by collection, such as cycle=1 recovery=1.
+func formatRecordCounts(records map[string]int) string {
+
+	if len(records) == 0 {
+		return "-"
+	}
+
+	collections := make([]string, 0, len(records))
+	for collection := range records {
+		collections = append(collections, collection)
+	}
+	sort.Strings(collections)
+
+	counts := make([]string, 0, len(collections))
+	for _, collection := range collections {
+		counts = append(counts, fmt.Sprintf("%s=%d", collection, records[collection]))
+	}
+
+	return strings.Join(counts, " ")
+}
+
+// formatRunExport returns the location and the size of the exported data.
+func formatRunExport(export *internal.RunExport) string {
+
+	if export == nil {
+		return "-"
+	}
+
+	location := strings.TrimSuffix(export.Destination, "/") + "/" + export.Key
+	if export.Key == "" {
+		location = export.Method
+	}
+
+	return fmt.Sprintf("%s (%d bytes)", location, export.Bytes)
+}
+
+// formatRunNotifications returns the number of delivered notifications out of the notifications sent.
+func formatRunNotifications(results []internal.RunNotification) string {
+
+	if len(results) == 0 {
+		return "-"
+	}
+
+	delivered := 0
+	for _, result := range results {
+		if result.Delivered {
+			delivered++
+		}
+	}
+
+	return fmt.Sprintf("%d/%d delivered", delivered, len(results))
+}
+
+// formatRunError returns the first line of the error, shortened to fit in the table.
+func formatRunError(err string) string {
+
+	if err == "" {
+		return "-"
+	}
+
+	line, _, _ := strings.Cut(err, "\n")
+	runes := []rune(line)
+	if len(runes) > 80 {
+		return string(runes[:79]) + "…"
+	}
+
+	return line
+}
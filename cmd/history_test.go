@@ -0,0 +1,135 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+)
+
+func TestHistoryFilter(t *testing.T) {
+
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		opts    historyOptions
+		since   time.Time
+		wantErr bool
+	}{
+		{name: "no since", opts: historyOptions{limit: 20}},
+		{name: "duration", opts: historyOptions{since: "72h"}, since: now.Add(-72 * time.Hour)},
+		{name: "date", opts: historyOptions{since: "2024-06-01"}, since: time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)},
+		{name: "RFC 3339", opts: historyOptions{since: "2024-06-01T13:00:00Z"}, since: time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)},
+		{name: "invalid since", opts: historyOptions{since: "last week"}, wantErr: true},
+		{name: "invalid status", opts: historyOptions{status: "running"}, wantErr: true},
+		{name: "invalid limit", opts: historyOptions{limit: -1}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := historyFilter(tc.opts, now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tc.wantErr && !filter.Since.Equal(tc.since) {
+				t.Errorf("expected since %s, got %s", tc.since, filter.Since)
+			}
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+	h := internal.NewHistory(internal.HistoryConfig{File: filePath})
+
+	start := time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)
+	runs := []internal.RunRecord{
+		{
+			ID:       "1",
+			Job:      "mywhoop_data_collection_job",
+			Status:   internal.RunStatusSuccess,
+			Start:    start,
+			End:      start.Add(5 * time.Second),
+			Attempts: 1,
+			CatchUp:  true,
+			Records:  map[string]int{"sleep": 1, "cycle": 1},
+			Export:   &internal.RunExport{Method: "s3", Destination: "s3://my-bucket", Key: "user.json", Bytes: 1024},
+		},
+		{
+			ID:       "2",
+			Job:      "mywhoop_token_refresh_job",
+			Account:  "alice",
+			Status:   internal.RunStatusFailed,
+			Start:    start.Add(time.Hour),
+			End:      start.Add(time.Hour + time.Minute),
+			Attempts: 3,
+			Error:    "unable to refresh the token\nstatus 401",
+		},
+	}
+	for _, run := range runs {
+		err := h.Append(run)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	err := history(&out, historyOptions{file: filePath, format: "table"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 runs, got %q", out.String())
+	}
+
+	if !strings.Contains(lines[1], "mywhoop_token_refresh_job") || !strings.Contains(lines[1], "unable to refresh the token") || strings.Contains(lines[1], "401") {
+		t.Errorf("unexpected first run %q", lines[1])
+	}
+
+	if !strings.Contains(lines[2], "success (catch-up)") || !strings.Contains(lines[2], "cycle=1 sleep=1") || !strings.Contains(lines[2], "s3://my-bucket/user.json (1024 bytes)") {
+		t.Errorf("unexpected second run %q", lines[2])
+	}
+
+	out.Reset()
+	err = history(&out, historyOptions{file: filePath, format: "json", job: "mywhoop_data_collection_job"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded []internal.RunRecord
+	err = json.Unmarshal(out.Bytes(), &decoded)
+	if err != nil {
+		t.Fatalf("unable to decode the output: %v", err)
+	}
+
+	if len(decoded) != 1 || decoded[0].ID != "1" {
+		t.Errorf("unexpected runs %+v", decoded)
+	}
+
+	out.Reset()
+	err = history(&out, historyOptions{file: filePath, status: internal.RunStatusSkipped})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.TrimSpace(out.String()) != "No runs found." {
+		t.Errorf("unexpected output %q", out.String())
+	}
+
+	err = history(&out, historyOptions{file: filePath, format: "xml"})
+	if err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	gocron "github.com/go-co-op/gocron/v2"
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+)
+
+// serverElector is a leader elector that renews its lease in the background.
+type serverElector interface {
+	gocron.Elector
+	// Run renews the lease until the context is cancelled and releases the lease.
+	Run(ctx context.Context)
+}
+
+// startLeaderElection starts the leader election of the configuration. The scheduler options make the scheduler run the jobs only on the leader.
+// The returned function stops the election and releases the lease. No option is returned if the leader election is disabled.
+func startLeaderElection(ctx context.Context, cfg internal.LeaderElection, client *http.Client) ([]gocron.SchedulerOption, func(), error) {
+
+	if !cfg.Enabled {
+		return nil, func() {}, nil
+	}
+
+	elector, err := internal.NewS3LeaseElector(ctx, cfg, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slog.Info("Leader election enabled. Only the leader runs the server jobs", "identity", elector.Identity(), "bucket", cfg.Bucket)
+
+	stop := runElector(ctx, elector)
+
+	return []gocron.SchedulerOption{gocron.WithDistributedElector(elector)}, stop, nil
+}
+
+// runElector renews the lease of the elector in the background. The returned function stops the renewals and waits for the lease to be released.
+func runElector(ctx context.Context, elector serverElector) func() {
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		elector.Run(ctx)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
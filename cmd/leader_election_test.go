@@ -0,0 +1,54 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+)
+
+// mockElector records the lifecycle of the lease renewals.
+type mockElector struct {
+	started  chan struct{}
+	released bool
+}
+
+func (m *mockElector) IsLeader(ctx context.Context) error { return nil }
+
+func (m *mockElector) Run(ctx context.Context) {
+	close(m.started)
+	<-ctx.Done()
+	m.released = true
+}
+
+func TestRunElector(t *testing.T) {
+
+	elector := &mockElector{started: make(chan struct{})}
+
+	stop := runElector(context.Background(), elector)
+	<-elector.started
+
+	// Stopping the election waits for the lease to be released
+	stop()
+	if !elector.released {
+		t.Error("expected the lease to be released")
+	}
+}
+
+func TestStartLeaderElectionDisabled(t *testing.T) {
+
+	opts, stop, err := startLeaderElection(context.Background(), internal.LeaderElection{}, &http.Client{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(opts) != 0 {
+		t.Errorf("expected no scheduler option, got %d", len(opts))
+	}
+
+	stop()
+}
@@ -0,0 +1,258 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	gocron "github.com/go-co-op/gocron/v2"
+	"github.com/karl-cardenas-coding/mywhoop/export"
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+	"github.com/karl-cardenas-coding/mywhoop/notifications"
+)
+
+var (
+	// logOutputMu guards the log output.
+	logOutputMu sync.Mutex
+	// logOutput is the log file of the default logger. Nil if the logs are not written to a file.
+	logOutput io.Closer
+)
+
+// logHandler applies the level of the component of a record and adds the log attributes of the context to the record.
+// The records without a component use the base level.
+type logHandler struct {
+	// handler is the handler formatting the records.
+	handler slog.Handler
+	// level is the base level.
+	level slog.Level
+	// levels contains the levels of the components.
+	levels map[string]slog.Level
+	// component is the component of the logger the handler belongs to.
+	component string
+}
+
+// newLogHandler returns the handler of the log configuration writing to the writer. The component levels are ignored if ignoreComponents is true.
+func newLogHandler(cfg internal.LogConfig, w io.Writer, verbosity string, ignoreComponents bool) *logHandler {
+
+	h := &logHandler{
+		level:  logLevel(verbosity),
+		levels: map[string]slog.Level{},
+	}
+
+	if !ignoreComponents {
+		components := map[string]string{
+			internal.LogComponentAPI:       cfg.Levels.API,
+			internal.LogComponentExport:    cfg.Levels.Export,
+			internal.LogComponentNotify:    cfg.Levels.Notify,
+			internal.LogComponentScheduler: cfg.Levels.Scheduler,
+		}
+		for component, level := range components {
+			if level != "" {
+				h.levels[component] = logLevel(level)
+			}
+		}
+	}
+
+	// The handler level is the lowest level, as the component of a record is only known once the record is handled
+	minLevel := h.level
+	for _, level := range h.levels {
+		minLevel = min(minLevel, level)
+	}
+
+	switch cfg.Format {
+	case "json":
+		h.handler = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: minLevel})
+	case "logfmt":
+		h.handler = slog.NewTextHandler(w, &slog.HandlerOptions{Level: minLevel, ReplaceAttr: utcTimeFormat})
+	default:
+		h.handler = slog.NewTextHandler(w, &slog.HandlerOptions{Level: minLevel, ReplaceAttr: changeTimeFormat})
+	}
+
+	return h
+}
+
+// componentLevel returns the level of the component.
+func (h *logHandler) componentLevel(component string) slog.Level {
+
+	level, ok := h.levels[component]
+	if !ok {
+		return h.level
+	}
+
+	return level
+}
+
+// Enabled reports whether the handler handles records of the level.
+// A handler without a component uses the base level, so a component at DEBUG does not enable the debug records of the other loggers.
+// Records carrying the component as an inline attribute are therefore only handled at or above the base level.
+func (h *logHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.componentLevel(h.component)
+}
+
+// Handle drops the records below the level of their component and adds the log attributes of the context missing from the record.
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+
+	component := h.component
+	keys := map[string]bool{}
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == internal.LOG_COMPONENT_KEY && component == "" {
+			component = a.Value.String()
+		}
+		keys[a.Key] = true
+		return true
+	})
+
+	if r.Level < h.componentLevel(component) {
+		return nil
+	}
+
+	var attrs []slog.Attr
+	for _, attr := range internal.LogAttrs(ctx) {
+		if !keys[attr.Key] {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	if len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler with the attributes. The component attribute sets the component of the handler.
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+
+	c := *h
+	for _, attr := range attrs {
+		if attr.Key == internal.LOG_COMPONENT_KEY {
+			c.component = attr.Value.String()
+		}
+	}
+	c.handler = h.handler.WithAttrs(attrs)
+
+	return &c
+}
+
+// WithGroup returns a handler with the group.
+func (h *logHandler) WithGroup(name string) slog.Handler {
+
+	c := *h
+	c.handler = h.handler.WithGroup(name)
+
+	return &c
+}
+
+// logLevel converts the string log level to the slog level. Default is INFO.
+func logLevel(verbosity string) slog.Level {
+
+	switch strings.ToUpper(verbosity) {
+	case "DEBUG":
+		return slog.LevelDebug
+	case "WARN":
+		return slog.LevelWarn
+	case "ERROR":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// utcTimeFormat formats the timestamp of the logger in the RFC 3339 format in UTC.
+func utcTimeFormat(groups []string, a slog.Attr) slog.Attr {
+
+	if a.Key == slog.TimeKey && len(groups) == 0 {
+		a.Value = slog.StringValue(a.Value.Time().UTC().Format(time.RFC3339Nano))
+	}
+	return a
+}
+
+// logWriter returns the writer of the log destination. The closer of the log file is returned with the file destination.
+func logWriter(cfg internal.LogConfig) (io.Writer, io.Closer, error) {
+
+	switch cfg.Destination {
+	case "stderr":
+		return os.Stderr, nil, nil
+	case "file":
+		f, err := internal.NewRotatingFile(cfg.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, f, nil
+	default:
+		return os.Stdout, nil, nil
+	}
+}
+
+// setLogger sets the default logger using the log configuration. The --debug flag takes precedence over the debug field and the component levels.
+// The log file of the previous logger is closed once the new logger is set.
+func setLogger(cfg internal.ConfigurationData) error {
+
+	verbosity := logVerbosity(cfg)
+
+	w, closer, err := logWriter(cfg.Log)
+	if err != nil {
+		slog.Error("unable to open the log destination", "destination", cfg.Log.Destination, "error", err)
+		return err
+	}
+
+	slog.SetDefault(slog.New(newLogHandler(cfg.Log, w, verbosity, VerbosityLevel != "")))
+
+	// The exporters and notification methods can't import the internal package, so their default loggers are labelled here
+	export.SetDefaultLogger(internal.ComponentLogger(internal.LogComponentExport))
+	notifications.SetDefaultLogger(internal.ComponentLogger(internal.LogComponentNotify))
+
+	logOutputMu.Lock()
+	previous := logOutput
+	logOutput = closer
+	logOutputMu.Unlock()
+
+	if previous != nil {
+		err = previous.Close()
+		if err != nil {
+			slog.Error("unable to close the previous log file", "error", err)
+		}
+	}
+
+	return nil
+}
+
+// logVerbosity returns the log level of the --debug flag, or the log level of the debug field if the flag is not set.
+func logVerbosity(cfg internal.ConfigurationData) string {
+
+	verbosity := strings.ToUpper(VerbosityLevel)
+	if verbosity == "" {
+		verbosity = cfg.Debug
+	}
+
+	return verbosity
+}
+
+// schedulerLogger writes the logs of the scheduler with the default logger labelled with the scheduler component.
+type schedulerLogger struct{}
+
+var _ gocron.Logger = schedulerLogger{}
+
+func (schedulerLogger) Debug(msg string, args ...any) {
+	internal.ComponentLogger(internal.LogComponentScheduler).Debug(msg, args...)
+}
+
+func (schedulerLogger) Error(msg string, args ...any) {
+	internal.ComponentLogger(internal.LogComponentScheduler).Error(msg, args...)
+}
+
+func (schedulerLogger) Info(msg string, args ...any) {
+	internal.ComponentLogger(internal.LogComponentScheduler).Info(msg, args...)
+}
+
+func (schedulerLogger) Warn(msg string, args ...any) {
+	internal.ComponentLogger(internal.LogComponentScheduler).Warn(msg, args...)
+}
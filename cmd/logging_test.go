@@ -0,0 +1,197 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/karl-cardenas-coding/mywhoop/export"
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+)
+
+func TestLogLevel(t *testing.T) {
+
+	tests := []struct {
+		level    string
+		expected slog.Level
+	}{
+		{level: "DEBUG", expected: slog.LevelDebug},
+		{level: "warn", expected: slog.LevelWarn},
+		{level: "ERROR", expected: slog.LevelError},
+		{level: "", expected: slog.LevelInfo},
+		{level: "TRACE", expected: slog.LevelInfo},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.level, func(t *testing.T) {
+			if got := logLevel(tc.level); got != tc.expected {
+				t.Errorf("Expected %v, got: %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestLogHandlerComponentLevels(t *testing.T) {
+
+	var buf bytes.Buffer
+	cfg := internal.LogConfig{
+		Format: "json",
+		Levels: internal.LogLevels{API: "DEBUG", Scheduler: "ERROR"},
+	}
+	log := slog.New(newLogHandler(cfg, &buf, "INFO", false))
+
+	log.Debug("server debug")
+	log.With(internal.LOG_COMPONENT_KEY, internal.LogComponentAPI).Debug("api debug")
+	log.Debug("export debug", internal.LOG_COMPONENT_KEY, internal.LogComponentExport)
+	log.With(internal.LOG_COMPONENT_KEY, internal.LogComponentScheduler).Warn("scheduler warning")
+	log.With(internal.LOG_COMPONENT_KEY, internal.LogComponentScheduler).Error("scheduler error")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "api debug") || !strings.Contains(lines[1], "scheduler error") {
+		t.Errorf("Expected the api debug and the scheduler error records but got: %v", lines)
+	}
+
+	handler := newLogHandler(cfg, &buf, "INFO", false)
+	if handler.Enabled(context.Background(), slog.LevelDebug) {
+		t.Errorf("Expected the debug level to be disabled for records without a component")
+	}
+	if !handler.WithAttrs([]slog.Attr{slog.String(internal.LOG_COMPONENT_KEY, internal.LogComponentAPI)}).Enabled(context.Background(), slog.LevelDebug) {
+		t.Errorf("Expected the debug level to be enabled for the api component")
+	}
+
+	// The --debug flag takes precedence over the component levels
+	buf.Reset()
+	log = slog.New(newLogHandler(cfg, &buf, "DEBUG", true))
+	log.With(internal.LOG_COMPONENT_KEY, internal.LogComponentScheduler).Debug("scheduler debug")
+	if !strings.Contains(buf.String(), "scheduler debug") {
+		t.Errorf("Expected the scheduler debug record but got: %s", buf.String())
+	}
+}
+
+func TestLogHandlerContextAttrs(t *testing.T) {
+
+	var buf bytes.Buffer
+	log := slog.New(newLogHandler(internal.LogConfig{Format: "json"}, &buf, "INFO", false))
+
+	ctx := internal.WithLogAttrs(context.Background(), slog.String("job", "hourly"), slog.String("run_id", "1234"))
+	ctx = internal.WithLogAttrs(ctx, slog.String("collection", "sleep"))
+
+	log.InfoContext(ctx, "Requesting sleep collection from Whoop API", "job", "hourly_alice")
+
+	var record map[string]any
+	err := json.Unmarshal(buf.Bytes(), &record)
+	if err != nil {
+		t.Fatalf("Expected a JSON record but got: %s", buf.String())
+	}
+
+	// The attributes of the record take precedence over the attributes of the context
+	if record["job"] != "hourly_alice" || record["run_id"] != "1234" || record["collection"] != "sleep" {
+		t.Errorf("Expected the run attributes but got: %v", record)
+	}
+
+	if strings.Count(buf.String(), `"job"`) != 1 {
+		t.Errorf("Expected a single job attribute but got: %s", buf.String())
+	}
+}
+
+func TestLogHandlerFormats(t *testing.T) {
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{format: "json", expected: `"msg":"Data collection complete"`},
+		{format: "logfmt", expected: `msg="Data collection complete"`},
+		{format: "", expected: `msg="Data collection complete"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.New(newLogHandler(internal.LogConfig{Format: tc.format}, &buf, "INFO", false)).Info("Data collection complete")
+			if !strings.Contains(buf.String(), tc.expected) {
+				t.Errorf("Expected %s, got: %s", tc.expected, buf.String())
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	slog.New(newLogHandler(internal.LogConfig{Format: "logfmt"}, &buf, "INFO", false)).Info("Data collection complete")
+	if !strings.Contains(buf.String(), "Z level=INFO") {
+		t.Errorf("Expected a UTC timestamp but got: %s", buf.String())
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+
+	previous := slog.Default()
+	defer slog.SetDefault(previous)
+
+	path := filepath.Join(t.TempDir(), "mywhoop.log")
+	err := setLogger(internal.ConfigurationData{Log: internal.LogConfig{Destination: "file", File: internal.LogFile{Path: path}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := logOutput.(*internal.RotatingFile); !ok {
+		t.Fatalf("Expected the log file to be open but got: %v", logOutput)
+	}
+
+	// The log file is closed once the logger is replaced
+	err = setLogger(internal.ConfigurationData{})
+	if err != nil || logOutput != nil {
+		t.Errorf("Expected the log file to be closed but got: %v, %v", logOutput, err)
+	}
+}
+
+func TestRunLogger(t *testing.T) {
+
+	previous := slog.Default()
+	defer slog.SetDefault(previous)
+
+	var buf bytes.Buffer
+	cfg := internal.LogConfig{Format: "json", Levels: internal.LogLevels{Notify: "ERROR"}}
+	slog.SetDefault(slog.New(newLogHandler(cfg, &buf, "INFO", false)))
+
+	ctx := internal.WithLogAttrs(context.Background(), slog.String("job", "hourly"), slog.String("account", "alice"), slog.String("run_id", "1234"))
+
+	// The records of the exporter carry the log attributes of the run
+	exporter := export.NewFileExport(t.TempDir(), "json", "user", "", false)
+	exporter.SetLogger(internal.RunLogger(ctx, internal.LogComponentExport))
+	err := exporter.Export([]byte("{}"))
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	var record map[string]any
+	err = json.Unmarshal(buf.Bytes(), &record)
+	if err != nil {
+		t.Fatalf("Expected a JSON record but got: %s", buf.String())
+	}
+
+	if record["job"] != "hourly" || record["account"] != "alice" || record["run_id"] != "1234" || record[internal.LOG_COMPONENT_KEY] != internal.LogComponentExport {
+		t.Errorf("Expected the run attributes and the export component but got: %v", record)
+	}
+
+	// The level of the component applies to the records of the run
+	buf.Reset()
+	internal.RunLogger(ctx, internal.LogComponentNotify).Info("notification sent")
+	if buf.Len() != 0 {
+		t.Errorf("Expected the notify record to be dropped but got: %s", buf.String())
+	}
+
+	// The logger follows a reload of the log configuration
+	var reloaded bytes.Buffer
+	slog.SetDefault(slog.New(newLogHandler(internal.LogConfig{Format: "json"}, &reloaded, "INFO", false)))
+	internal.RunLogger(ctx, internal.LogComponentNotify).Info("notification sent")
+	if !strings.Contains(reloaded.String(), `"run_id":"1234"`) {
+		t.Errorf("Expected the notify record in the reloaded log output but got: %s", reloaded.String())
+	}
+}
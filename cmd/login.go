@@ -63,6 +63,10 @@ type PageData struct {
 
 // login authenticates with Whoop API and gets an access token
 func login() error {
+
+	// The login command creates the token of an account before the account is added to the configuration file
+	allowUnconfiguredAccount = true
+
 	err := InitLogger(&Configuration)
 	if err != nil {
 		return err
@@ -70,11 +74,9 @@ func login() error {
 
 	cliCfg := Configuration
 
-	id := os.Getenv("WHOOP_CLIENT_ID")
-	secret := os.Getenv("WHOOP_CLIENT_SECRET")
-
-	if id == "" || secret == "" {
-		return errors.New("the required env variables WHOOP_CLIENT_ID and WHOOP_CLIENT_SECRET are not set")
+	clientCredentials, err := internal.NewClientCredentials(cliCfg.Credentials)
+	if err != nil {
+		return err
 	}
 
 	staticAssets, err := getStaticAssets(GlobalStaticAssets, "web/static")
@@ -83,8 +85,8 @@ func login() error {
 	}
 
 	config := &oauth2.Config{
-		ClientID:     id,
-		ClientSecret: secret,
+		ClientID:     clientCredentials.ID,
+		ClientSecret: clientCredentials.Secret,
 		RedirectURL:  "http://localhost:" + port + redirectURL,
 		Scopes: []string{
 			"offline",
@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/karl-cardenas-coding/mywhoop/internal"
@@ -17,6 +16,12 @@ import (
 )
 
 var (
+	// AccountName is the name of the account to use from the accounts list of the configuration file.
+	AccountName string
+	// allowUnconfiguredAccount allows an account missing from the accounts list. Only the login command creates the token of a new account.
+	allowUnconfiguredAccount bool
+	// keepSecretReferences keeps the client credential references unresolved. Only the config show command displays the references as is.
+	keepSecretReferences bool
 	// Credentials file containing a Whoop authentication token. Can also be set through ENV variable or configuration file.
 	CredentialsFile string
 	// cfgFile is the myWhoop configuration file
@@ -68,6 +73,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "MyWhoop config file - default is $HOME/.mywhoop.yaml")
 	rootCmd.PersistentFlags().StringVarP(&VerbosityLevel, "debug", "d", "", "Enable debug output. Use the values DEBUG, INFO, WARN, ERROR, Default is INFO.")
 	rootCmd.PersistentFlags().StringVar(&CredentialsFile, "credentials", "", "File path to the Whoop credentials file that contains a valid authentication token.")
+	rootCmd.PersistentFlags().StringVar(&AccountName, "account", "", "The name of the account to use from the accounts list of the configuration file.")
 
 	UserAgent = fmt.Sprintf("mywhoop/%s", VersionString)
 
@@ -76,24 +82,105 @@ func init() {
 // InitLogger initializes the logger
 func InitLogger(cfg *internal.ConfigurationData) error {
 
-	envConfigVars, err := internal.ExtractEnvVariables()
+	config, err := loadConfiguration()
+	if err != nil {
+		return err
+	}
+	*cfg = config
+
+	err = setLogger(*cfg)
 	if err != nil {
 		return err
 	}
 
+	slog.Debug("Environment Configuration",
+		slog.Group("Verbosity", slog.String("Level", logVerbosity(*cfg))),
+		slog.Group("Config", slog.String("File", cfgFile)),
+	)
+
+	slog.Debug("Configuration", "Config", internal.RedactSecrets(*cfg))
+
+	return nil
+
+}
+
+// loadConfiguration merges the configuration file, the environment variables, and the CLI flags.
+// The order of precedence is CLI flags, environment variables, configuration file, and default values.
+func loadConfiguration() (internal.ConfigurationData, error) {
+
+	var cfg internal.ConfigurationData
+
+	envConfigVars, err := internal.ExtractEnvVariables()
+	if err != nil {
+		return cfg, err
+	}
+
 	ok, configFilePath := internal.CheckConfigFile(cfgFile)
 	if ok {
 		slog.Info("config file specified", "config", configFilePath)
 		config, err := internal.GenerateConfigStruct(configFilePath)
 		if err != nil {
-			slog.Error("unable to generate configuration struct", "error", err)
-			os.Exit(1)
+			slog.Error("unable to generate configuration struct. Use the config validate command to review the configuration file", "config", configFilePath, "error", err)
+			return cfg, err
 		}
-		Configuration = config
+		cfg = config
 	}
 
-	// Merge the configuration data from the environment variables
-	cfg.Credentials = envConfigVars.Credentials
+	// Merge the configuration data from the MYWHOOP_<SECTION>_<FIELD> environment variables
+	overrides, err := internal.ApplyEnvOverrides(&cfg)
+	if err != nil {
+		slog.Error("unable to apply the environment variable overrides", "error", err)
+		return cfg, err
+	}
+
+	if len(overrides) > 0 {
+		slog.Info("configuration values provided through environment variables", "variables", overrides)
+	}
+
+	// Without a configuration file, the configuration is only validated once an export method is provided through the environment variables.
+	if len(overrides) > 0 && (ok || cfg.Export.Method != "") {
+		err = internal.ValidateConfiguration(cfg)
+		if err != nil {
+			slog.Error("invalid configuration after applying the environment variable overrides", "error", err)
+			return cfg, err
+		}
+	}
+
+	// Merge the configuration data from the WHOOP_* environment variables
+	if envConfigVars.Credentials.CredentialsFile != "" {
+		cfg.Credentials.CredentialsFile = envConfigVars.Credentials.CredentialsFile
+	}
+
+	if envConfigVars.Credentials.ClientID != "" {
+		cfg.Credentials.ClientID = envConfigVars.Credentials.ClientID
+	}
+
+	if envConfigVars.Credentials.ClientSecret != "" {
+		cfg.Credentials.ClientSecret = envConfigVars.Credentials.ClientSecret
+	}
+
+	// Resolve the client credential references once, so that the jobs keep using the same credentials until the configuration is loaded again
+	if !keepSecretReferences {
+		err = internal.ResolveCredentialReferences(&cfg)
+		if err != nil {
+			slog.Error("unable to resolve the Whoop client credentials", "error", err)
+			return cfg, err
+		}
+	}
+
+	if AccountName != "" {
+		account, ok := cfg.FindAccount(AccountName)
+		if !ok && !allowUnconfiguredAccount {
+			slog.Error("account not found in the configuration file", "account", AccountName)
+			return cfg, fmt.Errorf("the account %q is not configured in the accounts list of the configuration file", AccountName)
+		}
+
+		if !ok {
+			slog.Warn("account not found in the configuration file. The token is stored in the credentials file of the account", "account", AccountName, "credentials", internal.AccountCredentialsFile(AccountName))
+		}
+		slog.Info("Account selected", "account", account.Name)
+		cfg = cfg.ForAccount(account)
+	}
 
 	// Prioritize CLI flags
 
@@ -102,24 +189,12 @@ func InitLogger(cfg *internal.ConfigurationData) error {
 		cfg.Credentials.CredentialsFile = CredentialsFile
 	}
 
-	outputLvl := strings.ToUpper(VerbosityLevel)
-	if outputLvl == "" {
-		outputLvl = cfg.Debug
-	}
-	slog.SetDefault(logger(outputLvl))
-	slog.Debug("Environment Configuration",
-		slog.Group("Verbosity", slog.String("Level", outputLvl)),
-		slog.Group("Config", slog.String("File", cfgFile)),
-	)
-
 	if cfg.Credentials.CredentialsFile == "" {
 		slog.Info("No credentials file provided. Using default credentials file")
 		cfg.Credentials.CredentialsFile = internal.DEFAULT_CREDENTIALS_FILE
 	}
-	slog.Debug("Configuration", "Config", cfg)
-
-	return nil
 
+	return cfg, nil
 }
 
 // changeTimeFormat changes the timestamp of the logger.
@@ -132,42 +207,6 @@ func changeTimeFormat(groups []string, a slog.Attr) slog.Attr {
 
 }
 
-// Logger returns a new logger
-func logger(verbosity string) *slog.Logger {
-
-	var opts *slog.HandlerOptions
-
-	switch verbosity {
-	case "DEBUG":
-		opts = &slog.HandlerOptions{
-			Level:       slog.LevelDebug,
-			ReplaceAttr: changeTimeFormat,
-		}
-	case "INFO":
-		opts = &slog.HandlerOptions{
-			Level:       slog.LevelInfo,
-			ReplaceAttr: changeTimeFormat,
-		}
-	case "WARN":
-		opts = &slog.HandlerOptions{
-			Level:       slog.LevelWarn,
-			ReplaceAttr: changeTimeFormat,
-		}
-	case "ERROR":
-		opts = &slog.HandlerOptions{
-			Level:       slog.LevelError,
-			ReplaceAttr: changeTimeFormat,
-		}
-	default:
-		opts = &slog.HandlerOptions{
-			Level:       slog.LevelInfo,
-			ReplaceAttr: changeTimeFormat,
-		}
-	}
-
-	return slog.New(slog.NewTextHandler(os.Stdout, opts))
-}
-
 // cliFlags is a struct that holds the CLI flags
 type cliFlags struct {
 	dataLocation string
@@ -7,7 +7,11 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
 )
 
 func TestLogger(t *testing.T) {
@@ -37,7 +41,7 @@ func TestLogger(t *testing.T) {
 
 	for index, test := range tests {
 		test.id = index + 1
-		results := logger(test.logLevel)
+		results := slog.New(newLogHandler(internal.LogConfig{}, os.Stdout, test.logLevel, true))
 		ctx := context.Background()
 		var lv slog.Level
 		if test.opts.Level != nil {
@@ -72,3 +76,67 @@ func TestChangeTimeFormat(t *testing.T) {
 	}
 
 }
+
+func TestLoadConfigurationAccount(t *testing.T) {
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(configFile, []byte("export:\n  method: file\n  fileExport:\n    filePath: data/\naccounts:\n  - name: alice\n"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	previousCfgFile, previousAccount := cfgFile, AccountName
+	cfgFile = configFile
+	t.Cleanup(func() {
+		cfgFile, AccountName, allowUnconfiguredAccount = previousCfgFile, previousAccount, false
+	})
+
+	AccountName = "alice"
+	cfg, err := loadConfiguration()
+	if err != nil || cfg.Credentials.CredentialsFile != "token_alice.json" {
+		t.Errorf("Expected the alice account but got %s, %v", cfg.Credentials.CredentialsFile, err)
+	}
+
+	// A misspelled account is rejected instead of using a stray credentials file
+	AccountName = "alcie"
+	_, err = loadConfiguration()
+	if err == nil {
+		t.Error("Expected an error for an unconfigured account")
+	}
+
+	// The login command creates the token of a new account
+	allowUnconfiguredAccount = true
+	cfg, err = loadConfiguration()
+	if err != nil || cfg.Credentials.CredentialsFile != "token_alcie.json" {
+		t.Errorf("Expected the credentials file of the new account but got %s, %v", cfg.Credentials.CredentialsFile, err)
+	}
+}
+
+func TestLoadConfigurationCredentialReferences(t *testing.T) {
+
+	t.Setenv("MYWHOOP_TEST_CLIENT_SECRET", "BBBBBBBBBBBBBBBBBBBBB")
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(configFile, []byte("export:\n  method: file\n  fileExport:\n    filePath: data/\ncredentials:\n  clientID: AAAAAAAAAAAAAAAAAAA\n  clientSecret: env:MYWHOOP_TEST_CLIENT_SECRET\n"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	previousCfgFile := cfgFile
+	cfgFile = configFile
+	t.Cleanup(func() {
+		cfgFile, keepSecretReferences = previousCfgFile, false
+	})
+
+	cfg, err := loadConfiguration()
+	if err != nil || cfg.Credentials.ClientSecret != "BBBBBBBBBBBBBBBBBBBBB" {
+		t.Errorf("Expected the resolved client secret but got %s, %v", cfg.Credentials.ClientSecret, err)
+	}
+
+	// The config show command displays the references as is
+	keepSecretReferences = true
+	cfg, err = loadConfiguration()
+	if err != nil || cfg.Credentials.ClientSecret != "env:MYWHOOP_TEST_CLIENT_SECRET" {
+		t.Errorf("Expected the client secret reference but got %s, %v", cfg.Credentials.ClientSecret, err)
+	}
+}
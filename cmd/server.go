@@ -10,15 +10,18 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"path"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
 
 	gocron "github.com/go-co-op/gocron/v2"
-	"github.com/google/uuid"
 	"github.com/karl-cardenas-coding/mywhoop/internal"
+	"github.com/karl-cardenas-coding/mywhoop/notifications"
 	"github.com/spf13/cobra"
 	"golang.org/x/oauth2"
 )
@@ -50,6 +53,18 @@ func evaluateConfigOptions(cfg *internal.ConfigurationData) error {
 
 }
 
+// accountRuntime contains the resources used by the scheduled jobs of an account.
+type accountRuntime struct {
+	// name is the name of the account. The default account has no name.
+	name string
+	// collections contains the data collection jobs of the account.
+	collections []*dataCollection
+	// jobs contains the supervised jobs of the account. The circuit breakers of the jobs are carried over when the configuration is reloaded.
+	jobs []*supervisedJob
+	// notifier is the notification method of the account.
+	notifier internal.Notification
+}
+
 // login authenticates with Whoop API and gets an access token
 func server(ctx context.Context) error {
 	slog.Info("Server mode enabled")
@@ -61,87 +76,226 @@ func server(ctx context.Context) error {
 	cfg := Configuration
 	client := internal.CreateHTTPClient()
 	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
-	cfg.Server.Enabled = true
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
 	// Evaluate the configuration options
-	err = evaluateConfigOptions(&cfg)
+	err = prepareServerConfig(&cfg)
 	if err != nil {
 		slog.Error("unable to evaluate configuration options", "error", err)
 		return err
 	}
-	exportSelected, err := determineExporterExtension(cfg, client, cliFlags{})
+
+	// The lock prevents a second server on the same host from exporting the data and refreshing the token twice
+	lock, err := internal.AcquireInstanceLock(cfg.Server.Lock, cfg.Credentials.CredentialsFile)
 	if err != nil {
-		slog.Error("unable to determine exporter extension", "error", err)
+		slog.Error("unable to acquire the server lock", "error", err)
 		return err
 	}
+	defer func() {
+		err := lock.Unlock()
+		if err != nil {
+			slog.Error("unable to release the server lock", "error", err)
+		}
+	}()
 
-	err = exportSelected.Setup()
+	electionOpts, stopElection, err := startLeaderElection(ctx, cfg.Server.LeaderElection, client)
 	if err != nil {
-		slog.Error("unable to setup data exporter", "error", err)
+		slog.Error("unable to start the leader election", "error", err)
 		return err
 	}
+	defer stopElection()
 
-	notificationMethod, err := determineNotificationExtension(cfg)
+	schedulerOpts := append([]gocron.SchedulerOption{
+		gocron.WithLocation(time.Local),
+		gocron.WithLogger(schedulerLogger{}),
+	}, electionOpts...)
+
+	sch, err := gocron.NewScheduler(schedulerOpts...)
 	if err != nil {
-		slog.Error("unable to determine notification extension", "error", err)
+		slog.Error("unable to create scheduler", "error", err)
 		return err
 	}
+	rt := &serverRuntime{
+		ctx:    ctx,
+		sch:    sch,
+		client: client,
+		cfg:    cfg,
+		fatal:  make(chan error, 1),
+	}
 
-	if notificationMethod != nil {
-		err = notificationMethod.SetUp()
+	// The scheduler is shut down if the server fails to start. The runtime shuts down the scheduler once the server runs.
+	started := false
+	defer func() {
+		if started {
+			return
+		}
+		err := sch.Shutdown()
 		if err != nil {
-			slog.Error("unable to setup notification method", "error", err)
+			slog.Error("unable to shutdown scheduler", "error", err)
+		}
+	}()
+
+	rt.accounts, err = rt.scheduleAccounts(cfg, nil)
+	if err != nil {
+		return err
+	}
+
+	sch.Start()
+	started = true
+
+	reloads := make(chan struct{}, 1)
+	if cfg.Server.WatchConfig {
+		ok, configFilePath := internal.CheckConfigFile(cfgFile)
+		if !ok {
+			slog.Warn("no configuration file found. The configuration file will not be watched for changes")
+		} else {
+			err = watchConfigFile(ctx, configFilePath, reloads)
+			if err != nil {
+				slog.Error("unable to watch the configuration file for changes", "config", configFilePath, "error", err)
+				rt.shutdown()
+				return err
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-reloads:
+			slog.Info("Configuration file change detected")
+			rt.reload()
+
+		case sig := <-sigs:
+			if sig == syscall.SIGHUP {
+				slog.Info("Configuration reload signal received")
+				rt.reload()
+				continue
+			}
+
+			slog.Info("Server shutdown signal received")
+			rt.shutdown()
+			slog.Info("Server shutdown complete")
+			return nil
+
+		case err := <-rt.fatal:
+			slog.Error("a server job gave up. Shutting down the server", "error", err)
+			rt.shutdown()
+			slog.Info("Server shutdown complete")
 			return err
 		}
 	}
+}
 
-	sch, err := gocron.NewScheduler(
-		gocron.WithLocation(time.Local),
-		gocron.WithLogger(
-			gocron.NewLogger(loggerConverter(cfg.Debug)),
-		),
-	)
+// prepareServerConfig applies the server mode defaults to the configuration.
+func prepareServerConfig(cfg *internal.ConfigurationData) error {
+
+	cfg.Server.Enabled = true
+
+	_, err := internal.ParseSummaryTemplate(cfg.Summary.Template)
 	if err != nil {
-		slog.Error("unable to create scheduler", "error", err)
 		return err
 	}
-	defer func() {
-		err = sch.Shutdown()
+
+	return evaluateConfigOptions(cfg)
+}
+
+// scheduleAccountJobs sets up the exporters and notification method of an account and schedules the token refresh and data collection jobs of the account.
+// A data collection job is scheduled for every job of the configuration. The job names of named accounts are suffixed with the account name.
+// Failed job runs are retried and reported by the supervisor of the job. The exit function shuts down the server when a job run gives up and the give-up action is exit.
+// The startup job refreshing the token and catching up the missed runs is only scheduled if the account has no previous runtime.
+// The jobs keep the circuit breaker state of the jobs of the previous runtime with the same name.
+func scheduleAccountJobs(ctx context.Context, sch gocron.Scheduler, client *http.Client, cfg internal.ConfigurationData, accountName string, exit func(error), previous *accountRuntime) (accountRuntime, error) {
+
+	rt := accountRuntime{
+		name: accountName,
+	}
+
+	if accountName != "" {
+		slog.Info("Scheduling account jobs", "account", accountName, "credentials", cfg.Credentials.CredentialsFile)
+	}
+
+	notificationMethod, err := determineNotificationExtension(cfg)
+	if err != nil {
+		slog.Error("unable to determine notification extension", "account", accountName, "error", err)
+		return rt, err
+	}
+
+	notificationMethod = withDelivery(notificationMethod, cfg)
+
+	// The notification channels are set up when they are created. The undelivered notifications are sent upon startup and by the notification flush job.
+	if notificationMethod != nil && previous == nil {
+		flushUndelivered(client, notificationMethod)
+	}
+
+	if accountName != "" {
+		notificationMethod = newAccountNotification(accountName, notificationMethod)
+	}
+
+	rt.notifier = notificationMethod
+
+	clientCredentials, err := internal.NewClientCredentials(cfg.Credentials)
+	if err != nil {
+		slog.Error("missing Whoop client credentials", "account", accountName, "error", err)
+		return rt, err
+	}
+
+	supervisor := cfg.Server.Supervisor
+	history := internal.NewHistory(cfg.History)
+
+	// The data collections are created before the startup job, as the startup job catches up their missed runs
+	var collections []*dataCollection
+	for _, job := range cfg.ResolveJobs() {
+		collection, err := newJobCollection(cfg, job, accountName, client, notificationMethod, exit, history)
 		if err != nil {
-			slog.Error("unable to shutdown scheduler", "error", err)
+			return rt, err
 		}
-		os.Exit(1)
-	}()
 
-	// This job is to refresh the token immediately upon startup
-	// This is to ensure that the token is valid. If the token is invalid, the server will exit and the user will be notified immediately upon startup.
-	_, err = sch.NewJob(
-		gocron.OneTimeJob(
-			gocron.OneTimeJobStartImmediately(),
-		),
-		gocron.NewTask(func() error {
-			slog.Info("Refreshing auth token token")
-			return refreshJWT(ctx, client, cfg.Credentials.CredentialsFile)
-		}),
-		gocron.WithName("mywhoop_startup_token_refresh_job"),
-		gocron.WithSingletonMode(gocron.LimitModeReschedule),
-		gocron.WithEventListeners(
-			gocron.AfterJobRunsWithError(
-				func(jobID uuid.UUID, jobName string, err error) {
-					slog.Error("error completing the startup token refresh job", "error", err)
-					notifyErr := notificationMethod.Publish(client, []byte(fmt.Sprintf("Error running the token refresh job. Additional context below: \n %s", err)), internal.EventErrors.String())
-					if notifyErr != nil {
-						slog.Error("unable to send notification", "error", notifyErr)
-					}
-					os.Exit(1)
-				},
+		rt.collections = append(rt.collections, collection)
+		rt.jobs = append(rt.jobs, collection.job)
+		collections = append(collections, collection)
+	}
+
+	// The startup and the periodic token refresh jobs share a circuit breaker, so that both are paused when the token can't be refreshed.
+	tokenJob := newSupervisedJob(accountJobName("mywhoop_token_refresh_job", accountName), accountName, "Error running the token refresh job.", supervisor.TokenRefresh, supervisor.CircuitBreaker, client, notificationMethod, exit, history)
+	refreshToken := func(ctx context.Context, run *internal.RunRecord) error {
+		slog.Info("Refreshing auth token token", "account", accountName)
+		return refreshJWT(ctx, client, clientCredentials, cfg.Credentials.CredentialsFile)
+	}
+	rt.jobs = append(rt.jobs, tokenJob)
+
+	// A reload does not close the open circuit breakers
+	if previous != nil {
+		carryOverBreakers(previous.jobs, rt.jobs)
+	}
+
+	// The startup job is not scheduled again when the configuration is reloaded, so that a reload does not trigger a data collection.
+	if previous == nil {
+		// This job is to refresh the token immediately upon startup
+		// This is to ensure that the token is valid. If the token is invalid, the user is notified immediately upon startup.
+		_, err = sch.NewJob(
+			gocron.OneTimeJob(
+				gocron.OneTimeJobStartImmediately(),
 			),
-		),
-	)
-	if err != nil {
-		slog.Error("unable to create the immediate one-time JWT refresh upon startup", "error", err)
-		return err
+			// The missed data collections are caught up once the token is refreshed and before the schedule resumes.
+			gocron.NewTask(func() error {
+				err := tokenJob.run(ctx, refreshToken)
+				if err != nil {
+					return err
+				}
+
+				var errs []error
+				for _, collection := range collections {
+					errs = append(errs, collection.catchUp(ctx, cfg.Server.CatchUp.Policy))
+				}
+				return errors.Join(errs...)
+			}),
+			gocron.WithName(accountJobName("mywhoop_startup_token_refresh_job", accountName)),
+			gocron.WithTags(SERVER_JOBS_TAG),
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			slog.Error("unable to create the immediate one-time JWT refresh upon startup", "account", accountName, "error", err)
+			return rt, err
+		}
 	}
 
 	_, err = sch.NewJob(
@@ -149,266 +303,415 @@ func server(ctx context.Context) error {
 			jwtRefreshDurationValidator(cfg.Server.JWTRefreshDuration),
 		),
 		gocron.NewTask(func() error {
-			slog.Info("Refreshing auth token token")
-			return refreshJWT(ctx, client, cfg.Credentials.CredentialsFile)
+			return tokenJob.run(ctx, refreshToken)
 		}),
-		gocron.WithName("mywhoop_token_refresh_job"),
+		gocron.WithName(accountJobName("mywhoop_token_refresh_job", accountName)),
+		gocron.WithTags(SERVER_JOBS_TAG),
 		gocron.WithSingletonMode(gocron.LimitModeReschedule),
-		gocron.WithEventListeners(
-			gocron.AfterJobRunsWithError(
-				func(jobID uuid.UUID, jobName string, err error) {
-					slog.Error("error completing the token refresh job", "error", err)
-					notifyErr := notificationMethod.Publish(client, []byte(fmt.Sprintf("Error running the token refresh job. Additional context below: \n %s", err)), internal.EventErrors.String())
-					if notifyErr != nil {
-						slog.Error("unable to send notification", "error", notifyErr)
-					}
-					os.Exit(1)
-				},
-			),
-		),
 	)
 	if err != nil {
-		slog.Error("unable to create token cron job", "error", err)
-		return err
+		slog.Error("unable to create token cron job", "account", accountName, "error", err)
+		return rt, err
+	}
+
+	if notificationMethod != nil && cfg.NotificationDelivery.SpoolDir != "" {
+		_, err = sch.NewJob(
+			gocron.DurationJob(internal.DEFAULT_NOTIFICATION_FLUSH_INTERVAL),
+			gocron.NewTask(func() {
+				flushUndelivered(client, notificationMethod)
+			}),
+			gocron.WithName(accountJobName("mywhoop_notification_flush_job", accountName)),
+			gocron.WithTags(SERVER_JOBS_TAG),
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			slog.Error("unable to create the notification flush job", "account", accountName, "error", err)
+			return rt, err
+		}
 	}
 
-	var cronValue string
-	if cfg.Server.Crontab != "" {
-		cronValue = cfg.Server.Crontab
-	} else {
-		cronValue = internal.DEFAULT_SERVER_CRON_SCHEDULE
-	}
-	slog.Debug("Cron schedule", "schedule", cronValue)
+	for _, collection := range collections {
+		slog.Debug("Cron schedule", "job", collection.job.name, "schedule", collection.crontab, "window", collection.window, "account", accountName)
 
-	_, err = sch.NewJob(
-		gocron.CronJob(cronValue, false),
-		gocron.NewTask(downloadWhoopData,
-			ctx,
-			cfg,
-			client,
-			exportSelected,
-			notificationMethod),
-		gocron.WithName("mywhoop_data_collection_job"),
-		gocron.WithSingletonMode(gocron.LimitModeReschedule),
-		gocron.WithEventListeners(
-			gocron.AfterJobRunsWithError(
-				func(jobID uuid.UUID, jobName string, err error) {
-					slog.Error("error running server job", "error", err)
-					notifyErr := notificationMethod.Publish(client, []byte(fmt.Sprintf("Error running the server job. Additional context below: \n %s", err)), internal.EventErrors.String())
-					if notifyErr != nil {
-						slog.Error("unable to send notification", "error", notifyErr)
-					}
-					os.Exit(1)
-				},
-			),
-		),
-	)
-	if err != nil {
-		slog.Error("unable to create cron job", "error", err)
-		return err
+		_, err = sch.NewJob(
+			gocron.CronJob(collection.crontab, false),
+			gocron.NewTask(collection.scheduled, ctx),
+			gocron.WithName(collection.job.name),
+			gocron.WithTags(SERVER_JOBS_TAG),
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			slog.Error("unable to create cron job", "job", collection.job.name, "account", accountName, "error", err)
+			return rt, err
+		}
 	}
 
-	sch.Start()
+	return rt, nil
+}
 
-	sig := <-sigs
-	if sig == syscall.SIGINT || sig == syscall.SIGTERM {
-		slog.Info("Server shutdown signal received")
-		slog.Info("Cleaning up server resources")
-		err := exportSelected.CleanUp()
-		if err != nil {
-			slog.Error("unable to clean up export", "error", err)
-			notifyErr := notificationMethod.Publish(client, []byte(fmt.Sprintf("unable to clean up export. Additional error message: \n %s", err)), internal.EventErrors.String())
-			if notifyErr != nil {
-				slog.Error("unable to send notification", "error", notifyErr)
-			}
-		}
-		err = sch.StopJobs()
-		if err != nil {
-			slog.Error("unable to stop jobs", "error", err)
-			notifyErr := notificationMethod.Publish(client, []byte(fmt.Sprintf("unable to stop jobs. Additional error message: \n %s", err)), internal.EventErrors.String())
-			if notifyErr != nil {
-				slog.Error("unable to send notification", "error", notifyErr)
-			}
+// carryOverBreakers restores the circuit breaker state of the previous jobs in the jobs with the same name and account.
+func carryOverBreakers(previous, jobs []*supervisedJob) {
+
+	for _, job := range jobs {
+		if job.breaker == nil {
+			continue
 		}
-		err = sch.Shutdown()
-		if err != nil {
-			slog.Error("unable to shutdown scheduler", "error", err)
-			notifyErr := notificationMethod.Publish(client, []byte(fmt.Sprintf("unable to shutdown scheduler. Additional error message: \n %s", err)), internal.EventErrors.String())
-			if notifyErr != nil {
-				slog.Error("unable to send notification", "error", notifyErr)
+
+		for _, prev := range previous {
+			if prev.name == job.name && prev.account == job.account && prev.breaker != nil {
+				job.breaker.restore(prev.breaker)
 			}
 		}
-		slog.Info("Server shutdown complete")
-		os.Exit(0)
+	}
+}
+
+// newJobCollection sets up the exporter of the data collection job and returns the data collection of the job.
+// The job export and format settings take precedence over the account settings. The data of jobs with a window shorter than a day is named after the time of the run.
+func newJobCollection(cfg internal.ConfigurationData, job internal.ServerJob, accountName string, client *http.Client, notify internal.Notification, exit func(error), history *internal.History) (*dataCollection, error) {
+
+	jobCfg := cfg.ForJob(job, accountName)
+	jobName := accountJobName(job.JobName(), accountName)
+
+	exportSelected, err := determineExporterExtension(jobCfg, client, cliFlags{})
+	if err != nil {
+		slog.Error("unable to determine exporter extension", "job", jobName, "account", accountName, "error", err)
+		return nil, err
 	}
 
-	return nil
+	err = exportSelected.Setup()
+	if err != nil {
+		slog.Error("unable to setup data exporter", "job", jobName, "account", accountName, "error", err)
+		return nil, err
+	}
+
+	window := job.WindowDuration()
+	dated, ok := exportSelected.(internal.DatedExport)
+	if ok && window < 24*time.Hour {
+		dated.SetDateLayout("2006_01_02_1504")
+	}
+
+	dataJob := newSupervisedJob(jobName, accountName, "Error running the server job.", cfg.Server.Supervisor.DataCollection, cfg.Server.Supervisor.CircuitBreaker, client, notify, exit, history)
+
+	return newDataCollection(dataJob, job.Schedule(cfg.Server), window, cfg.Server.CatchUp, exportSelected, func(ctx context.Context, window internal.Window, run *internal.RunRecord) error {
+		return downloadWhoopData(ctx, jobName, jobCfg, client, exportSelected, notify, job, window, run)
+	}), nil
+}
+
+// accountJobName returns the name of a scheduled job. The names of jobs belonging to named accounts are suffixed with the account name.
+func accountJobName(jobName, accountName string) string {
+
+	if accountName == "" {
+		return jobName
+	}
+
+	return jobName + "_" + accountName
 }
 
 // downloadWhoopData orchestrates the download of Whoop data and ensures the data is exported.
-func downloadWhoopData(ctx context.Context, config internal.ConfigurationData, client *http.Client, exp internal.Export, notify internal.Notification) error {
+// The job name is included in the notifications sent by the job. Errors are returned to the supervisor of the job, which retries the job and sends the error notifications.
+// The collections of the job are fetched from the Whoop API for the window. The window, the record counts, the export, and the notifications of the job are added to the run record.
+// The daily summary and the health alerts are only sent if the job enables them.
+func downloadWhoopData(ctx context.Context, jobName string, config internal.ConfigurationData, client *http.Client, exp internal.Export, notify internal.Notification, job internal.ServerJob, window internal.Window, run *internal.RunRecord) error {
 
 	ok, _, err := internal.VerfyToken(config.Credentials.CredentialsFile)
 	if err != nil {
-		slog.Error("unable to verify token", "error", err)
-		notifyErr := notify.Publish(client, []byte("Unable to verify the existing token during the token refresh process."), internal.EventErrors.String())
-		if notifyErr != nil {
-			slog.Error("unable to send notification", "error", notifyErr)
-		}
-		return err
+		slog.ErrorContext(ctx, "unable to verify token", "error", err)
+		return fmt.Errorf("unable to verify the existing token: %w", err)
 	}
 
 	if !ok {
-		slog.Error("auth token is invalid or expired")
-		notifyErr := notify.Publish(client, []byte("The authentication token is invalid or expired."), internal.EventErrors.String())
-		if notifyErr != nil {
-			slog.Error("unable to send notification", "error", notifyErr)
-		}
-		os.Exit(1)
+		slog.ErrorContext(ctx, "auth token is invalid or expired")
+		return errors.New("the authentication token is invalid or expired")
 	}
 
-	slog.Info("Starting data collection")
+	slog.InfoContext(ctx, "Starting data collection")
 	var ua string = UserAgent
 
 	token, err := internal.ReadTokenFromFile(config.Credentials.CredentialsFile)
 	if err != nil {
-		slog.Error("unable to read token file", "error", err)
-		notifyErr := notify.Publish(client, []byte(fmt.Sprintf("Failed to read the authentication token from file during the regular daily retrieve cycle. Additional context below: \n %s", err)), internal.EventErrors.String())
-		if notifyErr != nil {
-			slog.Error("unable to send notification", "error", notifyErr)
-		}
-		os.Exit(1)
+		slog.ErrorContext(ctx, "unable to read token file", "error", err)
+		return fmt.Errorf("failed to read the authentication token from file: %w", err)
 	}
 
 	var user internal.User
 
-	finalDataRaw, err := getData(ctx, user, client, token, ua, getFileType(config))
+	startTime, endTime := window.Filter()
+	slog.DebugContext(ctx, "Time Filters", "start", startTime, "end", endTime)
+	run.Window = &internal.RunWindow{Start: startTime, End: endTime}
+
+	finalDataRaw, err := getData(ctx, &user, client, token, ua, getFileType(config), job.CollectionSet(), startTime, endTime)
 	if err != nil {
-		slog.Error("unable to get data", "error", err)
-		notifyErr := notify.Publish(client, []byte(fmt.Sprintf("Failed to get data from the Whoop API. Additional context below: \n %s", err)), internal.EventErrors.String())
-		if notifyErr != nil {
-			slog.Error("unable to send notification", "error", notifyErr)
-		}
-		os.Exit(1)
+		slog.ErrorContext(ctx, "unable to get data", "error", err)
+		return fmt.Errorf("failed to get data from the Whoop API: %w", err)
+	}
+	run.Records = recordCounts(user)
+
+	// The records of the exporter carry the log attributes of the run
+	if logged, ok := exp.(internal.LoggedExport); ok {
+		logged.SetLogger(internal.RunLogger(ctx, internal.LogComponentExport))
+		defer logged.SetLogger(nil)
 	}
 
 	err = exp.Export(finalDataRaw)
 	if err != nil {
-		slog.Error("unable to export data", "error", err)
-		notifyErr := notify.Publish(client, []byte(fmt.Sprintf("Failed to export data. Additional context below: \n %s", err)), internal.EventErrors.String())
-		if notifyErr != nil {
-			slog.Error("unable to send notification", "error", notifyErr)
+		slog.ErrorContext(ctx, "unable to export data", "error", err)
+		cleanUpErr := exp.CleanUp()
+		if cleanUpErr != nil {
+			slog.ErrorContext(ctx, "unable to clean up export", "error", cleanUpErr)
 		}
-		os.Exit(1)
+		return fmt.Errorf("failed to export data: %w", err)
 	}
+	run.Export = exportResult(config.Export, exp, len(finalDataRaw))
 
 	err = exp.CleanUp()
 	if err != nil {
-		slog.Error("unable to clean up export", "error", err)
-		notifyErr := notify.Publish(client, []byte(fmt.Sprintf("Failed to clean up export. Additional context below: \n %s", err)), internal.EventErrors.String())
-		if notifyErr != nil {
-			slog.Error("unable to send notification", "error", notifyErr)
-		}
-		os.Exit(1)
+		slog.ErrorContext(ctx, "unable to clean up export", "error", err)
+		return fmt.Errorf("failed to clean up export: %w", err)
 	}
 
-	slog.Info("Data collection complete")
-	err = notify.Publish(client, []byte("Daily data collection complete."), internal.EventSuccess.String())
+	slog.InfoContext(ctx, "Data collection complete")
+	body := "Data collection complete."
+	if job.SendsSummary() {
+		body = dailySummary(config, user)
+	}
+	msg := withExportedData(newJobMessage(ctx, jobName, internal.EventSuccess, body, nil), exp, finalDataRaw, getFileType(config))
+	err = publishMessage(client, notify, msg)
 	if err != nil {
-		slog.Error("unable to send notification", "error", err)
+		slog.ErrorContext(ctx, "unable to send notification", "error", err)
 	}
+	run.AddNotification(msg.Event, err)
+
+	if !job.EvaluatesAlerts() {
+		return nil
+	}
+
+	var baseline []internal.RecoveryRecords
+	if config.Alerts.NeedsBaseline() {
+		// The baseline ends with the window, so that a catch-up run compares the data of the window with the days before the window
+		recovery, err := user.GetRecoveryCollection(ctx, client, internal.DEFAULT_WHOOP_API_RECOVERY_DATA_URL, token.AccessToken, internal.BaselineFilter(window.End), ua)
+		if err != nil {
+			slog.ErrorContext(ctx, "unable to get the recovery baseline of the health alerts", "error", err)
+		} else {
+			baseline = recovery.RecoveryRecords
+		}
+	}
+
+	publishAlerts(ctx, jobName, config.Alerts, user, baseline, client, notify, run)
 
 	return nil
 }
 
-// refreshJWT refreshes the Whoop API JWT token.
-func refreshJWT(ctx context.Context, client *http.Client, credentialsFilePath string) error {
-	currentToken, err := internal.ReadTokenFromFile(credentialsFilePath)
-	if err != nil {
-		return err
+// publishAlerts evaluates the health alert rules against the collected data and publishes the raised alerts as a single alerts notification.
+// The result of the notification is added to the run record.
+func publishAlerts(ctx context.Context, jobName string, cfg internal.AlertsConfig, user internal.User, baseline []internal.RecoveryRecords, client *http.Client, notify internal.Notification, run *internal.RunRecord) {
+
+	if !cfg.Enabled() {
+		return
 	}
 
-	slog.Debug("Using current token", "token", currentToken.AccessToken[0:4])
+	alerts := internal.EvaluateAlerts(cfg, user, baseline)
+	if len(alerts) == 0 {
+		slog.Info("No health alerts raised")
+		return
+	}
 
-	auth := internal.AuthRequest{
-		AuthToken:        currentToken.AccessToken,
-		RefreshToken:     currentToken.RefreshToken,
-		Client:           client,
-		ClientID:         os.Getenv("WHOOP_CLIENT_ID"),
-		ClientSecret:     os.Getenv("WHOOP_CLIENT_SECRET"),
-		TokenURL:         internal.DEFAULT_ACCESS_TOKEN_URL,
-		AuthorizationURL: internal.DEFAULT_AUTHENTICATION_URL,
+	for _, alert := range alerts {
+		slog.Warn("Health alert raised", "rule", alert.Rule, "alert", alert.Message)
 	}
 
-	token, err := internal.RefreshToken(ctx, auth)
+	err := publishMessage(client, notify, newJobMessage(ctx, jobName, internal.EventAlerts, internal.AlertsMessage(alerts), nil))
 	if err != nil {
-		return err
+		slog.Error("unable to send notification", "error", err)
 	}
+	run.AddNotification(internal.EventAlerts.String(), err)
+}
 
-	if len(token.AccessToken) < 1 {
-		return errors.New("no access token")
+// newJobMessage returns a notification message sent by the job. The error is included as the error context of the message.
+// The message is published with the logger of the job run, so that the records of the notification methods carry the log attributes of the context.
+func newJobMessage(ctx context.Context, jobName string, event internal.Event, body string, err error) notifications.Message {
+
+	msg := notifications.Message{
+		Event:     event.String(),
+		Job:       jobName,
+		Body:      body,
+		Timestamp: time.Now(),
+	}.WithLogger(internal.RunLogger(ctx, internal.LogComponentNotify))
+
+	if err != nil {
+		msg.Error = err.Error()
 	}
 
-	slog.Info("New token generated:", token.AccessToken[0:4], "....")
+	return msg
+}
+
+// dailySummary returns the body of the success notification of the data collection job with the summary of the Whoop data.
+// The summary is omitted if the summary template can't be rendered.
+func dailySummary(config internal.ConfigurationData, user internal.User) string {
+
+	body := "Daily data collection complete."
 
-	data, err := json.MarshalIndent(token, "", " ")
+	tmpl, err := internal.ParseSummaryTemplate(config.Summary.Template)
 	if err != nil {
-		return err
+		slog.Error("unable to parse the summary template", "error", err)
+		return body
+	}
+
+	summary, err := internal.NewSummary(user).Render(tmpl)
+	if err != nil {
+		slog.Error("unable to render the daily summary", "error", err)
+		return body
+	}
+
+	if summary == "" {
+		return body
+	}
+
+	return body + "\n\n" + summary
+}
+
+// withExportedData adds the exported data to the message as an attachment. The location of the exported data is added as the message link if the exporter reports a URL.
+func withExportedData(msg notifications.Message, exp internal.Export, data []byte, fileType string) notifications.Message {
+
+	name := "user." + fileType
+
+	if locator, ok := exp.(internal.ExportLocator); ok {
+		location := locator.Location()
+		if location != "" {
+			name = path.Base(location)
+		}
+		if strings.HasPrefix(location, "https://") || strings.HasPrefix(location, "http://") {
+			msg.Link = location
+		}
+	}
+
+	msg.Attachment = &notifications.Attachment{
+		Name: name,
+		Data: data,
+	}
+
+	return msg
+}
+
+// recordCounts returns the number of records fetched per collection.
+func recordCounts(user internal.User) map[string]int {
+	return map[string]int{
+		"sleep":    len(user.SleepCollection.SleepCollectionRecords),
+		"recovery": len(user.RecoveryCollection.RecoveryRecords),
+		"workout":  len(user.WorkoutCollection.Records),
+		"cycle":    len(user.CycleCollection.Records),
+	}
+}
+
+// exportResult returns the destination and the key of the exported data. The bucket is the destination of the AWS S3 exports and the directory is the destination of the file exports.
+func exportResult(cfg internal.ConfigExport, exp internal.Export, size int) *internal.RunExport {
+
+	result := &internal.RunExport{
+		Method: cfg.Method,
+		Bytes:  size,
+	}
+
+	locator, ok := exp.(internal.ExportLocator)
+	if !ok {
+		return result
+	}
+
+	location := locator.Location()
+	if location == "" {
+		return result
+	}
+
+	if cfg.Method == "s3" {
+		result.Destination = "s3://" + cfg.AWSS3.Bucket
+		u, err := url.Parse(location)
+		if err == nil {
+			result.Key = strings.TrimPrefix(u.Path, "/")
+		}
+		return result
+	}
+
+	result.Destination = path.Dir(location)
+	result.Key = path.Base(location)
+
+	return result
+}
+
+// refreshJWT refreshes the Whoop API JWT token.
+// The credentials file is locked during the refresh so that other MyWhoop processes sharing the file are not invalidated.
+func refreshJWT(ctx context.Context, client *http.Client, clientCredentials internal.ClientCredentials, credentialsFilePath string) error {
+
+	auth := internal.AuthRequest{
+		Client:           client,
+		ClientID:         clientCredentials.ID,
+		ClientSecret:     clientCredentials.Secret,
+		TokenURL:         internal.DEFAULT_ACCESS_TOKEN_URL,
+		AuthorizationURL: internal.DEFAULT_AUTHENTICATION_URL,
 	}
 
-	err = os.WriteFile(credentialsFilePath, data, 0755)
+	token, err := internal.RefreshTokenFile(ctx, auth, credentialsFilePath)
 	if err != nil {
 		return err
 	}
 
+	slog.Info("New token generated:", token.AccessToken[0:4], "....")
+
 	return nil
 }
 
-// getData queries the Whoop API and gets the user data
-func getData(ctx context.Context, user internal.User, client *http.Client, token oauth2.Token, ua, fileType string) ([]byte, error) {
+// getData queries the Whoop API and gets the collections of the user data between the start and end times. The collections are stored in the user.
+func getData(ctx context.Context, user *internal.User, client *http.Client, token oauth2.Token, ua, fileType string, collections []string, startTime, endTime string) ([]byte, error) {
 
-	startTime, endTime := internal.GenerateLast24HoursString()
 	filterString := fmt.Sprintf("start=%s&end=%s", startTime, endTime)
 
-	slog.Debug("Filter string", "filter", filterString)
+	slog.DebugContext(ctx, "Filter string", "filter", filterString, "collections", collections)
 
-	sleep, err := user.GetSleepCollection(ctx, client, internal.DEFAULT_WHOOP_API_USER_SLEEP_DATA_URL, token.AccessToken, filterString, ua)
-	if err != nil {
-		internal.LogError(err)
-		return []byte{}, err
+	if slices.Contains(collections, "sleep") {
+		sleep, err := user.GetSleepCollection(internal.WithLogAttrs(ctx, slog.String("collection", "sleep")), client, internal.DEFAULT_WHOOP_API_USER_SLEEP_DATA_URL, token.AccessToken, filterString, ua)
+		if err != nil {
+			internal.LogError(err)
+			return []byte{}, err
+		}
+
+		sleep.NextToken = nil
+		user.SleepCollection = *sleep
 	}
 
-	sleep.NextToken = nil
-	user.SleepCollection = *sleep
+	if slices.Contains(collections, "recovery") {
+		recovery, err := user.GetRecoveryCollection(internal.WithLogAttrs(ctx, slog.String("collection", "recovery")), client, internal.DEFAULT_WHOOP_API_RECOVERY_DATA_URL, token.AccessToken, filterString, ua)
+		if err != nil {
+			internal.LogError(err)
+			return []byte{}, err
+		}
 
-	recovery, err := user.GetRecoveryCollection(ctx, client, internal.DEFAULT_WHOOP_API_RECOVERY_DATA_URL, token.AccessToken, filterString, ua)
-	if err != nil {
-		internal.LogError(err)
-		return []byte{}, err
+		recovery.NextToken = nil
+		user.RecoveryCollection = *recovery
 	}
 
-	recovery.NextToken = nil
-	user.RecoveryCollection = *recovery
+	if slices.Contains(collections, "workout") {
+		workout, err := user.GetWorkoutCollection(internal.WithLogAttrs(ctx, slog.String("collection", "workout")), client, internal.DEFAULT_WHOOP_API_WORKOUT_DATA_URL, token.AccessToken, filterString, ua)
+		if err != nil {
+			internal.LogError(err)
+			return []byte{}, err
+		}
 
-	workout, err := user.GetWorkoutCollection(ctx, client, internal.DEFAULT_WHOOP_API_WORKOUT_DATA_URL, token.AccessToken, filterString, ua)
-	if err != nil {
-		internal.LogError(err)
-		return []byte{}, err
+		workout.NextToken = nil
+		user.WorkoutCollection = *workout
 	}
 
-	workout.NextToken = nil
-	user.WorkoutCollection = *workout
+	if slices.Contains(collections, "cycle") {
+		cycle, err := user.GetCycleCollection(internal.WithLogAttrs(ctx, slog.String("collection", "cycle")), client, internal.DEFAULT_WHOOP_API_CYCLE_DATA_URL, token.AccessToken, filterString, ua)
+		if err != nil {
+			internal.LogError(err)
+			return []byte{}, err
+		}
 
-	cycle, err := user.GetCycleCollection(ctx, client, internal.DEFAULT_WHOOP_API_CYCLE_DATA_URL, token.AccessToken, filterString, ua)
-	if err != nil {
-		internal.LogError(err)
-		return []byte{}, err
+		cycle.NextToken = nil
+		user.CycleCollection = *cycle
 	}
 
-	cycle.NextToken = nil
-	user.CycleCollection = *cycle
-
-	var finalDataRaw []byte
+	var (
+		finalDataRaw []byte
+		err          error
+	)
 	switch fileType {
 	case "json":
 		finalDataRaw, err = json.MarshalIndent(user, "", "  ")
@@ -417,7 +720,7 @@ func getData(ctx context.Context, user internal.User, client *http.Client, token
 			return finalDataRaw, err
 		}
 	case "xlsx":
-		finalDataRaw, err = internal.ConvertToExcel(user)
+		finalDataRaw, err = internal.ConvertToExcel(*user)
 		if err != nil {
 			internal.LogError(err)
 			return finalDataRaw, err
@@ -435,22 +738,6 @@ func getData(ctx context.Context, user internal.User, client *http.Client, token
 
 }
 
-// loggerConverter converts the string log level to the gocron log level
-func loggerConverter(lvl string) gocron.LogLevel {
-	switch strings.ToLower(lvl) {
-	case "debug":
-		return gocron.LogLevelDebug
-	case "info":
-		return gocron.LogLevelInfo
-	case "warn":
-		return gocron.LogLevelWarn
-	case "error":
-		return gocron.LogLevelError
-	default:
-		return gocron.LogLevelInfo
-	}
-}
-
 // jwtRefreshDurationValidator validates the JWT refresh duration.
 // If the duration is greater than 59 minutes or less than 0, the default DEFAULT_SERVER_TOKEN_REFRESH_CRON_SCHEDULE is used.
 func jwtRefreshDurationValidator(incoming int) time.Duration {
@@ -0,0 +1,295 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	gocron "github.com/go-co-op/gocron/v2"
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+)
+
+// SERVER_JOBS_TAG is the tag of the scheduled jobs created from the configuration. The jobs are replaced when the configuration is reloaded.
+const SERVER_JOBS_TAG string = "mywhoop_server_jobs"
+
+// serverRuntime contains the scheduler and the account resources of the running server.
+// The account resources and the scheduled jobs are rebuilt when the configuration is reloaded.
+type serverRuntime struct {
+	// mu serializes configuration reloads and the server shutdown.
+	mu sync.Mutex
+	// ctx is the context of the server.
+	ctx context.Context
+	// sch is the scheduler of the server jobs.
+	sch gocron.Scheduler
+	// client is the HTTP client shared by the server jobs.
+	client *http.Client
+	// cfg is the configuration currently in use.
+	cfg internal.ConfigurationData
+	// accounts contains the resources of the scheduled accounts.
+	accounts []accountRuntime
+	// fatal receives the error of a server job that gave up with the exit action. The server shuts down when it receives an error.
+	fatal chan error
+}
+
+// scheduleAccounts schedules the jobs of every account in the configuration. The startup job refreshing the token and catching up
+// the missed runs is only scheduled for the accounts missing from the running accounts, so that a reload does not trigger a data collection.
+// The jobs of the running accounts keep their circuit breaker state.
+// If an account can't be scheduled, the jobs already scheduled from the configuration are removed and their exporters are cleaned up.
+func (s *serverRuntime) scheduleAccounts(cfg internal.ConfigurationData, running []accountRuntime) ([]accountRuntime, error) {
+
+	var runtimes []accountRuntime
+
+	for _, account := range cfg.ResolveAccounts() {
+		accountCfg := cfg.ForAccount(account)
+
+		// Evaluate the configuration options of the account as the export settings may be overridden
+		err := evaluateConfigOptions(&accountCfg)
+		if err != nil {
+			slog.Error("unable to evaluate configuration options", "account", account.Name, "error", err)
+			s.sch.RemoveByTags(SERVER_JOBS_TAG)
+			cleanUpExporters(runtimes)
+			return nil, err
+		}
+
+		var previous *accountRuntime
+		if i := slices.IndexFunc(running, func(rt accountRuntime) bool { return rt.name == account.Name }); i >= 0 {
+			previous = &running[i]
+		}
+
+		rt, err := scheduleAccountJobs(s.ctx, s.sch, s.client, accountCfg, account.Name, s.exit, previous)
+		if err != nil {
+			s.sch.RemoveByTags(SERVER_JOBS_TAG)
+			cleanUpExporters(append(runtimes, rt))
+			return nil, err
+		}
+		runtimes = append(runtimes, rt)
+	}
+
+	return runtimes, nil
+}
+
+// cleanUpExporters waits for the running data collections of the accounts to complete and cleans up their exporters.
+func cleanUpExporters(accounts []accountRuntime) {
+
+	for _, rt := range accounts {
+		for _, collection := range rt.collections {
+			err := collection.close()
+			if err != nil {
+				slog.Error("unable to clean up the previous export", "account", rt.name, "error", err)
+			}
+		}
+	}
+}
+
+// logCredentialChanges logs the accounts whose Whoop client credentials changed with the reloaded configuration.
+// The credentials are resolved when the configuration is loaded, so a rotated env: or file: reference only takes effect with a reload.
+func logCredentialChanges(previous, cfg internal.ConfigurationData) {
+
+	for _, account := range cfg.ResolveAccounts() {
+		current := cfg.ForAccount(account).Credentials
+
+		old, ok := previous.FindAccount(account.Name)
+		if !ok && account.Name != "" {
+			continue
+		}
+		before := previous.ForAccount(old).Credentials
+
+		if before.ClientID != current.ClientID || before.ClientSecret != current.ClientSecret {
+			slog.Info("Whoop client credentials changed with the reloaded configuration", "account", account.Name)
+		}
+	}
+}
+
+// exit requests the shutdown of the server. Only the first request is kept if several jobs give up at the same time.
+func (s *serverRuntime) exit(err error) {
+
+	select {
+	case s.fatal <- err:
+	default:
+		slog.Debug("server shutdown already requested", "error", err)
+	}
+}
+
+// reload loads the configuration again and reschedules the server jobs.
+// If the new configuration is invalid, the current configuration is kept and an error notification is sent.
+func (s *serverRuntime) reload() {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cfg, err := loadConfiguration()
+	if err == nil {
+		err = prepareServerConfig(&cfg)
+	}
+	if err != nil {
+		slog.Error("unable to reload the configuration. The current configuration is kept", "error", err)
+		s.notifyErrors(fmt.Sprintf("Unable to reload the configuration. The current configuration is kept. Additional context below: \n %s", err))
+		return
+	}
+
+	previous := s.accounts
+	s.sch.RemoveByTags(SERVER_JOBS_TAG)
+
+	// The removed jobs may still be running. The exporters are cleaned up once the running data collections complete,
+	// so that the circuit breaker state carried over to the new jobs includes the result of the running collections.
+	cleanUpExporters(previous)
+
+	accounts, err := s.scheduleAccounts(cfg, previous)
+	if err != nil {
+		slog.Error("unable to schedule the jobs of the reloaded configuration. Restoring the current configuration", "error", err)
+
+		restored, restoreErr := s.scheduleAccounts(s.cfg, previous)
+		if restoreErr != nil {
+			slog.Error("unable to restore the current configuration", "error", restoreErr)
+			err = fmt.Errorf("%w. The current configuration could not be restored: %s", err, restoreErr)
+		} else {
+			s.accounts = restored
+		}
+
+		s.notifyErrorsTo(previous, fmt.Sprintf("Unable to apply the reloaded configuration. Additional context below: \n %s", err))
+		return
+	}
+
+	logCredentialChanges(s.cfg, cfg)
+
+	s.cfg = cfg
+	s.accounts = accounts
+
+	err = setLogger(cfg)
+	if err != nil {
+		slog.Error("unable to apply the reloaded log configuration. The current logger is kept", "error", err)
+	}
+
+	slog.Info("Configuration reloaded", "accounts", len(accounts))
+}
+
+// shutdown cleans up the account resources and stops the scheduler.
+func (s *serverRuntime) shutdown() {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slog.Info("Cleaning up server resources")
+	for _, rt := range s.accounts {
+		for _, collection := range rt.collections {
+			err := collection.close()
+			if err != nil {
+				slog.Error("unable to clean up export", "account", rt.name, "error", err)
+				notifyErr := rt.notifier.Publish(s.client, []byte(fmt.Sprintf("unable to clean up export. Additional error message: \n %s", err)), internal.EventErrors.String())
+				if notifyErr != nil {
+					slog.Error("unable to send notification", "error", notifyErr)
+				}
+			}
+		}
+	}
+
+	err := s.sch.StopJobs()
+	if err != nil {
+		slog.Error("unable to stop jobs", "error", err)
+		s.notifyErrors(fmt.Sprintf("unable to stop jobs. Additional error message: \n %s", err))
+	}
+
+	err = s.sch.Shutdown()
+	if err != nil {
+		slog.Error("unable to shutdown scheduler", "error", err)
+		s.notifyErrors(fmt.Sprintf("unable to shutdown scheduler. Additional error message: \n %s", err))
+	}
+}
+
+// notifyErrors sends an error notification using the notification method of every scheduled account.
+func (s *serverRuntime) notifyErrors(msg string) {
+	s.notifyErrorsTo(s.accounts, msg)
+}
+
+// notifyErrorsTo sends an error notification using the notification method of the provided accounts.
+func (s *serverRuntime) notifyErrorsTo(accounts []accountRuntime, msg string) {
+
+	for _, rt := range accounts {
+		if rt.notifier == nil {
+			continue
+		}
+
+		notifyErr := rt.notifier.Publish(s.client, []byte(msg), internal.EventErrors.String())
+		if notifyErr != nil {
+			slog.Error("unable to send notification", "account", rt.name, "error", notifyErr)
+		}
+	}
+}
+
+// watchConfigFile watches the configuration file and signals the reloads channel when the file changes.
+// The directory of the file is watched as editors and Kubernetes ConfigMaps replace the file instead of writing to it.
+// Consecutive changes are debounced so that a single reload is triggered.
+func watchConfigFile(ctx context.Context, filePath string, reloads chan<- struct{}) error {
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	filePath = filepath.Clean(filePath)
+	err = watcher.Add(filepath.Dir(filePath))
+	if err != nil {
+		watcher.Close()
+		return err
+	}
+
+	slog.Info("Watching the configuration file for changes", "config", filePath)
+
+	go func() {
+		defer watcher.Close()
+
+		var debounce <-chan time.Time
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if !isConfigFileEvent(event, filePath) {
+					continue
+				}
+				slog.Debug("Configuration file event", "event", event.String())
+				debounce = time.After(internal.DEFAULT_CONFIG_RELOAD_DEBOUNCE)
+
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Error("error watching the configuration file", "config", filePath, "error", err)
+
+			case <-debounce:
+				debounce = nil
+				select {
+				case reloads <- struct{}{}:
+				default:
+					// A reload is already pending
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// isConfigFileEvent returns true if the file system event changes the content of the configuration file.
+func isConfigFileEvent(event fsnotify.Event, filePath string) bool {
+
+	if filepath.Clean(event.Name) != filePath && filepath.Base(event.Name) != "..data" {
+		return false
+	}
+
+	return event.Has(fsnotify.Write) || event.Has(fsnotify.Create) || event.Has(fsnotify.Rename)
+}
@@ -0,0 +1,216 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-co-op/gocron/v2"
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+)
+
+func TestServerRuntimeReload(t *testing.T) {
+
+	t.Setenv("WHOOP_CLIENT_ID", "AAAAAAAAAAAAAAAAAAA")
+	t.Setenv("WHOOP_CLIENT_SECRET", "BBBBBBBBBBBBBBBBBBBBB")
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(content string) {
+		err := os.WriteFile(configFile, []byte(content), 0600)
+		if err != nil {
+			t.Fatalf("Failed to write the configuration file: %v", err)
+		}
+	}
+
+	previousCfgFile := cfgFile
+	cfgFile = configFile
+	t.Cleanup(func() { cfgFile = previousCfgFile })
+
+	writeConfig("export:\n  method: file\n  fileExport:\n    filePath: data/\nserver:\n  crontab: \"0 13 * * *\"\n")
+
+	cfg, err := loadConfiguration()
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	err = prepareServerConfig(&cfg)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	// The scheduler is not started so that no job runs during the test
+	sch, err := gocron.NewScheduler()
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+	defer func() { _ = sch.Shutdown() }()
+
+	rt := &serverRuntime{
+		ctx:    context.Background(),
+		sch:    sch,
+		client: &http.Client{},
+		cfg:    cfg,
+	}
+
+	rt.accounts, err = rt.scheduleAccounts(cfg, nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	if len(sch.Jobs()) != 3 {
+		t.Fatalf("Expected 3 scheduled jobs but got %d", len(sch.Jobs()))
+	}
+
+	// An invalid configuration keeps the current configuration
+	writeConfig("export:\n  method: ftp\n")
+	rt.reload()
+
+	if rt.cfg.Server.Crontab != "0 13 * * *" {
+		t.Errorf("Expected the current configuration to be kept but got the crontab %s", rt.cfg.Server.Crontab)
+	}
+
+	if len(sch.Jobs()) != 3 {
+		t.Errorf("Expected the 3 scheduled jobs to be kept but got %d", len(sch.Jobs()))
+	}
+
+	// A valid configuration replaces the jobs
+	writeConfig("export:\n  method: file\n  fileExport:\n    filePath: data/\nserver:\n  crontab: \"0 6 * * *\"\naccounts:\n  - name: alice\n  - name: bob\n")
+	rt.reload()
+
+	if rt.cfg.Server.Crontab != "0 6 * * *" {
+		t.Errorf("Expected the reloaded crontab but got %s", rt.cfg.Server.Crontab)
+	}
+
+	if len(rt.accounts) != 2 {
+		t.Errorf("Expected 2 accounts but got %d", len(rt.accounts))
+	}
+
+	// The new accounts get a startup job
+	if len(sch.Jobs()) != 6 {
+		t.Errorf("Expected 6 scheduled jobs but got %d", len(sch.Jobs()))
+	}
+
+	previous := rt.accounts
+
+	// Open the circuit breaker of the data collection job of alice
+	breaker := rt.accounts[0].collections[0].job.breaker
+	for range internal.DEFAULT_CIRCUIT_BREAKER_THRESHOLD {
+		breaker.failure()
+	}
+
+	// A reload of the running accounts does not schedule the startup jobs again
+	writeConfig("export:\n  method: file\n  fileExport:\n    filePath: data/\nserver:\n  crontab: \"0 7 * * *\"\naccounts:\n  - name: alice\n  - name: bob\n")
+	rt.reload()
+
+	if len(sch.Jobs()) != 4 {
+		t.Errorf("Expected 4 scheduled jobs but got %d", len(sch.Jobs()))
+	}
+
+	// The open circuit breaker is carried over to the new job of alice
+	if rt.accounts[0].collections[0].job.breaker.allow() {
+		t.Errorf("Expected the circuit breaker of the alice job to stay open after the reload")
+	}
+
+	if !rt.accounts[1].collections[0].job.breaker.allow() {
+		t.Errorf("Expected the circuit breaker of the bob job to stay closed after the reload")
+	}
+
+	// The exporters of the replaced jobs are cleaned up
+	if !previous[0].collections[0].closed {
+		t.Errorf("Expected the replaced data collection to be closed")
+	}
+}
+
+func TestWatchConfigFile(t *testing.T) {
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(configFile, []byte("debug: info\n"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reloads := make(chan struct{}, 1)
+	err = watchConfigFile(ctx, configFile, reloads)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	// Unrelated files in the same directory are ignored
+	err = os.WriteFile(filepath.Join(filepath.Dir(configFile), "token.json"), []byte("{}"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write the unrelated file: %v", err)
+	}
+
+	select {
+	case <-reloads:
+		t.Fatalf("Expected no reload for an unrelated file")
+	case <-time.After(1 * time.Second):
+	}
+
+	err = os.WriteFile(configFile, []byte("debug: debug\n"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	select {
+	case <-reloads:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected a reload after the configuration file changed")
+	}
+}
+
+func TestIsConfigFileEvent(t *testing.T) {
+
+	configFile := filepath.Join("etc", "mywhoop", "config.yaml")
+
+	tests := []struct {
+		description string
+		event       fsnotify.Event
+		expected    bool
+	}{
+		{
+			description: "Write to the configuration file",
+			event:       fsnotify.Event{Name: configFile, Op: fsnotify.Write},
+			expected:    true,
+		},
+		{
+			description: "Configuration file replaced",
+			event:       fsnotify.Event{Name: configFile, Op: fsnotify.Create},
+			expected:    true,
+		},
+		{
+			description: "Kubernetes ConfigMap update",
+			event:       fsnotify.Event{Name: filepath.Join("etc", "mywhoop", "..data"), Op: fsnotify.Create},
+			expected:    true,
+		},
+		{
+			description: "Permission change",
+			event:       fsnotify.Event{Name: configFile, Op: fsnotify.Chmod},
+			expected:    false,
+		},
+		{
+			description: "Unrelated file",
+			event:       fsnotify.Event{Name: filepath.Join("etc", "mywhoop", "token.json"), Op: fsnotify.Write},
+			expected:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got := isConfigFileEvent(test.event, configFile)
+			if got != test.expected {
+				t.Errorf("Expected %t but got %t", test.expected, got)
+			}
+		})
+	}
+}
@@ -4,10 +4,10 @@
 package cmd
 
 import (
+	"context"
 	"testing"
 	"time"
 
-	"github.com/go-co-op/gocron/v2"
 	"github.com/karl-cardenas-coding/mywhoop/export"
 	"github.com/karl-cardenas-coding/mywhoop/internal"
 )
@@ -78,87 +78,267 @@ func TestEvaluateConfigOptions(t *testing.T) {
 
 }
 
-func TestLoggerConverter(t *testing.T) {
+func TestJwtRefreshDurationValidator(t *testing.T) {
 
 	tests := []struct {
 		name     string
-		level    string
-		expected gocron.LogLevel
+		duration int
+		expected time.Duration
 	}{
 		{
-			name:     "Test Debug",
-			level:    "debug",
-			expected: gocron.LogLevelDebug,
+			name:     "Test Zero Duration",
+			duration: 0,
+			expected: internal.DEFAULT_SERVER_TOKEN_REFRESH_CRON_SCHEDULE,
 		},
 		{
-			name:     "Test Info",
-			level:    "info",
-			expected: gocron.LogLevelInfo,
+			name:     "Test Negative Duration",
+			duration: -1,
+			expected: internal.DEFAULT_SERVER_TOKEN_REFRESH_CRON_SCHEDULE,
 		},
 		{
-			name:     "Test Warn",
-			level:    "warn",
-			expected: gocron.LogLevelWarn,
+			name:     "Test Positive Duration - 5 min",
+			duration: 5,
+			expected: 5 * time.Minute,
 		},
 		{
-			name:     "Test Error",
-			level:    "error",
-			expected: gocron.LogLevelError,
+			name:     "Test Positive Duration - 55 min",
+			duration: 55,
+			expected: 55 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := jwtRefreshDurationValidator(tt.duration)
+			if result != tt.expected {
+				t.Errorf("Expected %v, got: %v", tt.expected, result)
+			}
+		})
+	}
+
+}
+
+func TestAccountJobName(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		account  string
+		expected string
+	}{
+		{
+			name:     "Test Default Account",
+			account:  "",
+			expected: "mywhoop_data_collection_job",
 		},
 		{
-			name:     "Test Non Allowed Value",
-			level:    "fatal",
-			expected: gocron.LogLevel(2),
+			name:     "Test Named Account",
+			account:  "alice",
+			expected: "mywhoop_data_collection_job_alice",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := loggerConverter(tt.level)
+			result := accountJobName("mywhoop_data_collection_job", tt.account)
 			if result != tt.expected {
 				t.Errorf("Expected %v, got: %v", tt.expected, result)
 			}
 		})
 	}
+}
 
+// mockLocatorExport is an exporter that reports a fixed location.
+type mockLocatorExport struct {
+	location string
 }
 
-func TestJwtRefreshDurationValidator(t *testing.T) {
+func (m *mockLocatorExport) Setup() error             { return nil }
+func (m *mockLocatorExport) Export(data []byte) error { return nil }
+func (m *mockLocatorExport) CleanUp() error           { return nil }
+func (m *mockLocatorExport) Location() string         { return m.location }
+
+func TestWithExportedData(t *testing.T) {
 
 	tests := []struct {
-		name     string
-		duration int
-		expected time.Duration
+		name         string
+		exporter     internal.Export
+		expectedLink string
+		expectedName string
 	}{
 		{
-			name:     "Test Zero Duration",
-			duration: 0,
-			expected: internal.DEFAULT_SERVER_TOKEN_REFRESH_CRON_SCHEDULE,
+			name:         "Test S3 Location",
+			exporter:     &mockLocatorExport{location: "https://mywhoop.s3.us-east-1.amazonaws.com/user_2024-07-31.json"},
+			expectedLink: "https://mywhoop.s3.us-east-1.amazonaws.com/user_2024-07-31.json",
+			expectedName: "user_2024-07-31.json",
 		},
 		{
-			name:     "Test Negative Duration",
-			duration: -1,
-			expected: internal.DEFAULT_SERVER_TOKEN_REFRESH_CRON_SCHEDULE,
+			name:         "Test File Location",
+			exporter:     &mockLocatorExport{location: "data/user.xlsx"},
+			expectedLink: "",
+			expectedName: "user.xlsx",
 		},
 		{
-			name:     "Test Positive Duration - 5 min",
-			duration: 5,
-			expected: 5 * time.Minute,
+			name:         "Test Not Exported",
+			exporter:     &mockLocatorExport{},
+			expectedLink: "",
+			expectedName: "user.json",
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := withExportedData(newJobMessage(context.Background(), "job", internal.EventSuccess, "done", nil), tt.exporter, []byte("{}"), "json")
+			if msg.Link != tt.expectedLink {
+				t.Errorf("Expected link %v, got: %v", tt.expectedLink, msg.Link)
+			}
+			if msg.Attachment == nil {
+				t.Fatal("Expected an attachment, got nil")
+			}
+			if msg.Attachment.Name != tt.expectedName {
+				t.Errorf("Expected attachment name %v, got: %v", tt.expectedName, msg.Attachment.Name)
+			}
+			if string(msg.Attachment.Data) != "{}" {
+				t.Errorf("Expected attachment data %v, got: %v", "{}", string(msg.Attachment.Data))
+			}
+		})
+	}
+}
+
+func TestDailySummary(t *testing.T) {
+
+	user := internal.User{
+		CycleCollection: internal.CycleCollection{
+			Records: []internal.CycleRecords{
+				{ScoreState: "SCORED", Score: internal.CycleScore{Strain: 12.4, Kilojoule: 10104.4}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		user     internal.User
+		expected string
+	}{
 		{
-			name:     "Test Positive Duration - 55 min",
-			duration: 55,
-			expected: 55 * time.Minute,
+			name:     "Test Summary",
+			user:     user,
+			expected: "Daily data collection complete.\n\n🔥 Strain: 12.4, 2415 kcal",
+		},
+		{
+			name:     "Test Empty Summary",
+			user:     internal.User{},
+			expected: "Daily data collection complete.",
+		},
+		{
+			name:     "Test Missing Template",
+			template: "missing.tmpl",
+			user:     user,
+			expected: "Daily data collection complete.",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := jwtRefreshDurationValidator(tt.duration)
+			cfg := internal.ConfigurationData{Summary: internal.SummaryConfig{Template: tt.template}}
+			result := dailySummary(cfg, tt.user)
 			if result != tt.expected {
-				t.Errorf("Expected %v, got: %v", tt.expected, result)
+				t.Errorf("Expected %q, got: %q", tt.expected, result)
 			}
 		})
 	}
+}
+
+func TestPublishAlerts(t *testing.T) {
+
+	user := internal.User{
+		RecoveryCollection: internal.RecoveryCollection{
+			RecoveryRecords: []internal.RecoveryRecords{
+				{ScoreState: "SCORED", Score: internal.RecoveryScore{RecoveryScore: 28}},
+			},
+		},
+	}
 
+	tests := []struct {
+		name     string
+		cfg      internal.AlertsConfig
+		expected int
+	}{
+		{
+			name:     "Test Alerts Disabled",
+			cfg:      internal.AlertsConfig{},
+			expected: 0,
+		},
+		{
+			name:     "Test No Alert Raised",
+			cfg:      internal.AlertsConfig{RecoveryBelow: 20},
+			expected: 0,
+		},
+		{
+			name:     "Test Alert Raised",
+			cfg:      internal.AlertsConfig{RecoveryBelow: 33},
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockMessagePublisher{}
+			publishAlerts(context.Background(), "job", tt.cfg, user, nil, nil, mock, &internal.RunRecord{})
+
+			if len(mock.structured) != tt.expected {
+				t.Fatalf("Expected %d messages, got: %d", tt.expected, len(mock.structured))
+			}
+
+			if tt.expected == 0 {
+				return
+			}
+
+			msg := mock.structured[0]
+			if msg.Event != internal.EventAlerts.String() {
+				t.Errorf("Expected the %s event, got: %s", internal.EventAlerts, msg.Event)
+			}
+			if msg.Job != "job" {
+				t.Errorf("Expected the job name, got: %s", msg.Job)
+			}
+		})
+	}
+}
+
+func TestExportResult(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		cfg      internal.ConfigExport
+		exp      internal.Export
+		expected internal.RunExport
+	}{
+		{
+			name:     "s3",
+			cfg:      internal.ConfigExport{Method: "s3", AWSS3: export.AWS_S3{Bucket: "my-bucket"}},
+			exp:      &mockLocatorExport{location: "https://my-bucket.s3.us-east-1.amazonaws.com/whoop/user_2024-06-01.json"},
+			expected: internal.RunExport{Method: "s3", Destination: "s3://my-bucket", Key: "whoop/user_2024-06-01.json", Bytes: 10},
+		},
+		{
+			name:     "file",
+			cfg:      internal.ConfigExport{Method: "file"},
+			exp:      &mockLocatorExport{location: "data/user_2024-06-01.json"},
+			expected: internal.RunExport{Method: "file", Destination: "data", Key: "user_2024-06-01.json", Bytes: 10},
+		},
+		{
+			name:     "no location",
+			cfg:      internal.ConfigExport{Method: "file"},
+			exp:      &mockLocatorExport{},
+			expected: internal.RunExport{Method: "file", Bytes: 10},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := exportResult(tc.cfg, tc.exp, 10)
+			if *got != tc.expected {
+				t.Errorf("expected %+v, got %+v", tc.expected, *got)
+			}
+		})
+	}
 }
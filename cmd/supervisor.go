@@ -0,0 +1,300 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/google/uuid"
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+	"github.com/karl-cardenas-coding/mywhoop/notifications"
+)
+
+const (
+	// giveUpSkip skips the failed job run. The job runs again at its next scheduled time.
+	giveUpSkip string = "skip"
+	// giveUpExit shuts down the server when a job run gives up.
+	giveUpExit string = "exit"
+)
+
+// supervisedJob runs a server job with a retry policy and a circuit breaker.
+// A failed job run is retried with an exponential backoff. When the run gives up, an error notification is sent and the give-up action is taken.
+type supervisedJob struct {
+	// name is the name of the job.
+	name string
+	// account is the name of the account of the job. Empty for the default account.
+	account string
+	// failureMessage is the message of the error notification sent when a job run gives up.
+	failureMessage string
+	// maxAttempts is the number of attempts of a job run.
+	maxAttempts int
+	// backoff is the initial interval between the attempts of a job run.
+	backoff time.Duration
+	// giveUp is the action taken when a job run gives up.
+	giveUp string
+	// breaker is the circuit breaker of the job. Nil if the circuit breaker is disabled.
+	breaker *circuitBreaker
+	// client is the HTTP client used to send the notifications.
+	client *http.Client
+	// notify is the notification method of the job.
+	notify internal.Notification
+	// exit shuts down the server. It is called when a job run gives up and the give-up action is exit.
+	exit func(error)
+	// history is the run history the runs of the job are recorded to. Nil if the run history is disabled.
+	history *internal.History
+}
+
+// newSupervisedJob returns a supervised job using the retry policy and the circuit breaker configuration. Default values are used for the unset settings.
+// The runs of the job are recorded to the run history if a history is provided.
+func newSupervisedJob(name, account, failureMessage string, policy internal.RetryPolicy, breaker internal.CircuitBreaker, client *http.Client, notify internal.Notification, exit func(error), history *internal.History) *supervisedJob {
+
+	job := &supervisedJob{
+		name:           name,
+		account:        account,
+		failureMessage: failureMessage,
+		maxAttempts:    internal.DEFAULT_JOB_MAX_ATTEMPTS,
+		backoff:        internal.DEFAULT_JOB_RETRY_BACKOFF,
+		giveUp:         internal.DEFAULT_JOB_GIVE_UP_ACTION,
+		breaker:        newCircuitBreaker(breaker),
+		client:         client,
+		notify:         notify,
+		exit:           exit,
+		history:        history,
+	}
+
+	if policy.MaxAttempts > 0 {
+		job.maxAttempts = policy.MaxAttempts
+	}
+
+	if policy.Backoff > 0 {
+		job.backoff = time.Duration(policy.Backoff) * time.Second
+	}
+
+	if policy.GiveUp != "" {
+		job.giveUp = policy.GiveUp
+	}
+
+	return job
+}
+
+// run runs the task of the job and records the run in the run history. The run is skipped while the circuit breaker is open.
+// The task receives the record of the run to add the details of the run, such as the exported data.
+// The error of the last attempt is returned if the run gives up.
+func (j *supervisedJob) run(ctx context.Context, task func(ctx context.Context, run *internal.RunRecord) error) error {
+
+	run := &internal.RunRecord{
+		ID:      uuid.NewString(),
+		Job:     j.name,
+		Account: j.account,
+		Start:   time.Now(),
+	}
+	defer j.record(run)
+
+	// The records logged during the run carry the job, the account, and the run ID
+	ctx = internal.WithLogAttrs(ctx, slog.String("job", j.name), slog.String("account", j.account), slog.String("run_id", run.ID))
+
+	if j.breaker != nil && !j.breaker.allow() {
+		slog.WarnContext(ctx, "circuit breaker is open. Job run skipped", "job", j.name, "account", j.account, "retry_after", j.breaker.retryAfter().Format(time.RFC3339))
+		run.Status = internal.RunStatusSkipped
+		return nil
+	}
+
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = j.backoff
+	bo.Multiplier = 2
+	bo.MaxInterval = 16 * j.backoff
+	bo.MaxElapsedTime = 0
+
+	op := func() error {
+		run.Attempts++
+		return task(ctx, run)
+	}
+
+	notify := func(err error, wait time.Duration) {
+		slog.WarnContext(ctx, "job run failed. Retrying", "job", j.name, "account", j.account, "attempt", run.Attempts, "max_attempts", j.maxAttempts, "error", err, "retry_in", wait)
+	}
+
+	err := backoff.RetryNotify(op, backoff.WithContext(backoff.WithMaxRetries(bo, uint64(j.maxAttempts-1)), ctx), notify)
+	if err == nil {
+		run.Status = internal.RunStatusSuccess
+		j.succeeded(ctx, run)
+		return nil
+	}
+
+	run.Status = internal.RunStatusFailed
+	run.Error = err.Error()
+	j.failed(ctx, run, err)
+
+	return err
+}
+
+// record appends the run to the run history.
+func (j *supervisedJob) record(run *internal.RunRecord) {
+
+	run.End = time.Now()
+
+	err := j.history.Append(*run)
+	if err != nil {
+		slog.Error("unable to record the run in the run history", "job", j.name, "account", j.account, "run_id", run.ID, "error", err)
+	}
+}
+
+// succeeded closes the circuit breaker. A notification is sent if the job recovered from an open circuit breaker.
+func (j *supervisedJob) succeeded(ctx context.Context, run *internal.RunRecord) {
+
+	if j.breaker == nil {
+		return
+	}
+
+	failures, recovered := j.breaker.success()
+	if !recovered {
+		return
+	}
+
+	slog.Info("job recovered. Circuit breaker closed", "job", j.name, "account", j.account, "run_id", run.ID, "failed_runs", failures)
+	j.publish(run, newJobMessage(ctx, j.name, internal.EventSuccess, fmt.Sprintf("The job recovered after %d failed runs.", failures), nil))
+}
+
+// failed sends an error notification, records the failure in the circuit breaker, and takes the give-up action.
+func (j *supervisedJob) failed(ctx context.Context, run *internal.RunRecord, err error) {
+
+	slog.Error("job run gave up", "job", j.name, "account", j.account, "run_id", run.ID, "attempts", run.Attempts, "error", err)
+	j.publish(run, newJobMessage(ctx, j.name, internal.EventErrors, j.failureMessage, err))
+
+	if j.breaker != nil && j.breaker.failure() {
+		retryAfter := j.breaker.retryAfter()
+		slog.Error("circuit breaker opened. Job runs are skipped until the cooldown elapses", "job", j.name, "account", j.account, "retry_after", retryAfter.Format(time.RFC3339))
+		j.publish(run, newJobMessage(ctx, j.name, internal.EventErrors, fmt.Sprintf("The job failed repeatedly and is paused until %s.", retryAfter.Format(time.RFC3339)), err))
+	}
+
+	if j.giveUp == giveUpExit && j.exit != nil {
+		j.exit(fmt.Errorf("job %s gave up after %d attempts: %w", j.name, run.Attempts, err))
+	}
+}
+
+// publish sends the notification of the job and records the result in the run.
+func (j *supervisedJob) publish(run *internal.RunRecord, msg notifications.Message) {
+
+	if j.notify == nil {
+		return
+	}
+
+	err := publishMessage(j.client, j.notify, msg)
+	if err != nil {
+		slog.Error("unable to send notification", "error", err)
+	}
+
+	run.AddNotification(msg.Event, err)
+}
+
+// circuitBreaker counts the consecutive failed runs of a job. The breaker opens when the failure threshold is reached and
+// skips the runs of the job until the cooldown elapses. The next run after the cooldown is a trial: a success closes the breaker and a failure opens it again.
+type circuitBreaker struct {
+	// threshold is the number of consecutive failed runs that open the breaker.
+	threshold int
+	// cooldown is the time the breaker stays open.
+	cooldown time.Duration
+	// now returns the current time.
+	now func() time.Time
+
+	// mu protects failures and openedAt.
+	mu sync.Mutex
+	// failures is the number of consecutive failed runs.
+	failures int
+	// openedAt is the time the breaker opened. Zero if the breaker is closed.
+	openedAt time.Time
+}
+
+// newCircuitBreaker returns a circuit breaker using the configuration. Nil is returned if the circuit breaker is disabled.
+func newCircuitBreaker(cfg internal.CircuitBreaker) *circuitBreaker {
+
+	if cfg.FailureThreshold < 0 {
+		return nil
+	}
+
+	b := &circuitBreaker{
+		threshold: internal.DEFAULT_CIRCUIT_BREAKER_THRESHOLD,
+		cooldown:  internal.DEFAULT_CIRCUIT_BREAKER_COOLDOWN,
+		now:       time.Now,
+	}
+
+	if cfg.FailureThreshold > 0 {
+		b.threshold = cfg.FailureThreshold
+	}
+
+	if cfg.Cooldown > 0 {
+		b.cooldown = time.Duration(cfg.Cooldown) * time.Minute
+	}
+
+	return b
+}
+
+// allow returns true if the breaker is closed or if the cooldown of the open breaker elapsed.
+func (b *circuitBreaker) allow() bool {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.openedAt.IsZero() || b.now().Sub(b.openedAt) >= b.cooldown
+}
+
+// retryAfter returns the time the open breaker allows the next run.
+func (b *circuitBreaker) retryAfter() time.Time {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.openedAt.Add(b.cooldown)
+}
+
+// success closes the breaker. The number of consecutive failed runs is returned with true if the breaker was open.
+func (b *circuitBreaker) success() (int, bool) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failures := b.failures
+	recovered := !b.openedAt.IsZero()
+
+	b.failures = 0
+	b.openedAt = time.Time{}
+
+	return failures, recovered
+}
+
+// restore copies the consecutive failed runs and the opening time of the previous breaker, so that replacing a job does not close its open breaker.
+func (b *circuitBreaker) restore(previous *circuitBreaker) {
+
+	previous.mu.Lock()
+	failures, openedAt := previous.failures, previous.openedAt
+	previous.mu.Unlock()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = failures
+	b.openedAt = openedAt
+}
+
+// failure records a failed run. True is returned if the failure opened the breaker.
+func (b *circuitBreaker) failure() bool {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+
+	if !b.openedAt.IsZero() || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,274 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+)
+
+func TestNewSupervisedJob(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		policy      internal.RetryPolicy
+		breaker     internal.CircuitBreaker
+		maxAttempts int
+		backoff     time.Duration
+		giveUp      string
+		threshold   int
+		cooldown    time.Duration
+		noBreaker   bool
+	}{
+		{
+			name:        "defaults",
+			maxAttempts: internal.DEFAULT_JOB_MAX_ATTEMPTS,
+			backoff:     internal.DEFAULT_JOB_RETRY_BACKOFF,
+			giveUp:      giveUpSkip,
+			threshold:   internal.DEFAULT_CIRCUIT_BREAKER_THRESHOLD,
+			cooldown:    internal.DEFAULT_CIRCUIT_BREAKER_COOLDOWN,
+		},
+		{
+			name:        "custom",
+			policy:      internal.RetryPolicy{MaxAttempts: 5, Backoff: 10, GiveUp: giveUpExit},
+			breaker:     internal.CircuitBreaker{FailureThreshold: 2, Cooldown: 30},
+			maxAttempts: 5,
+			backoff:     10 * time.Second,
+			giveUp:      giveUpExit,
+			threshold:   2,
+			cooldown:    30 * time.Minute,
+		},
+		{
+			name:        "circuit breaker disabled",
+			breaker:     internal.CircuitBreaker{FailureThreshold: -1},
+			maxAttempts: internal.DEFAULT_JOB_MAX_ATTEMPTS,
+			backoff:     internal.DEFAULT_JOB_RETRY_BACKOFF,
+			giveUp:      giveUpSkip,
+			noBreaker:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			job := newSupervisedJob("job", "", "Error running the server job.", tc.policy, tc.breaker, &http.Client{}, nil, nil, nil)
+
+			if job.maxAttempts != tc.maxAttempts || job.backoff != tc.backoff || job.giveUp != tc.giveUp {
+				t.Errorf("unexpected retry policy: attempts %d, backoff %s, give up %s", job.maxAttempts, job.backoff, job.giveUp)
+			}
+
+			if tc.noBreaker {
+				if job.breaker != nil {
+					t.Error("expected the circuit breaker to be disabled")
+				}
+				return
+			}
+
+			if job.breaker == nil || job.breaker.threshold != tc.threshold || job.breaker.cooldown != tc.cooldown {
+				t.Errorf("unexpected circuit breaker: %+v", job.breaker)
+			}
+		})
+	}
+}
+
+func TestSupervisedJobRun(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		failures      int
+		giveUp        string
+		wantErr       bool
+		attempts      int
+		notifications int
+		exited        bool
+	}{
+		{name: "first attempt", failures: 0, giveUp: giveUpSkip, attempts: 1},
+		{name: "retried", failures: 2, giveUp: giveUpSkip, attempts: 3},
+		{name: "skip after the retries", failures: 5, giveUp: giveUpSkip, wantErr: true, attempts: 3, notifications: 1},
+		{name: "exit after the retries", failures: 5, giveUp: giveUpExit, wantErr: true, attempts: 3, notifications: 1, exited: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			notify := &mockMessagePublisher{}
+			var exitErr error
+
+			job := newSupervisedJob("mywhoop_data_collection_job", "", "Error running the server job.", internal.RetryPolicy{GiveUp: tc.giveUp}, internal.CircuitBreaker{FailureThreshold: -1}, &http.Client{}, notify, func(err error) { exitErr = err }, nil)
+			job.backoff = time.Millisecond
+
+			attempts := 0
+			err := job.run(context.Background(), func(ctx context.Context, run *internal.RunRecord) error {
+				attempts++
+				if attempts <= tc.failures {
+					return errors.New("failed to get data from the Whoop API")
+				}
+				return nil
+			})
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("unexpected error: %v", err)
+			}
+
+			if attempts != tc.attempts {
+				t.Errorf("expected %d attempts, got %d", tc.attempts, attempts)
+			}
+
+			if len(notify.structured) != tc.notifications {
+				t.Fatalf("expected %d notifications, got %d", tc.notifications, len(notify.structured))
+			}
+
+			if tc.notifications > 0 {
+				msg := notify.structured[0]
+				if msg.Event != internal.EventErrors.String() || msg.Job != "mywhoop_data_collection_job" || msg.Error != "failed to get data from the Whoop API" {
+					t.Errorf("unexpected notification: %+v", msg)
+				}
+			}
+
+			if (exitErr != nil) != tc.exited {
+				t.Errorf("unexpected exit: %v", exitErr)
+			}
+		})
+	}
+}
+
+func TestSupervisedJobCircuitBreaker(t *testing.T) {
+
+	notify := &mockMessagePublisher{}
+	job := newSupervisedJob("mywhoop_token_refresh_job", "", "Error running the token refresh job.", internal.RetryPolicy{MaxAttempts: 1}, internal.CircuitBreaker{FailureThreshold: 2, Cooldown: 60}, &http.Client{}, notify, nil, nil)
+
+	now := time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)
+	job.breaker.now = func() time.Time { return now }
+
+	runs := 0
+	fail := true
+	task := func(ctx context.Context, run *internal.RunRecord) error {
+		runs++
+		if fail {
+			return errors.New("unable to refresh the token")
+		}
+		return nil
+	}
+
+	for i := 0; i < 3; i++ {
+		_ = job.run(context.Background(), task)
+	}
+
+	if runs != 2 {
+		t.Fatalf("expected the circuit breaker to skip the third run, got %d runs", runs)
+	}
+
+	// Two failure notifications and one circuit breaker notification
+	if len(notify.structured) != 3 {
+		t.Fatalf("expected 3 notifications, got %d", len(notify.structured))
+	}
+
+	// A failed trial run after the cooldown opens the breaker again
+	now = now.Add(time.Hour)
+	_ = job.run(context.Background(), task)
+	_ = job.run(context.Background(), task)
+
+	if runs != 3 {
+		t.Fatalf("expected a single trial run after the cooldown, got %d runs", runs)
+	}
+
+	// A successful trial run closes the breaker
+	now = now.Add(time.Hour)
+	fail = false
+	err := job.run(context.Background(), task)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	last := notify.structured[len(notify.structured)-1]
+	if last.Event != internal.EventSuccess.String() {
+		t.Errorf("expected a recovery notification, got %+v", last)
+	}
+
+	if !job.breaker.allow() {
+		t.Error("expected the circuit breaker to be closed")
+	}
+}
+
+func TestDownloadWhoopDataError(t *testing.T) {
+
+	cfg := internal.ConfigurationData{
+		Credentials: internal.Credentials{
+			CredentialsFile: filepath.Join(t.TempDir(), "token.json"),
+		},
+	}
+
+	notify := &mockMessagePublisher{}
+
+	err := downloadWhoopData(context.Background(), "mywhoop_data_collection_job", cfg, &http.Client{}, &mockLocatorExport{}, notify, internal.ServerJob{}, internal.Last24Hours(time.Now()), &internal.RunRecord{})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(notify.structured) != 0 {
+		t.Errorf("expected the supervisor to send the error notification, got %d notifications", len(notify.structured))
+	}
+}
+
+func TestServerRuntimeExit(t *testing.T) {
+
+	rt := &serverRuntime{fatal: make(chan error, 1)}
+
+	rt.exit(errors.New("first"))
+	rt.exit(errors.New("second"))
+
+	err := <-rt.fatal
+	if err.Error() != "first" {
+		t.Errorf("expected the first error, got %v", err)
+	}
+
+	// A runtime without a fatal channel ignores the exit requests
+	(&serverRuntime{}).exit(errors.New("ignored"))
+}
+
+func TestSupervisedJobHistory(t *testing.T) {
+
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+	notify := &mockMessagePublisher{}
+
+	job := newSupervisedJob("mywhoop_data_collection_job", "alice", "Error running the server job.", internal.RetryPolicy{MaxAttempts: 2}, internal.CircuitBreaker{FailureThreshold: 1, Cooldown: 60}, &http.Client{}, notify, nil, internal.NewHistory(internal.HistoryConfig{File: filePath}))
+	job.backoff = time.Millisecond
+
+	_ = job.run(context.Background(), func(ctx context.Context, run *internal.RunRecord) error {
+		run.Records = map[string]int{"sleep": 1}
+		return errors.New("failed to export data")
+	})
+
+	_ = job.run(context.Background(), func(ctx context.Context, run *internal.RunRecord) error {
+		return nil
+	})
+
+	records, err := internal.ReadHistory(filePath)
+	if err != nil {
+		t.Fatalf("unable to read the history: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(records))
+	}
+
+	failed := records[0]
+	if failed.Status != internal.RunStatusFailed || failed.Attempts != 2 || failed.Account != "alice" || failed.Error != "failed to export data" || failed.Records["sleep"] != 1 || failed.ID == "" {
+		t.Errorf("unexpected failed run %+v", failed)
+	}
+
+	// The error notification and the circuit breaker notification
+	if len(failed.Notifications) != 2 {
+		t.Errorf("expected 2 notifications, got %+v", failed.Notifications)
+	}
+
+	skipped := records[1]
+	if skipped.Status != internal.RunStatusSkipped || skipped.Attempts != 0 {
+		t.Errorf("unexpected skipped run %+v", skipped)
+	}
+}
@@ -8,11 +8,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"slices"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
@@ -72,7 +73,7 @@ func NewAwsS3Export(region, bucket, profile string, client *http.Client, f *File
 
 	creds, err := cfg.Credentials.Retrieve(context.TODO())
 	if err != nil {
-		slog.Debug("error retrieving aws credentials", "error", err)
+		logger().Debug("error retrieving aws credentials", "error", err)
 		return nil, errors.New("ERROR RETRIEVING AWS CREDENTIALS")
 	}
 
@@ -145,9 +146,38 @@ func (f *AWS_S3) Export(data []byte) error {
 		}
 	}
 
+	f.location = objectURL(f.Bucket, f.Region, fileName)
+
 	return nil
 }
 
+// Location returns the URL of the last exported S3 object. An empty string is returned if no data has been exported.
+func (f *AWS_S3) Location() string {
+	return f.location
+}
+
+// SetDate sets the date used to name the data exported in server mode, such as the date of a missed run. A zero date restores the current date.
+func (f *AWS_S3) SetDate(date time.Time) {
+	f.FileConfig.date = date
+}
+
+// SetDateLayout sets the layout of the date used to name the data exported in server mode, such as 2006_01_02_1504 for jobs running several times a day. An empty layout restores the default layout.
+func (f *AWS_S3) SetDateLayout(layout string) {
+	f.FileConfig.dateLayout = layout
+}
+
+// objectURL returns the virtual-hosted-style URL of the S3 object.
+func objectURL(bucket, region, key string) string {
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   bucket + ".s3." + region + ".amazonaws.com",
+		Path:   "/" + key,
+	}
+
+	return u.String()
+}
+
 // CleanUp cleans up the AWS S3 export and any resources
 func (f *AWS_S3) CleanUp() error {
 	return nil
@@ -198,14 +228,14 @@ func generateObjectKey(cfg FileExport) string {
 	if cfg.ServerMode {
 
 		if cfg.FileNamePrefix != "" && cfg.FileName != "" {
-			return cfg.FileNamePrefix + "_" + cfg.FileName + "_" + getCurrentDate() + "." + cfg.FileType
+			return cfg.FileNamePrefix + "_" + cfg.FileName + "_" + exportDate(cfg) + "." + cfg.FileType
 		}
 
 		if cfg.FileName != "" {
-			return cfg.FileName + "_" + getCurrentDate() + "." + cfg.FileType
+			return cfg.FileName + "_" + exportDate(cfg) + "." + cfg.FileType
 		}
 
-		return getCurrentDate() + "." + cfg.FileType
+		return exportDate(cfg) + "." + cfg.FileType
 	}
 
 	if cfg.FileNamePrefix != "" {
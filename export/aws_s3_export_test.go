@@ -789,3 +789,38 @@ func TestUploadCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestObjectURL(t *testing.T) {
+
+	tests := []struct {
+		description string
+		bucket      string
+		region      string
+		key         string
+		expected    string
+	}{
+		{
+			description: "Object key",
+			bucket:      "mywhoop",
+			region:      "us-east-1",
+			key:         "user_2024-07-31.json",
+			expected:    "https://mywhoop.s3.us-east-1.amazonaws.com/user_2024-07-31.json",
+		},
+		{
+			description: "Object key with a space",
+			bucket:      "mywhoop",
+			region:      "eu-west-1",
+			key:         "my data.xlsx",
+			expected:    "https://mywhoop.s3.eu-west-1.amazonaws.com/my%20data.xlsx",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			got := objectURL(tc.bucket, tc.region, tc.key)
+			if got != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"os"
 	"path"
+	"time"
 )
 
 // Setup sets up the file export and any resources required
@@ -33,7 +34,7 @@ func (f *FileExport) Export(data []byte) error {
 
 	currentDir, err := os.Getwd()
 	if err != nil {
-		slog.Error("unable to get current directory", "error", err)
+		f.logger().Error("unable to get current directory", "error", err)
 		return err
 	}
 
@@ -52,23 +53,55 @@ func (f *FileExport) Export(data []byte) error {
 	// write the data to a file in the data folder in the current directory
 	err = writeToFile(*f, data)
 	if err != nil {
-		slog.Error("unable to write to  JSON file", "error", err)
+		f.logger().Error("unable to write to  JSON file", "error", err)
 		return err
 	}
 
+	f.location = path.Join(f.FilePath, generateName(*f))
+
 	return nil
 
 }
 
+// Location returns the path of the last exported file. An empty string is returned if no data has been exported.
+func (f *FileExport) Location() string {
+	return f.location
+}
+
+// SetDate sets the date used to name the data exported in server mode, such as the date of a missed run. A zero date restores the current date.
+func (f *FileExport) SetDate(date time.Time) {
+	f.date = date
+}
+
+// SetDateLayout sets the layout of the date used to name the data exported in server mode, such as 2006_01_02_1504 for jobs running several times a day. An empty layout restores the default layout.
+func (f *FileExport) SetDateLayout(layout string) {
+	f.dateLayout = layout
+}
+
+// SetLogger sets the logger of the exporter, such as the logger of a job run. A nil logger restores the default logger of the exporters.
+func (f *FileExport) SetLogger(logger *slog.Logger) {
+	f.log = logger
+}
+
+// logger returns the logger of the exporter.
+func (f *FileExport) logger() *slog.Logger {
+
+	if f.log != nil {
+		return f.log
+	}
+
+	return logger()
+}
+
 // generateName generates the name of the file to be created
 func generateName(cfg FileExport) string {
 
 	if cfg.ServerMode {
 
 		if cfg.FileNamePrefix != "" {
-			return cfg.FileNamePrefix + "_" + cfg.FileName + "_" + getCurrentDate() + "." + cfg.FileType
+			return cfg.FileNamePrefix + "_" + cfg.FileName + "_" + exportDate(cfg) + "." + cfg.FileType
 		}
-		return cfg.FileName + "_" + getCurrentDate() + "." + cfg.FileType
+		return cfg.FileName + "_" + exportDate(cfg) + "." + cfg.FileType
 	}
 
 	if cfg.FileNamePrefix != "" {
@@ -91,17 +124,17 @@ func writeToFile(cfg FileExport, data []byte) error {
 		if os.IsNotExist(err) {
 			err := os.MkdirAll(cfg.FilePath, 0755)
 			if err != nil {
-				slog.Error("unable to create data folder", "error", err)
+				cfg.logger().Error("unable to create data folder", "error", err)
 				return err
 			}
 		}
 		// Remove identical file if it exists to avoid conflicts
 	} else {
 		if _, err := os.Stat(path.Join(cfg.FilePath, fileName)); err == nil {
-			slog.Info("file already exists, removing it", "file", path.Join(cfg.FilePath, fileName))
+			cfg.logger().Info("file already exists, removing it", "file", path.Join(cfg.FilePath, fileName))
 			err := os.Remove(path.Join(cfg.FilePath, fileName))
 			if err != nil {
-				slog.Error("unable to remove file", "file", path.Join(cfg.FilePath, fileName), "error", err)
+				cfg.logger().Error("unable to remove file", "file", path.Join(cfg.FilePath, fileName), "error", err)
 				return err
 			}
 		}
@@ -110,7 +143,7 @@ func writeToFile(cfg FileExport, data []byte) error {
 
 	f, err := os.Create(path.Join(cfg.FilePath, fileName))
 	if err != nil {
-		slog.Error("unable to create file", "error", err)
+		cfg.logger().Error("unable to create file", "error", err)
 		return err
 	}
 
@@ -120,11 +153,11 @@ func writeToFile(cfg FileExport, data []byte) error {
 
 	_, err = f.WriteString(dataPretty)
 	if err != nil {
-		slog.Error("unable to write the content to the file", "error", err)
+		cfg.logger().Error("unable to write the content to the file", "error", err)
 		return err
 	}
 
-	slog.Info("data written to file", "file", path.Join(cfg.FilePath, fileName))
+	cfg.logger().Info("data written to file", "file", path.Join(cfg.FilePath, fileName))
 
 	return nil
 }
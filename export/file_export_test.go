@@ -11,6 +11,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 type User struct {
@@ -166,6 +167,16 @@ func TestGenerateName(t *testing.T) {
 			},
 			want: fmt.Sprintf("test_user_%s.xlsx", getCurrentDate()),
 		},
+		{
+			description: "Test case 7: Server mode enabled with a set date",
+			file: FileExport{
+				FileName:   "user",
+				FileType:   "json",
+				ServerMode: true,
+				date:       time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC),
+			},
+			want: "user_2024_06_01.json",
+		},
 	}
 
 	for index, tc := range tests {
@@ -178,6 +189,27 @@ func TestGenerateName(t *testing.T) {
 
 }
 
+func TestFileExportSetDate(t *testing.T) {
+
+	f := NewFileExport("", "json", "user", "", true)
+
+	f.SetDate(time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC))
+	if got := generateName(*f); got != "user_2024_06_01.json" {
+		t.Errorf("Expected user_2024_06_01.json, got: %s", got)
+	}
+
+	f.SetDate(time.Time{})
+	if got := generateName(*f); got != fmt.Sprintf("user_%s.json", getCurrentDate()) {
+		t.Errorf("Expected the current date after the date is reset, got: %s", got)
+	}
+
+	f.SetDate(time.Date(2024, 6, 1, 13, 5, 0, 0, time.UTC))
+	f.SetDateLayout("2006_01_02_1504")
+	if got := generateName(*f); got != "user_2024_06_01_1305.json" {
+		t.Errorf("Expected user_2024_06_01_1305.json, got: %s", got)
+	}
+}
+
 func TestSetup(t *testing.T) {
 
 	exp := &FileExport{
@@ -240,6 +272,10 @@ func TestExportData(t *testing.T) {
 		t.Errorf("Expected file content: %s, got: %s", string(expectedData), string(data))
 	}
 
+	if exp.Location() != "tests/data/user.json" {
+		t.Errorf("Expected location: %s, got: %s", "tests/data/user.json", exp.Location())
+	}
+
 	// Clean up the tests directory
 	err = os.RemoveAll("tests/")
 	if err != nil {
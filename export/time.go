@@ -12,3 +12,23 @@ func getCurrentDate() string {
 	currentDate := time.Now().Format("2006_01_02")
 	return currentDate
 }
+
+// exportDate returns the date used to name the data exported in server mode. The current date is used if no date is set.
+func exportDate(cfg FileExport) string {
+
+	if cfg.date.IsZero() && cfg.dateLayout == "" {
+		return getCurrentDate()
+	}
+
+	layout := cfg.dateLayout
+	if layout == "" {
+		layout = "2006_01_02"
+	}
+
+	date := cfg.date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	return date.Format(layout)
+}
@@ -4,30 +4,65 @@
 // A collection of data exporter that can be used to remotely ship data to a variety of destinations.
 package export
 
-import "github.com/aws/aws-sdk-go-v2/service/s3"
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
 
 type FileExport struct {
 	// FilePath is the path to the file to be created. If not provided, the default path is the data folder in the current directory.
-	FilePath string `yaml:"filePath"`
+	FilePath string `yaml:"filePath" json:"filePath"`
 	// FileType is the type of file to be created. If not provided, the default type is json.
-	FileType string `yaml:"fileType"`
+	FileType string `yaml:"fileType" json:"fileType"`
 	// FileName is the name of the file to be created. If not provided, the default name is user.
-	FileName string `yaml:"fileName"`
+	FileName string `yaml:"fileName" json:"fileName"`
 	// FileNamePrefix is used to prefix the file name. If not provided, the default prefix is empty.
-	FileNamePrefix string `yaml:"fileNamePrefix"`
+	FileNamePrefix string `yaml:"fileNamePrefix" json:"fileNamePrefix"`
 	// ServerMode is used to determine if the file is being exported in server mode. This ensures the file name is unique and contains a timestamp.
-	ServerMode bool `yaml:"serverMode"`
+	ServerMode bool `yaml:"serverMode" json:"serverMode"`
+	// location is the path of the last exported file.
+	location string
+	// date is the date used to name the data exported in server mode. The current date is used if the date is zero.
+	date time.Time
+	// dateLayout is the layout of the date used to name the data exported in server mode. The default layout is used if empty.
+	dateLayout string
+	// log is the logger of the exporter, such as the logger of a job run. The default logger of the exporters is used if nil.
+	log *slog.Logger
 }
 
 type AWS_S3 struct {
 	// The AWS region the S3 bucket is located in.
-	Region string `yaml:"region"`
+	Region string `yaml:"region" json:"region"`
 	// Bucket is the name of the S3 bucket.
-	Bucket string `yaml:"bucket"`
+	Bucket string `yaml:"bucket" json:"bucket"`
 	// S3Client is the S3 client.
-	S3Client *s3.Client `yaml:"-"`
+	S3Client *s3.Client `yaml:"-" json:"-"`
 	// FileConfig contains the file configuration.
-	FileConfig FileExport `yaml:"fileConfig"`
+	FileConfig FileExport `yaml:"fileConfig" json:"fileConfig"`
 	// Profile is AWS profile to use.
-	Profile string `yaml:"profile"`
+	Profile string `yaml:"profile" json:"profile"`
+	// location is the URL of the last exported S3 object.
+	location string
+}
+
+// defaultLogger is the logger of the exporters without a logger of their own.
+var defaultLogger atomic.Pointer[slog.Logger]
+
+// SetDefaultLogger sets the logger of the exporters without a logger of their own, such as a logger labelled with the export component.
+// The default logger of the slog package is used if nil.
+func SetDefaultLogger(logger *slog.Logger) {
+	defaultLogger.Store(logger)
+}
+
+// logger returns the default logger of the exporters.
+func logger() *slog.Logger {
+
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+
+	return slog.Default()
 }
@@ -0,0 +1,131 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// accountNameRegex is the allowed format of an account name.
+var accountNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// AccountCredentialsFile returns the default credentials file for the account with the provided name.
+// The default credentials file is used when an account does not specify a credentials file.
+func AccountCredentialsFile(name string) string {
+
+	if name == "" {
+		return DEFAULT_CREDENTIALS_FILE
+	}
+
+	ext := filepath.Ext(DEFAULT_CREDENTIALS_FILE)
+	return strings.TrimSuffix(DEFAULT_CREDENTIALS_FILE, ext) + "_" + name + ext
+}
+
+// ResolveAccounts returns the accounts to manage. If no accounts are configured, a single unnamed account
+// using the top-level configuration is returned.
+func (c ConfigurationData) ResolveAccounts() []Account {
+
+	if len(c.Accounts) == 0 {
+		return []Account{
+			{
+				Credentials: c.Credentials,
+			},
+		}
+	}
+
+	return c.Accounts
+}
+
+// FindAccount returns the account with the provided name. If the account is not configured, an account
+// using the default account credentials file is returned together with false.
+func (c ConfigurationData) FindAccount(name string) (Account, bool) {
+
+	for _, account := range c.Accounts {
+		if account.Name == name {
+			return account, true
+		}
+	}
+
+	return Account{
+		Name: name,
+	}, false
+}
+
+// ForAccount returns the configuration of the provided account. The account credentials, export, and notification
+// settings take precedence over the top-level settings. The exported files of named accounts are prefixed with the account name.
+func (c ConfigurationData) ForAccount(account Account) ConfigurationData {
+
+	cfg := c
+	cfg.Accounts = nil
+
+	if account.Name == "" {
+		return cfg
+	}
+
+	cfg.Credentials = account.Credentials
+	if cfg.Credentials.CredentialsFile == "" {
+		cfg.Credentials.CredentialsFile = AccountCredentialsFile(account.Name)
+	}
+
+	// Accounts usually share the same Whoop application
+	if cfg.Credentials.ClientID == "" {
+		cfg.Credentials.ClientID = c.Credentials.ClientID
+	}
+
+	if cfg.Credentials.ClientSecret == "" {
+		cfg.Credentials.ClientSecret = c.Credentials.ClientSecret
+	}
+
+	if account.Export != nil {
+		cfg.Export = *account.Export
+	}
+
+	if account.Notification != nil {
+		cfg.Notification = *account.Notification
+	}
+
+	cfg.Export.FileExport.FileNamePrefix = accountPrefix(account.Name, cfg.Export.FileExport.FileNamePrefix)
+	cfg.Export.AWSS3.FileConfig.FileNamePrefix = accountPrefix(account.Name, cfg.Export.AWSS3.FileConfig.FileNamePrefix)
+
+	return cfg
+}
+
+// validateAccounts validates the account names and ensures no two accounts share a credentials file.
+func validateAccounts(config ConfigurationData) error {
+
+	credentialFiles := make(map[string]string)
+
+	for _, account := range config.Accounts {
+
+		if !accountNameRegex.MatchString(account.Name) {
+			return fmt.Errorf("invalid account name %q. Only letters, numbers, dashes and underscores are allowed", account.Name)
+		}
+
+		file := account.Credentials.CredentialsFile
+		if file == "" {
+			file = AccountCredentialsFile(account.Name)
+		}
+		file = filepath.Clean(file)
+
+		if existing, ok := credentialFiles[file]; ok {
+			return fmt.Errorf("the accounts %q and %q use the same credentials file %s", existing, account.Name, file)
+		}
+		credentialFiles[file] = account.Name
+	}
+
+	return nil
+}
+
+// accountPrefix returns the file name prefix labelled with the account name.
+func accountPrefix(name, prefix string) string {
+
+	if prefix == "" {
+		return name
+	}
+
+	return name + "_" + prefix
+}
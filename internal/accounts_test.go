@@ -0,0 +1,228 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/karl-cardenas-coding/mywhoop/export"
+	"github.com/karl-cardenas-coding/mywhoop/notifications"
+)
+
+func TestAccountCredentialsFile(t *testing.T) {
+
+	tests := []struct {
+		description string
+		name        string
+		expected    string
+	}{
+		{
+			description: "Test default account",
+			name:        "",
+			expected:    "token.json",
+		},
+		{
+			description: "Test named account",
+			name:        "alice",
+			expected:    "token_alice.json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got := AccountCredentialsFile(test.name)
+			if got != test.expected {
+				t.Errorf("Expected %s but got %s", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestResolveAccounts(t *testing.T) {
+
+	cfg := ConfigurationData{
+		Credentials: Credentials{
+			CredentialsFile: "/opt/mywhoop/token.json",
+		},
+	}
+
+	got := cfg.ResolveAccounts()
+	if len(got) != 1 {
+		t.Fatalf("Expected a single default account but got %d accounts", len(got))
+	}
+
+	if got[0].Name != "" || got[0].Credentials.CredentialsFile != "/opt/mywhoop/token.json" {
+		t.Errorf("Expected the default account to use the top-level credentials but got %v", got[0])
+	}
+
+	cfg.Accounts = []Account{{Name: "alice"}, {Name: "bob"}}
+	got = cfg.ResolveAccounts()
+	if len(got) != 2 {
+		t.Errorf("Expected 2 accounts but got %d", len(got))
+	}
+}
+
+func TestForAccount(t *testing.T) {
+
+	cfg := ConfigurationData{
+		Credentials: Credentials{
+			CredentialsFile: "token.json",
+		},
+		Export: ConfigExport{
+			Method: "file",
+			FileExport: export.FileExport{
+				FilePath:       "data/",
+				FileNamePrefix: "daily",
+			},
+		},
+		Notification: NotificationConfig{
+			Method: "",
+		},
+		Accounts: []Account{
+			{
+				Name: "alice",
+			},
+			{
+				Name: "bob",
+				Credentials: Credentials{
+					CredentialsFile: "/opt/bob/token.json",
+				},
+				Export: &ConfigExport{
+					Method: "s3",
+					AWSS3: export.AWS_S3{
+						Bucket: "bob-bucket",
+					},
+				},
+				Notification: &NotificationConfig{
+					Method: "ntfy",
+					Ntfy: notifications.Ntfy{
+						ServerEndpoint: "https://ntfy.example.com",
+						SubscriptionID: "bob",
+					},
+				},
+			},
+		},
+	}
+
+	alice := cfg.ForAccount(cfg.Accounts[0])
+	if alice.Credentials.CredentialsFile != "token_alice.json" {
+		t.Errorf("Expected token_alice.json but got %s", alice.Credentials.CredentialsFile)
+	}
+
+	if alice.Export.FileExport.FileNamePrefix != "alice_daily" {
+		t.Errorf("Expected alice_daily but got %s", alice.Export.FileExport.FileNamePrefix)
+	}
+
+	if alice.Accounts != nil {
+		t.Errorf("Expected no accounts in the account configuration but got %v", alice.Accounts)
+	}
+
+	bob := cfg.ForAccount(cfg.Accounts[1])
+	if bob.Credentials.CredentialsFile != "/opt/bob/token.json" {
+		t.Errorf("Expected /opt/bob/token.json but got %s", bob.Credentials.CredentialsFile)
+	}
+
+	if bob.Export.Method != "s3" || bob.Export.AWSS3.FileConfig.FileNamePrefix != "bob" {
+		t.Errorf("Expected the s3 export override with the bob prefix but got %v", bob.Export)
+	}
+
+	if bob.Notification.Method != "ntfy" {
+		t.Errorf("Expected the ntfy notification override but got %s", bob.Notification.Method)
+	}
+
+	// The top-level configuration must not be modified
+	if cfg.Export.FileExport.FileNamePrefix != "daily" {
+		t.Errorf("Expected the top-level prefix to remain daily but got %s", cfg.Export.FileExport.FileNamePrefix)
+	}
+
+	defaultAccount := cfg.ForAccount(Account{})
+	if defaultAccount.Credentials.CredentialsFile != "token.json" {
+		t.Errorf("Expected token.json but got %s", defaultAccount.Credentials.CredentialsFile)
+	}
+}
+
+func TestFindAccount(t *testing.T) {
+
+	cfg := ConfigurationData{
+		Accounts: []Account{
+			{
+				Name: "alice",
+				Credentials: Credentials{
+					CredentialsFile: "/opt/alice/token.json",
+				},
+			},
+		},
+	}
+
+	account, ok := cfg.FindAccount("alice")
+	if !ok || account.Credentials.CredentialsFile != "/opt/alice/token.json" {
+		t.Errorf("Expected the alice account but got %v", account)
+	}
+
+	account, ok = cfg.FindAccount("bob")
+	if ok || account.Name != "bob" {
+		t.Errorf("Expected an unconfigured bob account but got %v", account)
+	}
+}
+
+func TestValidateAccounts(t *testing.T) {
+
+	tests := []struct {
+		description   string
+		accounts      []Account
+		errorExpected bool
+	}{
+		{
+			description:   "Test valid accounts",
+			accounts:      []Account{{Name: "alice"}, {Name: "bob-2"}},
+			errorExpected: false,
+		},
+		{
+			description:   "Test invalid account name",
+			accounts:      []Account{{Name: "alice/../bob"}},
+			errorExpected: true,
+		},
+		{
+			description: "Test shared credentials file",
+			accounts: []Account{
+				{Name: "alice", Credentials: Credentials{CredentialsFile: "token.json"}},
+				{Name: "bob", Credentials: Credentials{CredentialsFile: "./token.json"}},
+			},
+			errorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := validateAccounts(ConfigurationData{Accounts: test.accounts})
+			if err != nil && !test.errorExpected {
+				t.Errorf("Expected no error but got %v", err)
+			}
+
+			if err == nil && test.errorExpected {
+				t.Errorf("Expected an error but got none")
+			}
+		})
+	}
+}
+
+func TestForAccountClientCredentials(t *testing.T) {
+
+	cfg := ConfigurationData{
+		Credentials: Credentials{
+			ClientID:     "AAAAAAAAAAAAAAAAAAA",
+			ClientSecret: "env:WHOOP_CLIENT_SECRET",
+		},
+	}
+
+	got := cfg.ForAccount(Account{Name: "alice"})
+	if got.Credentials.ClientID != "AAAAAAAAAAAAAAAAAAA" || got.Credentials.ClientSecret != "env:WHOOP_CLIENT_SECRET" {
+		t.Errorf("Expected the account to inherit the client credentials but got %v", got.Credentials)
+	}
+
+	got = cfg.ForAccount(Account{Name: "bob", Credentials: Credentials{ClientID: "CCCCCCCCCCCCCCCCCCC"}})
+	if got.Credentials.ClientID != "CCCCCCCCCCCCCCCCCCC" {
+		t.Errorf("Expected the account client ID but got %s", got.Credentials.ClientID)
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// alertBaselineDays is the number of days used to compute the HRV and skin temperature baselines.
+	alertBaselineDays = 7
+	// minBaselineRecords is the minimum number of recoveries required to compute a baseline.
+	minBaselineRecords = 3
+)
+
+// Alert is a health alert raised by an alert rule.
+type Alert struct {
+	// Rule is the name of the alert rule, such as recoveryBelow.
+	Rule string
+	// Message describes the alert.
+	Message string
+}
+
+// Enabled returns true if at least one alert rule is configured.
+func (a AlertsConfig) Enabled() bool {
+	return a.RecoveryBelow > 0 || a.HRVDropPercent > 0 || a.SpO2Below > 0 || a.SkinTempDeviation > 0 || a.SleepDebtAbove > 0
+}
+
+// NeedsBaseline returns true if an alert rule compares the data with the recoveries of the previous days.
+func (a AlertsConfig) NeedsBaseline() bool {
+	return a.HRVDropPercent > 0 || a.SkinTempDeviation > 0
+}
+
+// BaselineFilter returns the Whoop API filter used to query the recoveries of the baseline period ending at the time.
+func BaselineFilter(end time.Time) string {
+
+	layout := "2006-01-02T15:04:05.000Z"
+	start := end.Add(-(alertBaselineDays + 1) * 24 * time.Hour)
+
+	return fmt.Sprintf("start=%s&end=%s", start.UTC().Format(layout), end.UTC().Format(layout))
+}
+
+// EvaluateAlerts evaluates the alert rules against the Whoop data of the user and returns the raised alerts.
+// The baseline contains the recoveries of the previous days and is only used by the HRV and skin temperature rules. Those rules are skipped if the baseline contains fewer than three scored recoveries.
+func EvaluateAlerts(cfg AlertsConfig, user User, baseline []RecoveryRecords) []Alert {
+
+	var alerts []Alert
+
+	sleep := latestSleep(user)
+	recovery := latestRecovery(user, sleep)
+
+	if recovery != nil {
+		score := recovery.Score
+
+		if cfg.RecoveryBelow > 0 && score.RecoveryScore < cfg.RecoveryBelow {
+			alerts = append(alerts, Alert{
+				Rule:    "recoveryBelow",
+				Message: fmt.Sprintf("Recovery of %.0f%% is below %.0f%%.", score.RecoveryScore, cfg.RecoveryBelow),
+			})
+		}
+
+		if cfg.SpO2Below > 0 && score.Spo2Percentage > 0 && score.Spo2Percentage < cfg.SpO2Below {
+			alerts = append(alerts, Alert{
+				Rule:    "spo2Below",
+				Message: fmt.Sprintf("SpO2 of %.1f%% is below %.1f%%.", score.Spo2Percentage, cfg.SpO2Below),
+			})
+		}
+
+		hrvBaseline, skinTempBaseline, ok := recoveryBaseline(baseline, *recovery)
+
+		if cfg.HRVDropPercent > 0 && ok && hrvBaseline > 0 {
+			drop := (hrvBaseline - score.HrvRmssdMilli) / hrvBaseline * 100
+			if drop > cfg.HRVDropPercent {
+				alerts = append(alerts, Alert{
+					Rule:    "hrvDropPercent",
+					Message: fmt.Sprintf("HRV of %.1f ms is %.0f%% below the %d-day baseline of %.1f ms.", score.HrvRmssdMilli, drop, alertBaselineDays, hrvBaseline),
+				})
+			}
+		}
+
+		if cfg.SkinTempDeviation > 0 && ok && skinTempBaseline > 0 && score.SkinTempCelsius > 0 {
+			deviation := score.SkinTempCelsius - skinTempBaseline
+			if math.Abs(deviation) > cfg.SkinTempDeviation {
+				alerts = append(alerts, Alert{
+					Rule:    "skinTempDeviation",
+					Message: fmt.Sprintf("Skin temperature of %.1f°C deviates by %+.1f°C from the %d-day baseline of %.1f°C.", score.SkinTempCelsius, deviation, alertBaselineDays, skinTempBaseline),
+				})
+			}
+		}
+	}
+
+	if sleep != nil && cfg.SleepDebtAbove > 0 {
+		debt := milliseconds(sleep.Score.SleepNeeded.NeedFromSleepDebtMilli)
+		limit := time.Duration(cfg.SleepDebtAbove) * time.Minute
+		if debt > limit {
+			alerts = append(alerts, Alert{
+				Rule:    "sleepDebtAbove",
+				Message: fmt.Sprintf("Sleep debt of %s is above %s.", formatDuration(debt), formatDuration(limit)),
+			})
+		}
+	}
+
+	return alerts
+}
+
+// AlertsMessage returns the notification message of the alerts.
+func AlertsMessage(alerts []Alert) string {
+
+	lines := make([]string, 0, len(alerts)+1)
+
+	if len(alerts) == 1 {
+		lines = append(lines, "1 health alert raised by the last data collection.")
+	} else {
+		lines = append(lines, fmt.Sprintf("%d health alerts raised by the last data collection.", len(alerts)))
+	}
+
+	for _, alert := range alerts {
+		lines = append(lines, "- "+alert.Message)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// recoveryBaseline returns the average HRV and skin temperature of the scored recoveries of the baseline, excluding the current recovery.
+// False is returned if the baseline contains fewer than three scored recoveries.
+func recoveryBaseline(baseline []RecoveryRecords, current RecoveryRecords) (float64, float64, bool) {
+
+	var (
+		hrv, skinTemp    float64
+		count, tempCount int
+	)
+
+	for _, record := range baseline {
+		if record.ScoreState != scoreStateScored || record.CycleID == current.CycleID {
+			continue
+		}
+
+		hrv += record.Score.HrvRmssdMilli
+		count++
+
+		if record.Score.SkinTempCelsius > 0 {
+			skinTemp += record.Score.SkinTempCelsius
+			tempCount++
+		}
+	}
+
+	if count < minBaselineRecords {
+		return 0, 0, false
+	}
+
+	if tempCount > 0 {
+		skinTemp = skinTemp / float64(tempCount)
+	}
+
+	return hrv / float64(count), skinTemp, true
+}
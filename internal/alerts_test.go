@@ -0,0 +1,186 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvaluateAlerts(t *testing.T) {
+
+	now := time.Date(2024, 7, 31, 8, 0, 0, 0, time.UTC)
+
+	user := User{
+		SleepCollection: SleepCollection{
+			SleepCollectionRecords: []SleepCollectionRecords{
+				{
+					ID:         10,
+					End:        now,
+					ScoreState: "SCORED",
+					Score: Score{
+						SleepNeeded: SleepNeeded{NeedFromSleepDebtMilli: 90 * 60000},
+					},
+				},
+			},
+		},
+		RecoveryCollection: RecoveryCollection{
+			RecoveryRecords: []RecoveryRecords{
+				{
+					CycleID:    7,
+					SleepID:    10,
+					CreatedAt:  now,
+					ScoreState: "SCORED",
+					Score: RecoveryScore{
+						RecoveryScore:   28,
+						HrvRmssdMilli:   40,
+						Spo2Percentage:  93.5,
+						SkinTempCelsius: 34.9,
+					},
+				},
+			},
+		},
+	}
+
+	baseline := []RecoveryRecords{
+		{CycleID: 4, ScoreState: "SCORED", Score: RecoveryScore{HrvRmssdMilli: 60, SkinTempCelsius: 33.8}},
+		{CycleID: 5, ScoreState: "SCORED", Score: RecoveryScore{HrvRmssdMilli: 62, SkinTempCelsius: 34}},
+		{CycleID: 6, ScoreState: "SCORED", Score: RecoveryScore{HrvRmssdMilli: 58, SkinTempCelsius: 34.2}},
+		// The current recovery and unscored recoveries are excluded from the baseline.
+		{CycleID: 7, ScoreState: "SCORED", Score: RecoveryScore{HrvRmssdMilli: 40, SkinTempCelsius: 34.9}},
+		{CycleID: 3, ScoreState: "PENDING_SCORE"},
+	}
+
+	tests := []struct {
+		description string
+		cfg         AlertsConfig
+		baseline    []RecoveryRecords
+		expected    []string
+	}{
+		{
+			description: "No rules",
+			cfg:         AlertsConfig{},
+			baseline:    baseline,
+			expected:    nil,
+		},
+		{
+			description: "Every rule raises an alert",
+			cfg: AlertsConfig{
+				RecoveryBelow:     33,
+				HRVDropPercent:    20,
+				SpO2Below:         95,
+				SkinTempDeviation: 0.5,
+				SleepDebtAbove:    60,
+			},
+			baseline: baseline,
+			expected: []string{"recoveryBelow", "spo2Below", "hrvDropPercent", "skinTempDeviation", "sleepDebtAbove"},
+		},
+		{
+			description: "Thresholds not reached",
+			cfg: AlertsConfig{
+				RecoveryBelow:     25,
+				HRVDropPercent:    40,
+				SpO2Below:         90,
+				SkinTempDeviation: 1,
+				SleepDebtAbove:    120,
+			},
+			baseline: baseline,
+			expected: nil,
+		},
+		{
+			description: "Baseline rules are skipped without enough recoveries",
+			cfg: AlertsConfig{
+				HRVDropPercent:    20,
+				SkinTempDeviation: 0.5,
+			},
+			baseline: baseline[:2],
+			expected: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			alerts := EvaluateAlerts(tc.cfg, user, tc.baseline)
+
+			if len(alerts) != len(tc.expected) {
+				t.Fatalf("Expected %d alerts, got %+v", len(tc.expected), alerts)
+			}
+
+			for i, rule := range tc.expected {
+				if alerts[i].Rule != rule {
+					t.Errorf("Expected the %s rule, got %s", rule, alerts[i].Rule)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateAlertsMessages(t *testing.T) {
+
+	user := User{
+		RecoveryCollection: RecoveryCollection{
+			RecoveryRecords: []RecoveryRecords{
+				{CycleID: 2, ScoreState: "SCORED", Score: RecoveryScore{RecoveryScore: 28, HrvRmssdMilli: 45}},
+			},
+		},
+	}
+
+	baseline := []RecoveryRecords{
+		{CycleID: 1, ScoreState: "SCORED", Score: RecoveryScore{HrvRmssdMilli: 60}},
+		{CycleID: 3, ScoreState: "SCORED", Score: RecoveryScore{HrvRmssdMilli: 60}},
+		{CycleID: 4, ScoreState: "SCORED", Score: RecoveryScore{HrvRmssdMilli: 60}},
+	}
+
+	alerts := EvaluateAlerts(AlertsConfig{RecoveryBelow: 33, HRVDropPercent: 10}, user, baseline)
+
+	expected := "2 health alerts raised by the last data collection.\n" +
+		"- Recovery of 28% is below 33%.\n" +
+		"- HRV of 45.0 ms is 25% below the 7-day baseline of 60.0 ms."
+	if got := AlertsMessage(alerts); got != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	single := AlertsMessage(alerts[:1])
+	if !strings.HasPrefix(single, "1 health alert raised") {
+		t.Errorf("Expected a single alert message, got %s", single)
+	}
+}
+
+func TestAlertsConfig(t *testing.T) {
+
+	tests := []struct {
+		description string
+		cfg         AlertsConfig
+		enabled     bool
+		baseline    bool
+	}{
+		{"Disabled", AlertsConfig{}, false, false},
+		{"Recovery rule", AlertsConfig{RecoveryBelow: 30}, true, false},
+		{"HRV rule", AlertsConfig{HRVDropPercent: 20}, true, true},
+		{"Skin temperature rule", AlertsConfig{SkinTempDeviation: 1}, true, true},
+		{"Sleep debt rule", AlertsConfig{SleepDebtAbove: 60}, true, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if tc.cfg.Enabled() != tc.enabled {
+				t.Errorf("Expected enabled %v, got %v", tc.enabled, tc.cfg.Enabled())
+			}
+			if tc.cfg.NeedsBaseline() != tc.baseline {
+				t.Errorf("Expected baseline %v, got %v", tc.baseline, tc.cfg.NeedsBaseline())
+			}
+		})
+	}
+}
+
+func TestBaselineFilter(t *testing.T) {
+
+	end := time.Date(2024, 7, 31, 8, 0, 0, 0, time.UTC)
+
+	expected := "start=2024-07-23T08:00:00.000Z&end=2024-07-31T08:00:00.000Z"
+	if got := BaselineFilter(end); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
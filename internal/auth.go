@@ -90,30 +90,216 @@ func RefreshToken(ctx context.Context, auth AuthRequest) (oauth2.Token, error) {
 		Expiry:       time.Now().Local().Add(time.Second * time.Duration(newAuth.ExpiresIn)),
 	}
 
+	if newAuth.Scope != "" {
+		token = *token.WithExtra(map[string]interface{}{"scope": newAuth.Scope})
+	}
+
 	return token, nil
 
 }
 
-// writeLocalToken creates file containing the Whoop authentication token
-func WriteLocalToken(filePath string, token *oauth2.Token) error {
+// RevokeToken revokes the access granted to MyWhoop by the provided access token.
+// The Whoop API invalidates both the access token and the refresh token.
+func RevokeToken(ctx context.Context, client *http.Client, url, authToken, ua string) error {
+
+	const method = "DELETE"
 
-	f, err := os.Create(filePath)
+	if client == nil {
+		return errors.New("no http client specified for the token revocation request")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
+		LogError(err)
 		return err
 	}
-	defer f.Close()
+	req.Header.Add("Authorization", "Bearer "+authToken)
+	req.Header.Add("User-Agent", ua)
 
-	json, err := json.MarshalIndent(token, " ", " ")
+	response, err := client.Do(req)
 	if err != nil {
+		LogError(err)
 		return err
 	}
-	_, err = f.WriteString(string(json))
+
+	if response == nil {
+		return errors.New("the HTTP request for token revocation returned an empty response struct")
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusOK {
+		return fmt.Errorf("the HTTP request for token revocation returned a non-successful status code. Status Code: %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// storedToken is the representation of the Whoop authentication token in the credentials file.
+// The scopes granted by the user are stored alongside the OAuth2 token values.
+type storedToken struct {
+	oauth2.Token
+	Scope string `json:"scope,omitempty"`
+}
+
+// TokenScopes returns the scopes granted to the token. An empty list is returned if the scopes are unknown.
+func TokenScopes(token oauth2.Token) []string {
+
+	scope, ok := token.Extra("scope").(string)
+	if !ok || scope == "" {
+		return []string{}
+	}
+
+	return strings.Fields(scope)
+}
+
+// ReadTokenScopes returns the scopes stored in the credentials file. Credentials files created by older versions of MyWhoop do not contain scopes and return an empty list.
+func ReadTokenScopes(filePath string) ([]string, error) {
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return []string{}, err
+	}
+
+	var stored storedToken
+	err = json.Unmarshal(content, &stored)
+	if err != nil {
+		return []string{}, err
+	}
+
+	return strings.Fields(stored.Scope), nil
+}
+
+// DeleteLocalToken securely deletes the credentials file. The file content is overwritten with zeros before the file is removed.
+// The credentials file lock is held during the deletion so that a running server never refreshes a token being deleted.
+func DeleteLocalToken(filePath string) error {
+
+	info, err := os.Stat(filePath)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory and not a credentials file", filePath)
+	}
+
+	lock, err := LockFile(context.Background(), filePath)
+	if err != nil {
+		return err
+	}
+
+	defer lock.Unlock()
+
+	return deleteLocalToken(filePath)
+}
+
+// deleteLocalToken overwrites the credentials file with zeros and removes it. The caller must hold the credentials file lock.
+func deleteLocalToken(filePath string) error {
+
+	// The size is read under the lock, as the token may have been refreshed since the file was checked
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filePath, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(make([]byte, info.Size()))
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Sync()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Remove(filePath)
+}
+
+// WriteLocalToken creates file containing the Whoop authentication token.
+// The credentials file lock is held while the file is written so that other MyWhoop processes never observe a stale refresh token.
+func WriteLocalToken(filePath string, token *oauth2.Token) error {
 
+	if token == nil {
+		return errors.New("no token provided")
+	}
+
+	lock, err := LockFile(context.Background(), filePath)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	return writeLocalToken(filePath, token)
+
+}
+
+// writeLocalToken atomically writes the Whoop authentication token to the credentials file. The caller must hold the credentials file lock.
+func writeLocalToken(filePath string, token *oauth2.Token) error {
+
+	stored := storedToken{
+		Token: *token,
+		Scope: strings.Join(TokenScopes(*token), " "),
+	}
+
+	json, err := json.MarshalIndent(stored, " ", " ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filePath, json, 0600)
+}
+
+// RefreshTokenFile refreshes the token stored in the credentials file and writes the new token back to the file.
+// Whoop rotates refresh tokens, so the read-refresh-write sequence is performed while holding the credentials file lock.
+// This allows multiple MyWhoop processes to safely share a credentials file. The AuthToken and RefreshToken values of the
+// provided AuthRequest are replaced with the values read from the credentials file.
+func RefreshTokenFile(ctx context.Context, auth AuthRequest, filePath string) (oauth2.Token, error) {
+
+	lock, err := LockFile(ctx, filePath)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+	defer lock.Unlock()
+
+	currentToken, err := ReadTokenFromFile(filePath)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	if currentToken.RefreshToken == "" {
+		return oauth2.Token{}, errors.New("the credentials file does not contain a refresh token")
+	}
+
+	auth.AuthToken = currentToken.AccessToken
+	auth.RefreshToken = currentToken.RefreshToken
+
+	token, err := RefreshToken(ctx, auth)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	if len(token.AccessToken) < 1 {
+		return oauth2.Token{}, errors.New("no access token")
+	}
+
+	err = writeLocalToken(filePath, &token)
+	if err != nil {
+		return oauth2.Token{}, err
+	}
+
+	return token, nil
 }
 
 // VerifyToken validates that the file containing the Whoop autentication token is valid.
@@ -656,3 +656,244 @@ func TestGenerateStateOauthCookie(t *testing.T) {
 		t.Error("Failed to generate state cookie")
 	}
 }
+
+func TestRevokeToken(t *testing.T) {
+
+	tests := []struct {
+		description   string
+		statusCode    int
+		errorExpected bool
+	}{
+		{
+			description:   "Test Case - 1: Token revoked",
+			statusCode:    http.StatusNoContent,
+			errorExpected: false,
+		},
+		{
+			description:   "Test Case - 2: Unauthorized",
+			statusCode:    http.StatusUnauthorized,
+			errorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Errorf("%s: Expected method DELETE but got %s", test.description, r.Method)
+				}
+				if r.Header.Get("Authorization") != "Bearer testAccessToken" {
+					t.Errorf("%s: Expected the bearer token in the Authorization header but got %s", test.description, r.Header.Get("Authorization"))
+				}
+				w.WriteHeader(test.statusCode)
+			}))
+			defer ts.Close()
+
+			err := RevokeToken(context.Background(), CreateHTTPClient(), ts.URL, "testAccessToken", "mywhoop/test")
+			if err != nil && !test.errorExpected {
+				t.Errorf("%s: Failed to revoke token: %v", test.description, err)
+			}
+
+			if err == nil && test.errorExpected {
+				t.Errorf("%s: Expected an error but got none", test.description)
+			}
+		})
+	}
+
+	err := RevokeToken(context.Background(), nil, DEFAULT_WHOOP_API_REVOKE_ACCESS_URL, "testAccessToken", "mywhoop/test")
+	if err == nil {
+		t.Errorf("Expected an error due to a missing HTTP client but got none")
+	}
+}
+
+func TestTokenScopes(t *testing.T) {
+
+	token := oauth2.Token{
+		AccessToken: "askjdsajklsdlkjfasdk",
+		Expiry:      time.Now().Add(30 * time.Minute),
+	}
+
+	got := TokenScopes(token)
+	if len(got) != 0 {
+		t.Errorf("Expected no scopes but got %v", got)
+	}
+
+	token = *token.WithExtra(map[string]interface{}{"scope": "offline read:profile"})
+	got = TokenScopes(token)
+	if len(got) != 2 || got[0] != "offline" || got[1] != "read:profile" {
+		t.Errorf("Expected the scopes [offline read:profile] but got %v", got)
+	}
+
+	filePath := filepath.Join("../tests/data/", "token.json")
+	err := os.MkdirAll("../tests/data/", 0755)
+	if err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	t.Cleanup(func() {
+		err := cleanUp("../tests/data/")
+		if err != nil {
+			t.Errorf("Failed to clean up: %v", err)
+		}
+	})
+
+	err = WriteLocalToken(filePath, &token)
+	if err != nil {
+		t.Fatalf("Failed to write token to file: %v", err)
+	}
+
+	got, err = ReadTokenScopes(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read the token scopes: %v", err)
+	}
+
+	if len(got) != 2 || got[0] != "offline" || got[1] != "read:profile" {
+		t.Errorf("Expected the scopes [offline read:profile] but got %v", got)
+	}
+
+	read, err := ReadTokenFromFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read token from file: %v", err)
+	}
+
+	if read.AccessToken != token.AccessToken {
+		t.Errorf("Expected %s but got %s", token.AccessToken, read.AccessToken)
+	}
+}
+
+func TestDeleteLocalToken(t *testing.T) {
+
+	filePath := filepath.Join("../tests/data/", "token.json")
+	err := os.MkdirAll("../tests/data/", 0755)
+	if err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	t.Cleanup(func() {
+		err := cleanUp("../tests/data/")
+		if err != nil {
+			t.Errorf("Failed to clean up: %v", err)
+		}
+	})
+
+	err = WriteLocalToken(filePath, &oauth2.Token{AccessToken: "askjdsajklsdlkjfasdk"})
+	if err != nil {
+		t.Fatalf("Failed to write token to file: %v", err)
+	}
+
+	err = DeleteLocalToken(filePath)
+	if err != nil {
+		t.Errorf("Failed to delete the credentials file: %v", err)
+	}
+
+	_, err = os.Stat(filePath)
+	if !os.IsNotExist(err) {
+		t.Errorf("Expected the credentials file to be deleted but got: %v", err)
+	}
+
+	_, err = os.Stat(lockFilePath(filePath))
+	if err != nil {
+		t.Errorf("Expected the lock file to remain after deleting the credentials file but got: %v", err)
+	}
+
+	err = DeleteLocalToken(filePath)
+	if err == nil {
+		t.Errorf("Expected an error when deleting a missing credentials file but got none")
+	}
+
+	err = DeleteLocalToken("../tests/data/")
+	if err == nil {
+		t.Errorf("Expected an error when deleting a directory but got none")
+	}
+}
+
+func TestRefreshTokenFile(t *testing.T) {
+
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		err := r.ParseForm()
+		if err != nil {
+			t.Errorf("Failed to parse the request form: %v", err)
+		}
+
+		// Whoop rotates the refresh token on every refresh
+		expectedRefreshToken := fmt.Sprintf("refresh-%d", requests-1)
+		if r.Form.Get("refresh_token") != expectedRefreshToken {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_grant"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err = fmt.Fprintf(w, `{
+			"access_token": "access-%d",
+			"expires_in": 3599,
+			"refresh_token": "refresh-%d",
+			"scope": "offline read:profile",
+			"token_type": "bearer"
+		}`, requests, requests)
+		if err != nil {
+			t.Errorf("Error writing response: %v", err)
+		}
+	}))
+	defer ts.Close()
+
+	filePath := filepath.Join(t.TempDir(), "token.json")
+	err := WriteLocalToken(filePath, &oauth2.Token{
+		AccessToken:  "access-0",
+		RefreshToken: "refresh-0",
+		Expiry:       time.Now().Add(30 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("Failed to write token to file: %v", err)
+	}
+
+	auth := AuthRequest{
+		Client:       CreateHTTPClient(),
+		ClientID:     "testClientID",
+		ClientSecret: "testClientSecret",
+		TokenURL:     ts.URL,
+	}
+
+	// Consecutive refreshes must always use the latest refresh token stored in the file
+	for i := 1; i <= 3; i++ {
+		token, err := RefreshTokenFile(context.Background(), auth, filePath)
+		if err != nil {
+			t.Fatalf("Refresh %d: Failed to refresh token: %v", i, err)
+		}
+
+		expected := fmt.Sprintf("access-%d", i)
+		if token.AccessToken != expected {
+			t.Errorf("Refresh %d: Expected %s but got %s", i, expected, token.AccessToken)
+		}
+	}
+
+	stored, err := ReadTokenFromFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read token from file: %v", err)
+	}
+
+	if stored.RefreshToken != "refresh-3" {
+		t.Errorf("Expected refresh-3 but got %s", stored.RefreshToken)
+	}
+
+	// A failed refresh must not overwrite the credentials file
+	err = WriteLocalToken(filePath, &oauth2.Token{AccessToken: "access-x", RefreshToken: "invalid"})
+	if err != nil {
+		t.Fatalf("Failed to write token to file: %v", err)
+	}
+
+	_, err = RefreshTokenFile(context.Background(), auth, filePath)
+	if err == nil {
+		t.Errorf("Expected an error but got none")
+	}
+
+	stored, err = ReadTokenFromFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read token from file: %v", err)
+	}
+
+	if stored.RefreshToken != "invalid" {
+		t.Errorf("Expected the credentials file to remain unchanged but got %s", stored.RefreshToken)
+	}
+}
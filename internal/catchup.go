@@ -0,0 +1,119 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// CatchUpSkip ignores the missed runs. The server resumes the schedule without collecting the missed window.
+	CatchUpSkip string = "skip"
+	// CatchUpOnce collects the whole missed window in a single run.
+	CatchUpOnce string = "once"
+	// CatchUpEach runs every missed run with the window it would have collected.
+	CatchUpEach string = "each"
+)
+
+// Window is the time window of the data fetched from the Whoop API.
+type Window struct {
+	// Start is the start of the window.
+	Start time.Time
+	// End is the end of the window.
+	End time.Time
+}
+
+// Last24Hours returns the window of the last 24 hours before the provided time.
+func Last24Hours(now time.Time) Window {
+	return LastWindow(now, 24*time.Hour)
+}
+
+// LastWindow returns the window of the provided duration before the provided time.
+func LastWindow(now time.Time, d time.Duration) Window {
+	return Window{
+		Start: now.Add(-d),
+		End:   now,
+	}
+}
+
+// Filter returns the start and the end of the window in the format of the Whoop API filters.
+func (w Window) Filter() (string, string) {
+
+	layout := "2006-01-02T15:04:05.000Z"
+
+	return w.Start.UTC().Format(layout), w.End.UTC().Format(layout)
+}
+
+// CatchUpRun is a catch-up run of a missed scheduled run.
+type CatchUpRun struct {
+	// Scheduled is the scheduled time of the missed run.
+	Scheduled time.Time
+	// Window is the time window of the data collected by the run.
+	Window Window
+}
+
+// MissedRuns returns the times the crontab scheduled a run after the last run and up to now.
+// Runs older than DEFAULT_CATCH_UP_MAX_AGE are ignored.
+func MissedRuns(crontab string, last, now time.Time) ([]time.Time, error) {
+
+	schedule, err := cron.ParseStandard(crontab)
+	if err != nil {
+		return nil, fmt.Errorf("invalid crontab %s: %w", crontab, err)
+	}
+
+	from := last
+	oldest := now.Add(-DEFAULT_CATCH_UP_MAX_AGE)
+	if from.Before(oldest) {
+		from = oldest
+	}
+
+	var missed []time.Time
+	for next := schedule.Next(from); !next.IsZero() && !next.After(now); next = schedule.Next(next) {
+		missed = append(missed, next)
+	}
+
+	return missed, nil
+}
+
+// CatchUpRuns returns the catch-up runs of the missed runs using the policy. The start is the end of the window of the last successful run.
+// The once policy returns a single run covering the window from the start to now. The each policy returns a run per missed run,
+// limited to the DEFAULT_CATCH_UP_MAX_RUNS most recent missed runs. The first window always begins at the start so no data is left uncollected.
+// The skip policy and an empty list of missed runs return no run.
+func CatchUpRuns(policy string, start time.Time, missed []time.Time, now time.Time) []CatchUpRun {
+
+	if len(missed) == 0 {
+		return nil
+	}
+
+	switch policy {
+	case CatchUpOnce, "":
+		return []CatchUpRun{
+			{
+				Scheduled: missed[len(missed)-1],
+				Window:    Window{Start: start, End: now},
+			},
+		}
+	case CatchUpEach:
+		if len(missed) > DEFAULT_CATCH_UP_MAX_RUNS {
+			missed = missed[len(missed)-DEFAULT_CATCH_UP_MAX_RUNS:]
+		}
+
+		runs := make([]CatchUpRun, 0, len(missed))
+		previous := start
+		for _, scheduled := range missed {
+			runs = append(runs, CatchUpRun{
+				Scheduled: scheduled,
+				Window:    Window{Start: previous, End: scheduled},
+			})
+			previous = scheduled
+		}
+
+		return runs
+	default:
+		return nil
+	}
+}
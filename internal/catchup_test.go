@@ -0,0 +1,164 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMissedRuns(t *testing.T) {
+
+	now := time.Date(2024, 6, 4, 9, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		crontab  string
+		last     time.Time
+		expected []time.Time
+		wantErr  bool
+	}{
+		{
+			name:    "missed daily runs",
+			crontab: DEFAULT_SERVER_CRON_SCHEDULE,
+			last:    time.Date(2024, 6, 1, 13, 0, 0, 0, time.Local),
+			expected: []time.Time{
+				time.Date(2024, 6, 2, 13, 0, 0, 0, time.Local),
+				time.Date(2024, 6, 3, 13, 0, 0, 0, time.Local),
+			},
+		},
+		{
+			name:    "no missed run",
+			crontab: DEFAULT_SERVER_CRON_SCHEDULE,
+			last:    time.Date(2024, 6, 3, 13, 0, 0, 0, time.Local),
+		},
+		{
+			name:    "runs older than the maximum age are ignored",
+			crontab: "0 13 1 * *",
+			last:    time.Date(2023, 1, 1, 13, 0, 0, 0, time.Local),
+			expected: []time.Time{
+				time.Date(2024, 6, 1, 13, 0, 0, 0, time.Local),
+			},
+		},
+		{
+			name:    "invalid crontab",
+			crontab: "every day",
+			last:    time.Date(2024, 6, 1, 13, 0, 0, 0, time.Local),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			missed, err := MissedRuns(tc.crontab, tc.last, now)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(missed, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, missed)
+			}
+		})
+	}
+}
+
+func TestCatchUpRuns(t *testing.T) {
+
+	start := time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 6, 4, 9, 0, 0, 0, time.UTC)
+	missed := []time.Time{
+		time.Date(2024, 6, 2, 13, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 3, 13, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		policy   string
+		missed   []time.Time
+		expected []CatchUpRun
+	}{
+		{
+			name:   "once",
+			policy: CatchUpOnce,
+			missed: missed,
+			expected: []CatchUpRun{
+				{Scheduled: missed[1], Window: Window{Start: start, End: now}},
+			},
+		},
+		{
+			name:   "default policy",
+			policy: "",
+			missed: missed,
+			expected: []CatchUpRun{
+				{Scheduled: missed[1], Window: Window{Start: start, End: now}},
+			},
+		},
+		{
+			name:   "each",
+			policy: CatchUpEach,
+			missed: missed,
+			expected: []CatchUpRun{
+				{Scheduled: missed[0], Window: Window{Start: start, End: missed[0]}},
+				{Scheduled: missed[1], Window: Window{Start: missed[0], End: missed[1]}},
+			},
+		},
+		{
+			name:   "skip",
+			policy: CatchUpSkip,
+			missed: missed,
+		},
+		{
+			name:   "no missed run",
+			policy: CatchUpOnce,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			runs := CatchUpRuns(tc.policy, start, tc.missed, now)
+			if !reflect.DeepEqual(runs, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, runs)
+			}
+		})
+	}
+}
+
+func TestCatchUpRunsMaxRuns(t *testing.T) {
+
+	start := time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)
+
+	var missed []time.Time
+	for i := 1; i <= DEFAULT_CATCH_UP_MAX_RUNS+5; i++ {
+		missed = append(missed, start.AddDate(0, 0, i))
+	}
+
+	runs := CatchUpRuns(CatchUpEach, start, missed, missed[len(missed)-1])
+	if len(runs) != DEFAULT_CATCH_UP_MAX_RUNS {
+		t.Fatalf("expected %d runs, got %d", DEFAULT_CATCH_UP_MAX_RUNS, len(runs))
+	}
+
+	// The first window begins at the start so no data is left uncollected
+	if !runs[0].Window.Start.Equal(start) || !runs[len(runs)-1].Scheduled.Equal(missed[len(missed)-1]) {
+		t.Errorf("unexpected runs: first %+v, last %+v", runs[0], runs[len(runs)-1])
+	}
+}
+
+func TestWindowFilter(t *testing.T) {
+
+	window := Window{
+		Start: time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 6, 2, 15, 30, 0, 0, time.FixedZone("CEST", 2*60*60)),
+	}
+
+	start, end := window.Filter()
+	if start != "2024-06-01T13:00:00.000Z" || end != "2024-06-02T13:30:00.000Z" {
+		t.Errorf("unexpected filter: start %s, end %s", start, end)
+	}
+
+	last := Last24Hours(window.Start)
+	if last.End.Sub(last.Start) != 24*time.Hour || !last.End.Equal(window.Start) {
+		t.Errorf("unexpected window of the last 24 hours: %+v", last)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// secretReferenceEnvPrefix is the prefix of a secret reference pointing to an environment variable.
+	secretReferenceEnvPrefix string = "env:"
+	// secretReferenceFilePrefix is the prefix of a secret reference pointing to a file.
+	secretReferenceFilePrefix string = "file:"
+)
+
+// ClientCredentials contains the credentials of the Whoop application used to authenticate with the Whoop API.
+type ClientCredentials struct {
+	// ID is the client ID of the Whoop application.
+	ID string
+	// Secret is the client secret of the Whoop application.
+	Secret string
+}
+
+// ResolveClientCredentials resolves the Whoop client ID and client secret from the credentials configuration.
+// The values can either be provided as is or as secret references. Environment variables are merged into the
+// credentials configuration by the caller and take precedence over the configuration file.
+func ResolveClientCredentials(cfg Credentials) (ClientCredentials, error) {
+
+	err := resolveCredentialReferences(&cfg)
+	if err != nil {
+		return ClientCredentials{}, err
+	}
+
+	return NewClientCredentials(cfg)
+}
+
+// NewClientCredentials returns the Whoop client ID and client secret of a credentials configuration whose secret references are already resolved.
+// Use ResolveCredentialReferences to resolve the secret references of the configuration.
+func NewClientCredentials(cfg Credentials) (ClientCredentials, error) {
+
+	switch {
+	case cfg.ClientID == "" && cfg.ClientSecret == "":
+		return ClientCredentials{}, errors.New("the Whoop client ID and client secret are not set. Provide them through the env variables WHOOP_CLIENT_ID and WHOOP_CLIENT_SECRET, the WHOOP_CLIENT_ID_FILE and WHOOP_CLIENT_SECRET_FILE env variables, or the configuration file")
+
+	case cfg.ClientID == "":
+		return ClientCredentials{}, errors.New("the Whoop client ID is not set")
+
+	case cfg.ClientSecret == "":
+		return ClientCredentials{}, errors.New("the Whoop client secret is not set")
+	}
+
+	return ClientCredentials{
+		ID:     cfg.ClientID,
+		Secret: cfg.ClientSecret,
+	}, nil
+}
+
+// ResolveCredentialReferences replaces the secret references of the top-level and account client credentials with the values they reference.
+// The references are resolved once per configuration load, so that the server jobs keep using the same client credentials until the configuration is loaded again.
+func ResolveCredentialReferences(cfg *ConfigurationData) error {
+
+	err := resolveCredentialReferences(&cfg.Credentials)
+	if err != nil {
+		return err
+	}
+
+	for i := range cfg.Accounts {
+		err := resolveCredentialReferences(&cfg.Accounts[i].Credentials)
+		if err != nil {
+			return fmt.Errorf("account %s: %w", cfg.Accounts[i].Name, err)
+		}
+	}
+
+	return nil
+}
+
+// resolveCredentialReferences replaces the secret references of the client ID and client secret with the values they reference.
+func resolveCredentialReferences(cfg *Credentials) error {
+
+	id, err := ResolveSecretReference(cfg.ClientID)
+	if err != nil {
+		return fmt.Errorf("unable to resolve the Whoop client ID: %w", err)
+	}
+
+	secret, err := ResolveSecretReference(cfg.ClientSecret)
+	if err != nil {
+		return fmt.Errorf("unable to resolve the Whoop client secret: %w", err)
+	}
+
+	cfg.ClientID = id
+	cfg.ClientSecret = secret
+
+	return nil
+}
+
+// ResolveSecretReference resolves a secret reference. A value starting with env: is resolved from the referenced environment variable.
+// A value starting with file: is resolved from the content of the referenced file, such as a Docker or Kubernetes secret.
+// Any other value is returned as is.
+func ResolveSecretReference(value string) (string, error) {
+
+	switch {
+	case strings.HasPrefix(value, secretReferenceEnvPrefix):
+		name := strings.TrimPrefix(value, secretReferenceEnvPrefix)
+		resolved, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("the referenced env variable %s is not set", name)
+		}
+		return resolved, nil
+
+	case strings.HasPrefix(value, secretReferenceFilePrefix):
+		content, err := os.ReadFile(strings.TrimPrefix(value, secretReferenceFilePrefix))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(content)), nil
+
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveSecretReference(t *testing.T) {
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	err := os.WriteFile(secretFile, []byte("file-secret\n"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to create the secret file: %v", err)
+	}
+
+	t.Setenv("MYWHOOP_TEST_SECRET", "env-secret")
+
+	tests := []struct {
+		description   string
+		value         string
+		expected      string
+		errorExpected bool
+	}{
+		{
+			description: "Test plain value",
+			value:       "plain-secret",
+			expected:    "plain-secret",
+		},
+		{
+			description: "Test env reference",
+			value:       "env:MYWHOOP_TEST_SECRET",
+			expected:    "env-secret",
+		},
+		{
+			description:   "Test missing env reference",
+			value:         "env:MYWHOOP_TEST_MISSING_SECRET",
+			errorExpected: true,
+		},
+		{
+			description: "Test file reference",
+			value:       "file:" + secretFile,
+			expected:    "file-secret",
+		},
+		{
+			description:   "Test missing file reference",
+			value:         "file:" + filepath.Join(t.TempDir(), "missing"),
+			errorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := ResolveSecretReference(test.value)
+			if err != nil && !test.errorExpected {
+				t.Errorf("Expected no error but got %v", err)
+			}
+
+			if err == nil && test.errorExpected {
+				t.Errorf("Expected an error but got none")
+			}
+
+			if got != test.expected {
+				t.Errorf("Expected %s but got %s", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestResolveClientCredentials(t *testing.T) {
+
+	t.Setenv("MYWHOOP_TEST_CLIENT_SECRET", "BBBBBBBBBBBBBBBBBBBBB")
+
+	tests := []struct {
+		description   string
+		cfg           Credentials
+		expected      ClientCredentials
+		errorExpected bool
+	}{
+		{
+			description: "Test client credentials with secret reference",
+			cfg: Credentials{
+				ClientID:     "AAAAAAAAAAAAAAAAAAA",
+				ClientSecret: "env:MYWHOOP_TEST_CLIENT_SECRET",
+			},
+			expected: ClientCredentials{
+				ID:     "AAAAAAAAAAAAAAAAAAA",
+				Secret: "BBBBBBBBBBBBBBBBBBBBB",
+			},
+		},
+		{
+			description:   "Test missing client credentials",
+			cfg:           Credentials{},
+			errorExpected: true,
+		},
+		{
+			description: "Test missing client secret",
+			cfg: Credentials{
+				ClientID: "AAAAAAAAAAAAAAAAAAA",
+			},
+			errorExpected: true,
+		},
+		{
+			description: "Test missing client ID",
+			cfg: Credentials{
+				ClientSecret: "BBBBBBBBBBBBBBBBBBBBB",
+			},
+			errorExpected: true,
+		},
+		{
+			description: "Test unresolvable secret reference",
+			cfg: Credentials{
+				ClientID:     "AAAAAAAAAAAAAAAAAAA",
+				ClientSecret: "env:MYWHOOP_TEST_MISSING_SECRET",
+			},
+			errorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := ResolveClientCredentials(test.cfg)
+			if err != nil && !test.errorExpected {
+				t.Errorf("Expected no error but got %v", err)
+			}
+
+			if err == nil && test.errorExpected {
+				t.Errorf("Expected an error but got none")
+			}
+
+			if got != test.expected {
+				t.Errorf("Expected %v but got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestResolveCredentialReferences(t *testing.T) {
+
+	t.Setenv("MYWHOOP_TEST_CLIENT_ID", "AAAAAAAAAAAAAAAAAAA")
+	t.Setenv("MYWHOOP_TEST_CLIENT_SECRET", "BBBBBBBBBBBBBBBBBBBBB")
+
+	cfg := ConfigurationData{
+		Credentials: Credentials{
+			ClientID:     "env:MYWHOOP_TEST_CLIENT_ID",
+			ClientSecret: "env:MYWHOOP_TEST_CLIENT_SECRET",
+		},
+		Accounts: []Account{
+			{Name: "alice"},
+			{Name: "bob", Credentials: Credentials{ClientSecret: "env:MYWHOOP_TEST_CLIENT_ID"}},
+		},
+	}
+
+	err := ResolveCredentialReferences(&cfg)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	// The resolved values are not resolved again
+	t.Setenv("MYWHOOP_TEST_CLIENT_SECRET", "CCCCCCCCCCCCCCCCCCCCC")
+
+	got, err := NewClientCredentials(cfg.ForAccount(cfg.Accounts[0]).Credentials)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	if got.ID != "AAAAAAAAAAAAAAAAAAA" || got.Secret != "BBBBBBBBBBBBBBBBBBBBB" {
+		t.Errorf("Expected the resolved top-level credentials but got %v", got)
+	}
+
+	if cfg.Accounts[1].Credentials.ClientSecret != "AAAAAAAAAAAAAAAAAAA" {
+		t.Errorf("Expected the resolved account client secret but got %s", cfg.Accounts[1].Credentials.ClientSecret)
+	}
+
+	cfg.Accounts[1].Credentials.ClientID = "env:MYWHOOP_TEST_MISSING_ID"
+	err = ResolveCredentialReferences(&cfg)
+	if err == nil || !strings.Contains(err.Error(), "bob") {
+		t.Errorf("Expected an error naming the account but got %v", err)
+	}
+}
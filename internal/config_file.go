@@ -4,6 +4,8 @@
 package internal
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -17,24 +19,28 @@ import (
 
 func CheckConfigFile(filePath string) (bool, string) {
 
-	// Check if config file is in  $HOME/.mywhoop.yaml
+	// Check if config file is in  $HOME/.mywhoop.yaml or $HOME/.mywhoop.json
 	if filePath == "" {
 		home, err := os.UserHomeDir()
 		if err != nil {
 			slog.Error("unable to get user home directory", "error", err)
 			return false, ""
 		}
-		filePath = path.Join(home, DEFAULT_CONFIG_FILE)
 
-		// Check if specified config file exists in $HOME directory
-		_, err = os.Stat(filePath)
-		if err != nil {
+		for _, name := range []string{DEFAULT_CONFIG_FILE, DEFAULT_CONFIG_FILE_JSON} {
+			filePath = path.Join(home, name)
+
+			// Check if specified config file exists in $HOME directory
+			_, err = os.Stat(filePath)
+			if err == nil {
+				return true, filePath
+			}
+
 			if os.IsNotExist(err) {
-				slog.Debug("config file not found in ~/.mywhoop.yaml", "config", err)
+				slog.Debug("config file not found in ~/"+name, "config", err)
 			}
-			return false, ""
 		}
-		return true, filePath
+		return false, ""
 
 	}
 
@@ -56,24 +62,58 @@ func GenerateConfigStruct(filePath string) (ConfigurationData, error) {
 		return ConfigurationData{}, err
 	}
 
-	if fileType != "yaml" {
-		return ConfigurationData{}, errors.New("invalid file type provided. Must be of type json, yaml or yml")
-	}
+	var configuration ConfigurationData
 
-	configuration, err := readConfigFileYaml(filePath)
+	switch fileType {
+	case "yaml":
+		configuration, err = readConfigFileYaml(filePath)
+	case "json":
+		configuration, err = readConfigFileJson(filePath)
+	default:
+		err = errors.New("invalid file type provided. Must be of type json, yaml or yml")
+	}
 	if err != nil {
 		return configuration, err
 	}
 
-	err = validateConfiguration(configuration)
+	err = ValidateConfiguration(configuration)
 	if err != nil {
-		slog.Info("invalid configuration", "error", err)
 		return configuration, err
 	}
 
 	return configuration, nil
 }
 
+// ValidateConfiguration validates the configuration data, the accounts list, the server crontab, and the server jobs list.
+func ValidateConfiguration(configuration ConfigurationData) error {
+
+	err := validateConfiguration(configuration)
+	if err != nil {
+		slog.Info("invalid configuration", "error", err)
+		return err
+	}
+
+	err = validateAccounts(configuration)
+	if err != nil {
+		slog.Info("invalid account configuration", "error", err)
+		return err
+	}
+
+	err = validateServerCrontab(configuration)
+	if err != nil {
+		slog.Info("invalid server configuration", "error", err)
+		return err
+	}
+
+	err = validateJobs(configuration)
+	if err != nil {
+		slog.Info("invalid server job configuration", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 // readConfigFileYaml is a function that takes a file path as input and returns a list of Lambdas to be deleted. A YAML file is expected.
 func readConfigFileYaml(file string) (ConfigurationData, error) {
 
@@ -82,9 +122,9 @@ func readConfigFileYaml(file string) (ConfigurationData, error) {
 		return ConfigurationData{}, errors.New("unable to read the input file")
 	}
 
-	fileContent, err := os.ReadFile(file)
+	fileContent, err := readConfigFileContent(file, "yaml")
 	if err != nil {
-		slog.Error("unable to read the content of the file", "file", file)
+		slog.Error("unable to read the content of the file", "file", file, "error", err)
 		return ConfigurationData{}, err
 	}
 
@@ -104,6 +144,37 @@ func readConfigFileYaml(file string) (ConfigurationData, error) {
 
 }
 
+// readConfigFileJson is a function that takes a file path as input and returns the configuration data. A JSON file is expected.
+// Unknown fields are rejected to match the behavior of readConfigFileYaml.
+func readConfigFileJson(file string) (ConfigurationData, error) {
+
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		slog.Error("file not found", "file", file)
+		return ConfigurationData{}, errors.New("unable to read the input file")
+	}
+
+	fileContent, err := readConfigFileContent(file, "json")
+	if err != nil {
+		slog.Error("unable to read the content of the file", "file", file, "error", err)
+		return ConfigurationData{}, err
+	}
+
+	config := ConfigurationData{}
+
+	dc := json.NewDecoder(bytes.NewReader(fileContent))
+	dc.DisallowUnknownFields()
+
+	if err := dc.Decode(&config); err != nil {
+		return ConfigurationData{}, fmt.Errorf("unable to decode the JSON file. Ensure the file is in the correct format and that all fields are correct. %s", err.Error())
+	}
+
+	// Set debug values to all upper case.
+	config.Debug = strings.ToUpper(config.Debug)
+
+	return config, nil
+
+}
+
 // determineFileType validates the existence of an input file and ensures its prefix is json | yaml | yml
 // If the file prefix is yml then it is converted to yaml.
 func determineFileType(file string) (string, error) {
@@ -126,19 +197,20 @@ func determineFileType(file string) (string, error) {
 // validateConfiguration is a function that validates the configuration data
 func validateConfiguration(config ConfigurationData) error {
 
-	validate := validator.New(validator.WithRequiredStructEnabled())
-	err := validate.Struct(config)
+	err := newConfigValidator().Struct(config)
 	if err != nil {
-		slog.Info("Invalid configuration file provided", "", "")
-		for _, err := range err.(validator.ValidationErrors) {
-			slog.Info(
-				"The following field failed validation",
-				"field", err.Field(),
-				"error", err.ActualTag())
-			slog.Debug("Additional Context: ", "params", err)
-
+		slog.Info("Invalid configuration file provided. Use the config validate command to review the errors.")
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			for _, fieldErr := range validationErrors {
+				slog.Info(
+					"The following field failed validation",
+					"field", validationFieldPath(fieldErr),
+					"error", validationMessage(fieldErr))
+				slog.Debug("Additional Context: ", "params", fieldErr)
+			}
 		}
-		slog.Debug("Configuration Received", "config", config)
+		slog.Debug("Configuration Received", "config", RedactSecrets(config))
 
 		return err
 	}
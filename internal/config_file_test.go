@@ -117,3 +117,96 @@ func TestDetermineFileType(t *testing.T) {
 	}
 
 }
+
+func TestGenerateConfigStructAccounts(t *testing.T) {
+
+	got, err := GenerateConfigStruct("../tests/valid_accounts_config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to generate the configuration. Expected no error but received %v", err)
+	}
+
+	if len(got.Accounts) != 2 {
+		t.Fatalf("Expected 2 accounts but received %d", len(got.Accounts))
+	}
+
+	if got.Accounts[1].Export == nil || got.Accounts[1].Export.FileExport.FileType != "xlsx" {
+		t.Fatalf("Expected the export override of the bob account to be read")
+	}
+
+	if got.Accounts[0].Export != nil {
+		t.Fatalf("Expected no export override for the alice account")
+	}
+}
+
+func TestReadConfigFileJson(t *testing.T) {
+
+	expectedYaml, err := readConfigFileYaml("../tests/valid_config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read the Yaml file. Expected no error but received %v", err)
+	}
+
+	got, err := readConfigFileJson("../tests/valid_config.json")
+	if err != nil {
+		t.Fatalf("Failed to read the JSON file. Expected no error but received %v", err)
+	}
+
+	if got.Export.Method != expectedYaml.Export.Method {
+		t.Fatalf("Failed to read the JSON file. Expected %s but received %s", expectedYaml.Export.Method, got.Export.Method)
+	}
+
+	if got.Export.FileExport != expectedYaml.Export.FileExport {
+		t.Fatalf("Failed to read the JSON file. Expected %v but received %v", expectedYaml.Export.FileExport, got.Export.FileExport)
+	}
+
+	if got.Notification.Ntfy.ServerEndpoint != expectedYaml.Notification.Ntfy.ServerEndpoint {
+		t.Fatalf("Failed to read the JSON file. Expected %s but received %s", expectedYaml.Notification.Ntfy.ServerEndpoint, got.Notification.Ntfy.ServerEndpoint)
+	}
+
+	if got.Debug != "DEBUG" {
+		t.Fatalf("Failed to read the JSON file. Expected %s but received %s", "DEBUG", got.Debug)
+	}
+
+}
+
+func TestReadConfigFileJsonErrors(t *testing.T) {
+
+	tests := []struct {
+		description string
+		fileName    string
+	}{
+		{
+			description: "Test file not found",
+			fileName:    "../tests/missing_config.json",
+		},
+		{
+			description: "Test invalid JSON",
+			fileName:    "../tests/invalid.json",
+		},
+		{
+			description: "Test unknown field",
+			fileName:    "../tests/unknown_field_config.json",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			_, err := readConfigFileJson(test.fileName)
+			if err == nil {
+				t.Fatalf("Error expected but got nil %v", err)
+			}
+		})
+	}
+
+}
+
+func TestGenerateConfigStructJson(t *testing.T) {
+
+	got, err := GenerateConfigStruct("../tests/valid_config.json")
+	if err != nil {
+		t.Fatalf("Failed to generate the configuration. Expected no error but received %v", err)
+	}
+
+	if got.Export.Method != "file" {
+		t.Fatalf("Expected the file export method but received %s", got.Export.Method)
+	}
+}
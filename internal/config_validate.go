@@ -0,0 +1,376 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlLineRegex extracts the line number from the errors returned by the YAML decoder.
+var yamlLineRegex = regexp.MustCompile(`line (\d+): `)
+
+// ConfigError is a human readable error found in a configuration file.
+type ConfigError struct {
+	// Field is the path of the field in the configuration file, such as export.method. Empty if the error is not related to a field.
+	Field string
+	// Line is the line number of the field in the configuration file. Zero if the line number is unknown.
+	Line int
+	// Message describes the error.
+	Message string
+}
+
+// Error returns the string representation of the configuration error.
+func (e ConfigError) Error() string {
+
+	var out strings.Builder
+
+	if e.Line > 0 {
+		fmt.Fprintf(&out, "line %d: ", e.Line)
+	}
+
+	if e.Field != "" {
+		out.WriteString(e.Field + " ")
+	}
+
+	out.WriteString(e.Message)
+
+	return out.String()
+}
+
+// ValidateConfigFile validates the configuration file and returns a human readable error for every invalid field.
+// The line numbers of the invalid fields are included for YAML files. An error is returned if the file can't be read.
+func ValidateConfigFile(filePath string) ([]ConfigError, error) {
+
+	fileType, err := determineFileType(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(filePath); err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	strict, err := strictInterpolation()
+	if err != nil {
+		return []ConfigError{{Message: err.Error()}}, nil
+	}
+
+	var (
+		config ConfigurationData
+		lines  = make(map[string]int)
+	)
+
+	switch fileType {
+	case "yaml":
+		var root yaml.Node
+		err = yaml.Unmarshal(content, &root)
+		if err != nil {
+			return []ConfigError{yamlConfigError(err.Error())}, nil
+		}
+		yamlNodeLines(&root, "", lines)
+
+		interpolated, err := InterpolateEnv(content, fileType, strict)
+		if err != nil {
+			return []ConfigError{{Message: err.Error()}}, nil
+		}
+
+		dc := yaml.NewDecoder(bytes.NewReader(interpolated))
+		dc.KnownFields(true)
+		err = dc.Decode(&config)
+		if err != nil {
+			var configErrors []ConfigError
+			var typeErr *yaml.TypeError
+			if errors.As(err, &typeErr) {
+				for _, msg := range typeErr.Errors {
+					configErrors = append(configErrors, yamlConfigError(msg))
+				}
+			} else {
+				configErrors = append(configErrors, yamlConfigError(err.Error()))
+			}
+
+			// The interpolated content is encoded again, so the line numbers are translated to the lines of the configuration file
+			if !bytes.Equal(interpolated, content) {
+				translateYamlLines(&root, interpolated, configErrors)
+			}
+			return configErrors, nil
+		}
+
+	case "json":
+		// The interpolation only changes string values, so the decoding errors are reported with the offsets of the configuration file
+		dc := json.NewDecoder(bytes.NewReader(content))
+		dc.DisallowUnknownFields()
+		err = dc.Decode(&config)
+		if err != nil {
+			return []ConfigError{jsonConfigError(content, err)}, nil
+		}
+
+		interpolated, err := InterpolateEnv(content, fileType, strict)
+		if err != nil {
+			return []ConfigError{{Message: err.Error()}}, nil
+		}
+
+		config = ConfigurationData{}
+		err = json.Unmarshal(interpolated, &config)
+		if err != nil {
+			return []ConfigError{jsonConfigError(interpolated, err)}, nil
+		}
+	}
+
+	return describeValidationErrors(config, lines), nil
+}
+
+// describeValidationErrors validates the configuration and converts the validation errors to human readable errors.
+// The line numbers are looked up in the provided map of field paths to line numbers.
+func describeValidationErrors(config ConfigurationData, lines map[string]int) []ConfigError {
+
+	var configErrors []ConfigError
+
+	err := newConfigValidator().Struct(config)
+	if err != nil {
+		var validationErrors validator.ValidationErrors
+		if !errors.As(err, &validationErrors) {
+			return []ConfigError{{Message: err.Error()}}
+		}
+
+		for _, fieldErr := range validationErrors {
+			field := validationFieldPath(fieldErr)
+			configErrors = append(configErrors, ConfigError{
+				Field:   field,
+				Line:    fieldLine(lines, field),
+				Message: validationMessage(fieldErr),
+			})
+		}
+	}
+
+	err = validateAccounts(config)
+	if err != nil {
+		configErrors = append(configErrors, ConfigError{
+			Line:    lines["accounts"],
+			Message: err.Error(),
+		})
+	}
+
+	err = validateServerCrontab(config)
+	if err != nil {
+		configErrors = append(configErrors, ConfigError{
+			Line:    lines["server.crontab"],
+			Message: err.Error(),
+		})
+	}
+
+	err = validateJobs(config)
+	if err != nil {
+		configErrors = append(configErrors, ConfigError{
+			Line:    lines["server.jobs"],
+			Message: err.Error(),
+		})
+	}
+
+	return configErrors
+}
+
+// fieldLine returns the line number of the field. The line number of the closest parent is returned for fields missing from the configuration file.
+func fieldLine(lines map[string]int, field string) int {
+
+	for field != "" {
+		if line, ok := lines[field]; ok {
+			return line
+		}
+
+		i := strings.LastIndexAny(field, ".[")
+		if i < 0 {
+			return 0
+		}
+		field = field[:i]
+	}
+
+	return 0
+}
+
+// newConfigValidator returns a validator that reports the configuration file names of the fields instead of the Go field names.
+func newConfigValidator() *validator.Validate {
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := configFieldName(field)
+		if name == "" {
+			return "-"
+		}
+		return name
+	})
+
+	return validate
+}
+
+// validationFieldPath returns the configuration file path of the field that failed validation, such as export.method.
+// The items of the notification channels list are reported as notification[0].method.
+func validationFieldPath(fieldErr validator.FieldError) string {
+
+	namespace := strings.ReplaceAll(fieldErr.Namespace(), ".-[", "[")
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+
+	return namespace
+}
+
+// validationMessage returns a human readable description of the validation rule that failed.
+func validationMessage(fieldErr validator.FieldError) string {
+
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "required_if":
+		params := strings.Fields(fieldErr.Param())
+		if len(params) == 2 {
+			return fmt.Sprintf("is required when %s is %s", lowerFirst(params[0]), params[1])
+		}
+		return "is required"
+	case "oneof":
+		var values []string
+		for _, value := range parseOneOf(fieldErr.Param()) {
+			values = append(values, strconv.Quote(value))
+		}
+		return fmt.Sprintf("has the invalid value %q. Allowed values are %s", fmt.Sprint(fieldErr.Value()), strings.Join(values, ", "))
+	case "unique":
+		return fmt.Sprintf("must not contain duplicate %s values", lowerFirst(fieldErr.Param()))
+	default:
+		return fmt.Sprintf("failed the %s validation", fieldErr.Tag())
+	}
+}
+
+// lowerFirst returns the string with the first letter in lower case.
+func lowerFirst(s string) string {
+
+	if s == "" {
+		return s
+	}
+
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// yamlNodeLines walks the YAML node tree and records the line number of every field path.
+// Sequence items use the [index] notation, such as accounts[0].name.
+func yamlNodeLines(node *yaml.Node, path string, lines map[string]int) {
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			yamlNodeLines(child, path, lines)
+		}
+
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+			}
+			lines[childPath] = key.Line
+			yamlNodeLines(node.Content[i+1], childPath, lines)
+		}
+
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			lines[childPath] = child.Line
+			yamlNodeLines(child, childPath, lines)
+		}
+	}
+}
+
+// translateYamlLines replaces the line numbers of the errors found in the interpolated content with the line numbers of the configuration file.
+// The nodes of the interpolated content are matched with the nodes of the configuration file as the interpolation does not change the structure of the file.
+func translateYamlLines(root *yaml.Node, interpolated []byte, configErrors []ConfigError) {
+
+	var interpolatedRoot yaml.Node
+	err := yaml.Unmarshal(interpolated, &interpolatedRoot)
+	if err != nil {
+		return
+	}
+
+	translation := make(map[int]int)
+	yamlLineTranslation(root, &interpolatedRoot, translation)
+
+	for i := range configErrors {
+		configErrors[i].Line = translation[configErrors[i].Line]
+	}
+}
+
+// yamlLineTranslation walks the node trees of the configuration file and of the interpolated content, and maps the line numbers of the interpolated nodes to the original line numbers.
+func yamlLineTranslation(original, interpolated *yaml.Node, translation map[int]int) {
+
+	if _, ok := translation[interpolated.Line]; !ok {
+		translation[interpolated.Line] = original.Line
+	}
+
+	if len(original.Content) != len(interpolated.Content) {
+		return
+	}
+
+	for i := range original.Content {
+		yamlLineTranslation(original.Content[i], interpolated.Content[i], translation)
+	}
+}
+
+// yamlConfigError converts an error message of the YAML decoder to a ConfigError with the line number extracted from the message.
+func yamlConfigError(msg string) ConfigError {
+
+	msg = strings.TrimPrefix(msg, "yaml: ")
+
+	match := yamlLineRegex.FindStringSubmatch(msg)
+	if match == nil {
+		return ConfigError{Message: msg}
+	}
+
+	line, _ := strconv.Atoi(match[1])
+
+	return ConfigError{
+		Line:    line,
+		Message: strings.Replace(msg, match[0], "", 1),
+	}
+}
+
+// jsonConfigError converts an error of the JSON decoder to a ConfigError. The line number is computed from the error offset when available.
+func jsonConfigError(content []byte, err error) ConfigError {
+
+	var offset int64
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return ConfigError{Message: strings.TrimPrefix(err.Error(), "json: ")}
+	}
+
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+
+	return ConfigError{
+		Line:    bytes.Count(content[:offset], []byte("\n")) + 1,
+		Message: strings.TrimPrefix(err.Error(), "json: "),
+	}
+}
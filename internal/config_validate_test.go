@@ -0,0 +1,173 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateConfigFile(t *testing.T) {
+
+	tests := []struct {
+		description string
+		fileName    string
+		content     string
+		expected    []ConfigError
+	}{
+		{
+			description: "Valid configuration",
+			fileName:    "config.yaml",
+			content:     "export:\n  method: file\n  fileExport:\n    filePath: data/\n",
+			expected:    nil,
+		},
+		{
+			description: "Invalid enum value",
+			fileName:    "config.yaml",
+			content:     "export:\n  method: ftp\n",
+			expected: []ConfigError{
+				{Field: "export.method", Line: 2, Message: `has the invalid value "ftp". Allowed values are "file", "s3"`},
+			},
+		},
+		{
+			description: "Missing required field uses the parent line",
+			fileName:    "config.yaml",
+			content:     "export:\n  method: file\n  fileExport:\n    filePath: data/\nnotification:\n  method: ntfy\n",
+			expected: []ConfigError{
+				{Field: "notification.ntfy", Line: 5, Message: "is required when method is ntfy"},
+			},
+		},
+		{
+			description: "Invalid notification channel",
+			fileName:    "config.yaml",
+			content:     "export:\n  method: file\n  fileExport:\n    filePath: data/\nnotification:\n  - method: slack\n    events: errors\n  - method: sms\n    events: success\n",
+			expected: []ConfigError{
+				{Field: "notification[1].method", Line: 8, Message: `has the invalid value "sms". Allowed values are "ntfy", "slack", "discord", "email", "webhook", "gotify", "pushover", "telegram", "matrix", "stdout", ""`},
+			},
+		},
+		{
+			description: "Unknown notification channel field",
+			fileName:    "config.yaml",
+			content:     "export:\n  method: file\n  fileExport:\n    filePath: data/\nnotification:\n  - method: slack\n    event: errors\n",
+			expected: []ConfigError{
+				{Line: 7, Message: "field event not found in type internal.notificationConfig"},
+			},
+		},
+		{
+			description: "Unknown field",
+			fileName:    "config.yaml",
+			content:     "export:\n  methd: file\n",
+			expected: []ConfigError{
+				{Line: 2, Message: "field methd not found in type internal.ConfigExport"},
+			},
+		},
+		{
+			description: "Unknown field after an interpolated value",
+			fileName:    "config.yaml",
+			content:     "# Export\n\nexport:\n  method: ${MYWHOOP_TEST_METHOD:-file}\n\n  methd: file\n",
+			expected: []ConfigError{
+				{Line: 6, Message: "field methd not found in type internal.ConfigExport"},
+			},
+		},
+		{
+			description: "Invalid account name",
+			fileName:    "config.yaml",
+			content:     "export:\n  method: file\n  fileExport:\n    filePath: data/\naccounts:\n  - name: \"bad name\"\n",
+			expected: []ConfigError{
+				{Line: 5, Message: `invalid account name "bad name". Only letters, numbers, dashes and underscores are allowed`},
+			},
+		},
+		{
+			description: "Invalid server crontab",
+			fileName:    "config.yaml",
+			content:     "export:\n  method: file\n  fileExport:\n    filePath: data/\nserver:\n  enabled: true\n  crontab: \"0 25 * * *\"\n",
+			expected: []ConfigError{
+				{Line: 7, Message: `invalid server crontab "0 25 * * *": end of range (25) above maximum (23): 25`},
+			},
+		},
+		{
+			description: "JSON syntax error",
+			fileName:    "config.json",
+			content:     "{\n  \"export\": {\n    \"method\": file\n  }\n}\n",
+			expected: []ConfigError{
+				{Line: 3, Message: "invalid character 'i' in literal false (expecting 'a')"},
+			},
+		},
+		{
+			description: "JSON invalid enum value",
+			fileName:    "config.json",
+			content:     `{"export": {"method": "ftp"}}`,
+			expected: []ConfigError{
+				{Field: "export.method", Message: `has the invalid value "ftp". Allowed values are "file", "s3"`},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), test.fileName)
+			err := os.WriteFile(filePath, []byte(test.content), 0600)
+			if err != nil {
+				t.Fatalf("Failed to create the configuration file: %v", err)
+			}
+
+			got, err := ValidateConfigFile(filePath)
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+
+			if len(got) != len(test.expected) {
+				t.Fatalf("Expected %d errors but got %d: %v", len(test.expected), len(got), got)
+			}
+
+			for i := range got {
+				if got[i] != test.expected[i] {
+					t.Errorf("Expected %+v but got %+v", test.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateConfigFileInvalidType(t *testing.T) {
+
+	_, err := ValidateConfigFile("config.txt")
+	if err == nil {
+		t.Fatalf("Expected an error due to the invalid file type")
+	}
+}
+
+func TestConfigErrorString(t *testing.T) {
+
+	tests := []struct {
+		description string
+		input       ConfigError
+		expected    string
+	}{
+		{
+			description: "Field and line",
+			input:       ConfigError{Field: "export.method", Line: 2, Message: "is required"},
+			expected:    "line 2: export.method is required",
+		},
+		{
+			description: "No line",
+			input:       ConfigError{Field: "export.method", Message: "is required"},
+			expected:    "export.method is required",
+		},
+		{
+			description: "No field",
+			input:       ConfigError{Line: 4, Message: "did not find expected key"},
+			expected:    "line 4: did not find expected key",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if got := test.input.Error(); got != test.expected {
+				t.Errorf("Expected %s but got %s", test.expected, got)
+			}
+		})
+	}
+}
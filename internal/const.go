@@ -18,6 +18,8 @@ const (
 	DEFAULT_CREDENTIALS_FILE string = "token.json"
 	// DEFAULT_CONFIG_FILE is the default file to store the configuration
 	DEFAULT_CONFIG_FILE string = ".mywhoop.yaml"
+	// DEFAULT_CONFIG_FILE_JSON is the default JSON file to store the configuration. Used if DEFAULT_CONFIG_FILE does not exist.
+	DEFAULT_CONFIG_FILE_JSON string = ".mywhoop.json"
 	// Retry/Backoff constants
 	DEFAULT_RETRY_MAX_ELAPSED_TIME time.Duration = 5 * time.Minute
 	DEFAULT_RETRY_MULTIPLIER       float64       = 1.5
@@ -35,8 +37,62 @@ const (
 	DEFAULT_WHOOP_API_WORKOUT_DATA_URL = "https://api.prod.whoop.com/developer/v1/activity/workout?"
 	// DEFAULT_WHOOP_API_CYCLE_DATA_URL is the URL to get the user cycle data from the Whoop API
 	DEFAULT_WHOOP_API_CYCLE_DATA_URL = "https://api.prod.whoop.com/developer/v1/cycle?"
+	// DEFAULT_WHOOP_API_REVOKE_ACCESS_URL is the URL to revoke the access granted to MyWhoop through the Whoop API
+	DEFAULT_WHOOP_API_REVOKE_ACCESS_URL = "https://api.prod.whoop.com/developer/v1/user/access"
 	// DEFAULT_SERVER_CRON_SCHEDULE is the default cron schedule for the server. Everyday at 1:00 PM OR 1300 hours.
 	DEFAULT_SERVER_CRON_SCHEDULE string = "0 13 * * *"
 	// DEFAULT_SERVER_TOKEN_REFRESH_CRON_SCHEDULE is the default cron schedule for the token refresh. Every 45 minutes.
 	DEFAULT_SERVER_TOKEN_REFRESH_CRON_SCHEDULE time.Duration = 45 * time.Minute
+	// DEFAULT_FILE_LOCK_TIMEOUT is the maximum time to wait for a file lock held by another MyWhoop process.
+	DEFAULT_FILE_LOCK_TIMEOUT time.Duration = 30 * time.Second
+	// DEFAULT_FILE_LOCK_RETRY_INTERVAL is the interval between attempts to acquire a file lock.
+	DEFAULT_FILE_LOCK_RETRY_INTERVAL time.Duration = 100 * time.Millisecond
+	// DEFAULT_CONFIG_RELOAD_DEBOUNCE is the time to wait for the configuration file changes to settle before the configuration is reloaded.
+	DEFAULT_CONFIG_RELOAD_DEBOUNCE time.Duration = 500 * time.Millisecond
+	// DEFAULT_NOTIFICATION_PUBLISH_TIMEOUT is the maximum time to wait for the notification channels to publish a notification.
+	DEFAULT_NOTIFICATION_PUBLISH_TIMEOUT time.Duration = 30 * time.Second
+	// DEFAULT_NOTIFICATION_MAX_RETRIES is the default number of times a failed notification is retried.
+	DEFAULT_NOTIFICATION_MAX_RETRIES int = 3
+	// DEFAULT_NOTIFICATION_RETRY_INTERVAL is the initial interval between the retries of a failed notification.
+	DEFAULT_NOTIFICATION_RETRY_INTERVAL time.Duration = 1 * time.Second
+	// DEFAULT_NOTIFICATION_DEDUP_WINDOW is the default window during which identical error and alert notifications are only sent once.
+	DEFAULT_NOTIFICATION_DEDUP_WINDOW time.Duration = 60 * time.Minute
+	// DEFAULT_NOTIFICATION_FLUSH_INTERVAL is the interval between the deliveries of the notifications stored in the spool files.
+	DEFAULT_NOTIFICATION_FLUSH_INTERVAL time.Duration = 15 * time.Minute
+	// DEFAULT_NOTIFICATION_SPOOL_SIZE is the maximum number of undelivered notifications stored per notification channel. The oldest notifications are dropped first.
+	DEFAULT_NOTIFICATION_SPOOL_SIZE int = 100
+	// DEFAULT_JOB_MAX_ATTEMPTS is the default number of attempts of a server job run before the run gives up.
+	DEFAULT_JOB_MAX_ATTEMPTS int = 3
+	// DEFAULT_JOB_RETRY_BACKOFF is the default initial interval between the attempts of a server job run. The interval doubles after every attempt.
+	DEFAULT_JOB_RETRY_BACKOFF time.Duration = 30 * time.Second
+	// DEFAULT_JOB_GIVE_UP_ACTION is the default action taken when a server job run gives up. The run is skipped and the job runs again at its next scheduled time.
+	DEFAULT_JOB_GIVE_UP_ACTION string = "skip"
+	// DEFAULT_CIRCUIT_BREAKER_THRESHOLD is the default number of consecutive failed runs of a server job that open the circuit breaker.
+	DEFAULT_CIRCUIT_BREAKER_THRESHOLD int = 3
+	// DEFAULT_CIRCUIT_BREAKER_COOLDOWN is the default time the circuit breaker stays open before a server job runs again.
+	DEFAULT_CIRCUIT_BREAKER_COOLDOWN time.Duration = 6 * time.Hour
+	// DEFAULT_HISTORY_FILE is the default file the runs of the server jobs are recorded to.
+	DEFAULT_HISTORY_FILE string = "history.jsonl"
+	// DEFAULT_SERVER_STATE_FILE is the default file the time of the last successful data collection is stored in.
+	DEFAULT_SERVER_STATE_FILE string = "mywhoop_state.json"
+	// DEFAULT_CATCH_UP_POLICY is the default catch-up policy. The whole missed window is collected in a single run.
+	DEFAULT_CATCH_UP_POLICY string = "once"
+	// DEFAULT_CATCH_UP_MAX_AGE is the maximum age of a missed run. Older missed runs are not collected.
+	DEFAULT_CATCH_UP_MAX_AGE time.Duration = 30 * 24 * time.Hour
+	// DEFAULT_CATCH_UP_MAX_RUNS is the maximum number of missed runs collected with the each policy. The most recent missed runs are collected.
+	DEFAULT_CATCH_UP_MAX_RUNS int = 31
+	// DEFAULT_SERVER_LOCK_FILE is the default lock file that prevents two servers on the same host from running the jobs.
+	DEFAULT_SERVER_LOCK_FILE string = "mywhoop.lock"
+	// DEFAULT_LEADER_ELECTION_KEY is the default key of the S3 object holding the leader election lease.
+	DEFAULT_LEADER_ELECTION_KEY string = "mywhoop/leader.json"
+	// DEFAULT_LEADER_LEASE_DURATION is the default time the leader election lease is valid without renewal.
+	DEFAULT_LEADER_LEASE_DURATION time.Duration = 60 * time.Second
+	// DEFAULT_LOG_FILE is the default log file used with the file destination.
+	DEFAULT_LOG_FILE string = "mywhoop.log"
+	// DEFAULT_LOG_MAX_SIZE is the default size in megabytes the log file is rotated at.
+	DEFAULT_LOG_MAX_SIZE int = 10
+	// DEFAULT_LOG_MAX_AGE is the default time the log file is rotated after.
+	DEFAULT_LOG_MAX_AGE time.Duration = 24 * time.Hour
+	// DEFAULT_LOG_MAX_BACKUPS is the default number of rotated log files kept.
+	DEFAULT_LOG_MAX_BACKUPS int = 7
 )
@@ -93,14 +93,14 @@ func (u User) GetUserMeasurements(ctx context.Context, client *http.Client, url,
 
 	body, err := io.ReadAll(response.Body)
 	if err != nil {
-		slog.Error("unable to read response body from user mesurements payload", "msg", err)
+		apiLogger().ErrorContext(ctx, "unable to read response body from user mesurements payload", "msg", err)
 		return nil, err
 	}
 
 	var user UserMesaurements
 	err = json.Unmarshal(body, &user)
 	if err != nil {
-		slog.Error("unable to unmarshal data from Whoop API user mesaurement payload", "msg", err)
+		apiLogger().ErrorContext(ctx, "unable to unmarshal data from Whoop API user mesaurement payload", "msg", err)
 		return nil, err
 	}
 
@@ -121,18 +121,18 @@ func (u User) GetSleepCollection(ctx context.Context, client *http.Client, url,
 	urlWithFilters := url
 
 	if filters != "" {
-		slog.Debug("Sleep Filters", slog.String("Filters", filters))
+		apiLogger().DebugContext(ctx, "Sleep Filters", slog.String("Filters", filters))
 		urlWithFilters = url + filters
 	}
 
 	bo := generateBackoff()
-	slog.Info(("Requesting sleep collection from Whoop API"))
+	apiLogger().InfoContext(ctx, "Requesting sleep collection from Whoop API")
 	for continueLoop {
 
 		if nextLoopUrl == "" {
 			nextLoopUrl = urlWithFilters
 		}
-		slog.Debug("URL", slog.String("URL", nextLoopUrl))
+		apiLogger().DebugContext(ctx, "URL", slog.String("URL", nextLoopUrl))
 		req, err := http.NewRequestWithContext(context.Background(), method, nextLoopUrl, nil)
 		if err != nil {
 			LogError(err)
@@ -159,7 +159,7 @@ func (u User) GetSleepCollection(ctx context.Context, client *http.Client, url,
 			defer response.Body.Close()
 
 			if response.StatusCode == http.StatusTooManyRequests {
-				slog.Info("Too many requests. Retrying...")
+				apiLogger().InfoContext(ctx, "Too many requests. Retrying...")
 				return errors.New("too many requests")
 			}
 
@@ -172,7 +172,7 @@ func (u User) GetSleepCollection(ctx context.Context, client *http.Client, url,
 
 			body, err := io.ReadAll(response.Body)
 			if err != nil {
-				slog.Error("unable to read response body from sleep collection payload", "msg", err)
+				apiLogger().ErrorContext(ctx, "unable to read response body from sleep collection payload", "msg", err)
 				return err
 			}
 
@@ -206,7 +206,7 @@ func (u User) GetSleepCollection(ctx context.Context, client *http.Client, url,
 		}
 	}
 
-	slog.Debug("Sleep Records", slog.Any("Sleep Records Count", len(sleepRecords)))
+	apiLogger().DebugContext(ctx, "Sleep Records", slog.Any("Sleep Records Count", len(sleepRecords)))
 
 	sleep.SleepCollectionRecords = sleepRecords
 
@@ -230,13 +230,13 @@ func (u User) GetRecoveryCollection(ctx context.Context, client *http.Client, ur
 	}
 
 	bo := generateBackoff()
-	slog.Info(("Requesting recovery collection from Whoop API"))
+	apiLogger().InfoContext(ctx, "Requesting recovery collection from Whoop API")
 	for continueLoop {
 
 		if nextLoopUrl == "" {
 			nextLoopUrl = urlWithFilters
 		}
-		slog.Debug("URL", slog.String("URL", nextLoopUrl))
+		apiLogger().DebugContext(ctx, "URL", slog.String("URL", nextLoopUrl))
 		req, err := http.NewRequestWithContext(context.Background(), method, nextLoopUrl, nil)
 		if err != nil {
 			LogError(err)
@@ -263,7 +263,7 @@ func (u User) GetRecoveryCollection(ctx context.Context, client *http.Client, ur
 			defer response.Body.Close()
 
 			if response.StatusCode == http.StatusTooManyRequests {
-				slog.Info("Too many requests. Retrying...")
+				apiLogger().InfoContext(ctx, "Too many requests. Retrying...")
 				return errors.New("too many requests")
 			}
 
@@ -276,7 +276,7 @@ func (u User) GetRecoveryCollection(ctx context.Context, client *http.Client, ur
 
 			body, err := io.ReadAll(response.Body)
 			if err != nil {
-				slog.Error("unable to read response body from recovery collection payload", "msg", err)
+				apiLogger().ErrorContext(ctx, "unable to read response body from recovery collection payload", "msg", err)
 				return err
 			}
 
@@ -310,7 +310,7 @@ func (u User) GetRecoveryCollection(ctx context.Context, client *http.Client, ur
 		}
 	}
 
-	slog.Debug("Recovery Records", slog.Any("Recovery Records Count", len(recoveryRecords)))
+	apiLogger().DebugContext(ctx, "Recovery Records", slog.Any("Recovery Records Count", len(recoveryRecords)))
 
 	recovery.RecoveryRecords = recoveryRecords
 
@@ -334,13 +334,13 @@ func (u User) GetWorkoutCollection(ctx context.Context, client *http.Client, url
 	}
 
 	bo := generateBackoff()
-	slog.Info(("Requesting workout collection from Whoop API"))
+	apiLogger().InfoContext(ctx, "Requesting workout collection from Whoop API")
 	for continueLoop {
 
 		if nextLoopUrl == "" {
 			nextLoopUrl = urlWithFilters
 		}
-		slog.Debug("URL", slog.String("URL", nextLoopUrl))
+		apiLogger().DebugContext(ctx, "URL", slog.String("URL", nextLoopUrl))
 		req, err := http.NewRequestWithContext(context.Background(), method, nextLoopUrl, nil)
 		if err != nil {
 			LogError(err)
@@ -367,7 +367,7 @@ func (u User) GetWorkoutCollection(ctx context.Context, client *http.Client, url
 			defer response.Body.Close()
 
 			if response.StatusCode == http.StatusTooManyRequests {
-				slog.Info("Too many requests. Retrying...")
+				apiLogger().InfoContext(ctx, "Too many requests. Retrying...")
 				return errors.New("too many requests")
 			}
 
@@ -380,7 +380,7 @@ func (u User) GetWorkoutCollection(ctx context.Context, client *http.Client, url
 
 			body, err := io.ReadAll(response.Body)
 			if err != nil {
-				slog.Error("unable to read response body from workout collection payload", "msg", err)
+				apiLogger().ErrorContext(ctx, "unable to read response body from workout collection payload", "msg", err)
 				err = backoff.Permanent(err)
 				return err
 			}
@@ -392,7 +392,7 @@ func (u User) GetWorkoutCollection(ctx context.Context, client *http.Client, url
 			var workout WorkoutCollection
 			err = json.Unmarshal(body, &workout)
 			if err != nil {
-				slog.Debug("Workout", "data", workout)
+				apiLogger().DebugContext(ctx, "Workout", "data", workout)
 				LogError(err)
 				err = backoff.Permanent(err)
 				return err
@@ -416,7 +416,7 @@ func (u User) GetWorkoutCollection(ctx context.Context, client *http.Client, url
 		}
 	}
 
-	slog.Debug("Workout Records", slog.Any("Workout Records Count", len(workoutRecords)))
+	apiLogger().DebugContext(ctx, "Workout Records", slog.Any("Workout Records Count", len(workoutRecords)))
 
 	workout.Records = workoutRecords
 	return &workout, nil
@@ -437,13 +437,13 @@ func (u User) GetCycleCollection(ctx context.Context, client *http.Client, url,
 	}
 
 	bo := generateBackoff()
-	slog.Info(("Requesting cycle collection from Whoop API"))
+	apiLogger().InfoContext(ctx, "Requesting cycle collection from Whoop API")
 	for continueLoop {
 
 		if nextLoopUrl == "" {
 			nextLoopUrl = urlWithFilters
 		}
-		slog.Debug("URL", slog.String("URL", nextLoopUrl))
+		apiLogger().DebugContext(ctx, "URL", slog.String("URL", nextLoopUrl))
 		req, err := http.NewRequestWithContext(context.Background(), method, nextLoopUrl, nil)
 		if err != nil {
 			LogError(err)
@@ -477,7 +477,7 @@ func (u User) GetCycleCollection(ctx context.Context, client *http.Client, url,
 
 			body, err := io.ReadAll(response.Body)
 			if err != nil {
-				slog.Error("unable to read response body from cycle collection payload", "msg", err)
+				apiLogger().ErrorContext(ctx, "unable to read response body from cycle collection payload", "msg", err)
 				err = backoff.Permanent(err)
 				return err
 			}
@@ -513,7 +513,7 @@ func (u User) GetCycleCollection(ctx context.Context, client *http.Client, url,
 		}
 	}
 
-	slog.Debug("Cycle Records", slog.Any("Cycle Records Count", len(cycleRecords)))
+	apiLogger().DebugContext(ctx, "Cycle Records", slog.Any("Cycle Records Count", len(cycleRecords)))
 
 	cycle.Records = cycleRecords
 	return &cycle, nil
@@ -523,10 +523,10 @@ func (u User) GetCycleCollection(ctx context.Context, client *http.Client, url,
 func notification(err error, duration time.Duration) {
 
 	if err != nil {
-		slog.Error("error", "msg", err)
+		apiLogger().Error("error", "msg", err)
 	}
 
-	slog.Info("Error getting sleep records", "Retrying in: ", duration.String())
+	apiLogger().Info("Error getting sleep records", "Retrying in: ", duration.String())
 
 }
 
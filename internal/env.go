@@ -4,32 +4,33 @@
 package internal
 
 import (
-	"errors"
+	"fmt"
 	"os"
+	"strings"
 )
 
-// checkRequiredEnvVars checks if the required environment variables are set
+// ExtractEnvVariables extracts the configuration values provided through environment variables.
+// The Whoop client ID and client secret are read from WHOOP_CLIENT_ID and WHOOP_CLIENT_SECRET, or from the files referenced by
+// WHOOP_CLIENT_ID_FILE and WHOOP_CLIENT_SECRET_FILE, such as Docker or Kubernetes secrets.
 // A ConfigurationData struct is returned with the values of the environment variables
 func ExtractEnvVariables() (ConfigurationData, error) {
 
 	var output ConfigurationData
 
-	id := os.Getenv("WHOOP_CLIENT_ID")
-	secret := os.Getenv("WHOOP_CLIENT_SECRET")
-
-	credsFile := os.Getenv("WHOOP_CREDENTIALS_FILE")
-
-	switch {
-	case id == "" && secret == "":
-		return output, errors.New("the required env variables WHOOP_CLIENT_ID and WHOOP_CLIENT_SECRET are not set")
+	id, err := envOrFile("WHOOP_CLIENT_ID")
+	if err != nil {
+		return output, err
+	}
 
-	case id == "":
-		return output, errors.New("the required env variable WHOOP_CLIENT_ID is not set")
+	secret, err := envOrFile("WHOOP_CLIENT_SECRET")
+	if err != nil {
+		return output, err
+	}
 
-	case secret == "":
-		return output, errors.New("the required env variable WHOOP_CLIENT_SECRET is not set")
+	credsFile := os.Getenv("WHOOP_CREDENTIALS_FILE")
 
-	}
+	output.Credentials.ClientID = id
+	output.Credentials.ClientSecret = secret
 
 	if credsFile != "" {
 		output.Credentials.CredentialsFile = credsFile
@@ -37,3 +38,26 @@ func ExtractEnvVariables() (ConfigurationData, error) {
 
 	return output, nil
 }
+
+// envOrFile returns the value of the environment variable or the content of the file referenced by the <name>_FILE environment variable.
+// An error is returned if both environment variables are set.
+func envOrFile(name string) (string, error) {
+
+	value := os.Getenv(name)
+	file := os.Getenv(name + "_FILE")
+
+	switch {
+	case value != "" && file != "":
+		return "", fmt.Errorf("the env variables %s and %s_FILE are mutually exclusive", name, name)
+
+	case file != "":
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the file referenced by %s_FILE: %w", name, err)
+		}
+		return strings.TrimSpace(string(content)), nil
+
+	default:
+		return value, nil
+	}
+}
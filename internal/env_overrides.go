@@ -0,0 +1,156 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ENV_OVERRIDE_PREFIX is the prefix of the environment variables that override configuration file values.
+const ENV_OVERRIDE_PREFIX string = "MYWHOOP"
+
+// EnvOverride describes an environment variable that overrides a configuration file value.
+type EnvOverride struct {
+	// Name is the name of the environment variable, such as MYWHOOP_EXPORT_METHOD.
+	Name string
+	// Path is the location of the value in the configuration file, such as export.method.
+	Path string
+	// Type is the type of the value. Supported types are string, bool, int, number, and list. Number values are floating point numbers. List values are comma separated.
+	Type string
+	// index is the field index path in the ConfigurationData struct.
+	index []int
+}
+
+// EnvOverrides returns the environment variables that can override the configuration file values.
+// A variable is generated for every configurable field using the MYWHOOP_<SECTION>_<FIELD> format.
+// The accounts list is not covered by environment variables.
+func EnvOverrides() []EnvOverride {
+
+	var overrides []EnvOverride
+	collectEnvOverrides(reflect.TypeOf(ConfigurationData{}), []string{ENV_OVERRIDE_PREFIX}, nil, nil, &overrides)
+
+	return overrides
+}
+
+// collectEnvOverrides walks the struct type and appends an EnvOverride for every field with a supported type.
+func collectEnvOverrides(t reflect.Type, names, paths []string, index []int, overrides *[]EnvOverride) {
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := configFieldName(field)
+		if name == "" {
+			continue
+		}
+
+		fieldNames := append(append([]string{}, names...), envName(name))
+		fieldPaths := append(append([]string{}, paths...), name)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			collectEnvOverrides(field.Type, fieldNames, fieldPaths, fieldIndex, overrides)
+		case reflect.String, reflect.Bool, reflect.Int:
+			*overrides = append(*overrides, EnvOverride{
+				Name:  strings.Join(fieldNames, "_"),
+				Path:  strings.Join(fieldPaths, "."),
+				Type:  field.Type.Kind().String(),
+				index: fieldIndex,
+			})
+		case reflect.Float64:
+			*overrides = append(*overrides, EnvOverride{
+				Name:  strings.Join(fieldNames, "_"),
+				Path:  strings.Join(fieldPaths, "."),
+				Type:  "number",
+				index: fieldIndex,
+			})
+		case reflect.Slice:
+			if field.Type.Elem().Kind() != reflect.String {
+				continue
+			}
+			*overrides = append(*overrides, EnvOverride{
+				Name:  strings.Join(fieldNames, "_"),
+				Path:  strings.Join(fieldPaths, "."),
+				Type:  "list",
+				index: fieldIndex,
+			})
+		}
+	}
+}
+
+// envName converts a camel case configuration field name to the upper snake case used by environment variables.
+// For example, serverEndpoint is converted to SERVER_ENDPOINT and awsS3 is converted to AWS_S3.
+func envName(name string) string {
+
+	var out strings.Builder
+	runes := []rune(name)
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && !unicode.IsUpper(runes[i-1]) {
+			out.WriteRune('_')
+		}
+		out.WriteRune(unicode.ToUpper(r))
+	}
+
+	return out.String()
+}
+
+// ApplyEnvOverrides overrides the configuration values with the values of the MYWHOOP_<SECTION>_<FIELD> environment variables.
+// The names of the environment variables that were applied are returned. An error is returned if a value can't be converted to the type of the field.
+func ApplyEnvOverrides(cfg *ConfigurationData) ([]string, error) {
+
+	var applied []string
+	root := reflect.ValueOf(cfg).Elem()
+
+	for _, override := range EnvOverrides() {
+		value, ok := os.LookupEnv(override.Name)
+		if !ok {
+			continue
+		}
+
+		field := root.FieldByIndex(override.index)
+
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return applied, fmt.Errorf("invalid value for the env variable %s. A boolean value is expected: %w", override.Name, err)
+			}
+			field.SetBool(b)
+		case reflect.Int:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return applied, fmt.Errorf("invalid value for the env variable %s. An integer value is expected: %w", override.Name, err)
+			}
+			field.SetInt(int64(n))
+		case reflect.Float64:
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return applied, fmt.Errorf("invalid value for the env variable %s. A number is expected: %w", override.Name, err)
+			}
+			field.SetFloat(f)
+		case reflect.Slice:
+			var values []string
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					values = append(values, item)
+				}
+			}
+			field.Set(reflect.ValueOf(values))
+		}
+
+		applied = append(applied, override.Name)
+	}
+
+	cfg.Debug = strings.ToUpper(cfg.Debug)
+
+	return applied, nil
+}
@@ -0,0 +1,190 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+
+	tests := []struct {
+		description string
+		input       string
+		expected    string
+	}{
+		{
+			description: "Single word",
+			input:       "method",
+			expected:    "METHOD",
+		},
+		{
+			description: "Camel case",
+			input:       "serverEndpoint",
+			expected:    "SERVER_ENDPOINT",
+		},
+		{
+			description: "Acronym suffix",
+			input:       "subscriptionID",
+			expected:    "SUBSCRIPTION_ID",
+		},
+		{
+			description: "Digits",
+			input:       "awsS3",
+			expected:    "AWS_S3",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got := envName(test.input)
+			if got != test.expected {
+				t.Errorf("Expected %s but got %s", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+
+	expected := map[string]string{
+		"MYWHOOP_DEBUG":                             "debug",
+		"MYWHOOP_EXPORT_METHOD":                     "export.method",
+		"MYWHOOP_EXPORT_AWS_S3_BUCKET":              "export.awsS3.bucket",
+		"MYWHOOP_NOTIFICATION_NTFY_SERVER_ENDPOINT": "notification.ntfy.serverEndpoint",
+		"MYWHOOP_SERVER_JWT_REFRESH_DURATION":       "server.jwtRefreshDuration",
+		"MYWHOOP_CREDENTIALS_CREDENTIALS_FILE":      "credentials.credentialsFile",
+	}
+
+	overrides := EnvOverrides()
+	got := make(map[string]string)
+	for _, override := range overrides {
+		got[override.Name] = override.Path
+	}
+
+	for name, path := range expected {
+		if got[name] != path {
+			t.Errorf("Expected %s to override %s but got %s", name, path, got[name])
+		}
+	}
+
+	for _, override := range overrides {
+		if strings.HasPrefix(override.Path, "accounts") {
+			t.Errorf("Expected the accounts list to be excluded but got %s", override.Name)
+		}
+		if override.Name == "MYWHOOP_NOTIFICATION_NTFY_PASSWORD" || override.Name == "MYWHOOP_NOTIFICATION_NTFY_ACCESS_TOKEN" {
+			t.Errorf("Expected secrets excluded from the configuration file to be excluded but got %s", override.Name)
+		}
+	}
+}
+
+func TestApplyEnvOverrides(t *testing.T) {
+
+	cfg, err := readConfigFileYaml("../tests/valid_config.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read the Yaml file. Expected no error but received %v", err)
+	}
+
+	t.Setenv("MYWHOOP_EXPORT_FILE_EXPORT_FILE_PATH", "/opt/mywhoop/data/")
+	t.Setenv("MYWHOOP_SERVER_ENABLED", "true")
+	t.Setenv("MYWHOOP_SERVER_JWT_REFRESH_DURATION", "30")
+	t.Setenv("MYWHOOP_DEBUG", "warn")
+	t.Setenv("MYWHOOP_NOTIFICATION_EMAIL_TO", "alice@example.com, bob@example.com,")
+	t.Setenv("MYWHOOP_ALERTS_SKIN_TEMP_DEVIATION", "0.8")
+
+	applied, err := ApplyEnvOverrides(&cfg)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	if len(applied) != 6 {
+		t.Errorf("Expected 6 overrides to be applied but got %v", applied)
+	}
+
+	if cfg.Alerts.SkinTempDeviation != 0.8 {
+		t.Errorf("Expected 0.8 but got %v", cfg.Alerts.SkinTempDeviation)
+	}
+
+	if len(cfg.Notification.Email.To) != 2 || cfg.Notification.Email.To[1] != "bob@example.com" {
+		t.Errorf("Expected the two email recipients but got %v", cfg.Notification.Email.To)
+	}
+
+	if cfg.Export.FileExport.FilePath != "/opt/mywhoop/data/" {
+		t.Errorf("Expected /opt/mywhoop/data/ but got %s", cfg.Export.FileExport.FilePath)
+	}
+
+	// Values not provided through the environment are preserved
+	if cfg.Export.FileExport.FileName != "user" {
+		t.Errorf("Expected user but got %s", cfg.Export.FileExport.FileName)
+	}
+
+	if !cfg.Server.Enabled {
+		t.Errorf("Expected the server to be enabled")
+	}
+
+	if cfg.Server.JWTRefreshDuration != 30 {
+		t.Errorf("Expected 30 but got %d", cfg.Server.JWTRefreshDuration)
+	}
+
+	if cfg.Debug != "WARN" {
+		t.Errorf("Expected WARN but got %s", cfg.Debug)
+	}
+}
+
+func TestApplyEnvOverridesErrors(t *testing.T) {
+
+	tests := []struct {
+		description string
+		name        string
+		value       string
+	}{
+		{
+			description: "Invalid boolean",
+			name:        "MYWHOOP_SERVER_ENABLED",
+			value:       "maybe",
+		},
+		{
+			description: "Invalid integer",
+			name:        "MYWHOOP_SERVER_JWT_REFRESH_DURATION",
+			value:       "45m",
+		},
+		{
+			description: "Invalid number",
+			name:        "MYWHOOP_ALERTS_RECOVERY_BELOW",
+			value:       "33%",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			t.Setenv(test.name, test.value)
+
+			var cfg ConfigurationData
+			_, err := ApplyEnvOverrides(&cfg)
+			if err == nil {
+				t.Fatalf("Expected an error but got nil")
+			}
+
+			if !strings.Contains(err.Error(), test.name) {
+				t.Errorf("Expected the error to reference %s but got %s", test.name, err.Error())
+			}
+		})
+	}
+}
+
+func TestEnvOverridesDocumented(t *testing.T) {
+
+	docs, err := os.ReadFile("../docs/environment_variables.md")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	for _, override := range EnvOverrides() {
+		if !strings.Contains(string(docs), "`"+override.Name+"`") {
+			t.Errorf("The env variable %s is not documented in docs/environment_variables.md", override.Name)
+		}
+	}
+}
@@ -5,24 +5,21 @@ package internal
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 )
 
-func TestExtractEnvVariablesError(t *testing.T) {
+func TestExtractEnvVariablesEmpty(t *testing.T) {
 
-	os.Unsetenv("WHOOP_CLIENT_ID")
-	os.Unsetenv("WHOOP_CLIENT_SECRET")
-	os.Unsetenv("WHOOP_CREDENTIALS_FILE")
+	cleanUpEnvVars()
 
-	expectedMsg := "the required env variables WHOOP_CLIENT_ID and WHOOP_CLIENT_SECRET are not set"
-	_, err := ExtractEnvVariables()
-	if err == nil {
-		t.Log(err)
-		t.Errorf("Expected  an error, but got %v", err)
+	cfg, err := ExtractEnvVariables()
+	if err != nil {
+		t.Errorf("Expected no error as the client credentials can be provided through the configuration file, but got %v", err)
 	}
 
-	if err.Error() != expectedMsg {
-		t.Errorf("Expected the following error message:  %s - but got: %s", expectedMsg, err.Error())
+	if cfg.Credentials.ClientID != "" || cfg.Credentials.ClientSecret != "" {
+		t.Errorf("Expected empty client credentials but got %v", cfg.Credentials)
 	}
 
 	cleanUpEnvVars()
@@ -33,39 +30,66 @@ func TestExtractEnvVariablesClient(t *testing.T) {
 	cleanUpEnvVars()
 
 	os.Setenv("WHOOP_CLIENT_ID", "AAAAAAAAAAAAAAAAAAA")
-	expectedMsg := "the required env variable WHOOP_CLIENT_SECRET is not set"
-	_, err := ExtractEnvVariables()
+	os.Setenv("WHOOP_CLIENT_SECRET", "BBBBBBBBBBBBBBBBBBBBB")
 
-	if err == nil {
-		t.Errorf("Expected  an error, but got %v", err)
+	cfg, err := ExtractEnvVariables()
+	if err != nil {
+		t.Errorf("Expected no error but got %v", err)
 	}
 
-	if err.Error() != expectedMsg {
-		t.Errorf("Expected the following error message:  %s - but got: %s", expectedMsg, err.Error())
+	if cfg.Credentials.ClientID != "AAAAAAAAAAAAAAAAAAA" {
+		t.Errorf("Expected AAAAAAAAAAAAAAAAAAA but got %s", cfg.Credentials.ClientID)
+	}
+
+	if cfg.Credentials.ClientSecret != "BBBBBBBBBBBBBBBBBBBBB" {
+		t.Errorf("Expected BBBBBBBBBBBBBBBBBBBBB but got %s", cfg.Credentials.ClientSecret)
 	}
 
 	cleanUpEnvVars()
 }
 
-func TestExtractEnvVariablesClientSecret(t *testing.T) {
+func TestExtractEnvVariablesClientFile(t *testing.T) {
 
 	cleanUpEnvVars()
 
-	os.Setenv("WHOOP_CLIENT_SECRET", "BBBBBBBBBBBBBBBBBBBBB")
-	expectedMsg := "the required env variable WHOOP_CLIENT_ID is not set"
-	_, err := ExtractEnvVariables()
+	secretFile := filepath.Join(t.TempDir(), "whoop_client_secret")
+	err := os.WriteFile(secretFile, []byte("BBBBBBBBBBBBBBBBBBBBB\n"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to create the secret file: %v", err)
+	}
+
+	os.Setenv("WHOOP_CLIENT_ID", "AAAAAAAAAAAAAAAAAAA")
+	os.Setenv("WHOOP_CLIENT_SECRET_FILE", secretFile)
+
+	cfg, err := ExtractEnvVariables()
+	if err != nil {
+		t.Errorf("Expected no error but got %v", err)
+	}
 
+	if cfg.Credentials.ClientSecret != "BBBBBBBBBBBBBBBBBBBBB" {
+		t.Errorf("Expected BBBBBBBBBBBBBBBBBBBBB but got %s", cfg.Credentials.ClientSecret)
+	}
+
+	// The env variable and the file variant are mutually exclusive
+	os.Setenv("WHOOP_CLIENT_SECRET", "BBBBBBBBBBBBBBBBBBBBB")
+	expectedMsg := "the env variables WHOOP_CLIENT_SECRET and WHOOP_CLIENT_SECRET_FILE are mutually exclusive"
+	_, err = ExtractEnvVariables()
 	if err == nil {
-		t.Log(err)
-		t.Errorf("Expected  an error, but got %v", err)
+		t.Fatalf("Expected an error, but got %v", err)
 	}
 
 	if err.Error() != expectedMsg {
 		t.Errorf("Expected the following error message:  %s - but got: %s", expectedMsg, err.Error())
 	}
 
-	cleanUpEnvVars()
+	os.Unsetenv("WHOOP_CLIENT_SECRET")
+	os.Setenv("WHOOP_CLIENT_SECRET_FILE", filepath.Join(t.TempDir(), "missing"))
+	_, err = ExtractEnvVariables()
+	if err == nil {
+		t.Errorf("Expected an error due to a missing secret file, but got %v", err)
+	}
 
+	cleanUpEnvVars()
 }
 
 func TestExtractEnvVariablesCredsFileEmpty(t *testing.T) {
@@ -112,5 +136,7 @@ func cleanUpEnvVars() {
 	os.Unsetenv("WHOOP_CLIENT_ID")
 	os.Unsetenv("WHOOP_CLIENT_SECRET")
 	os.Unsetenv("WHOOP_CREDENTIALS_FILE")
+	os.Unsetenv("WHOOP_CLIENT_ID_FILE")
+	os.Unsetenv("WHOOP_CLIENT_SECRET_FILE")
 
 }
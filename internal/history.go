@@ -0,0 +1,258 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// RunStatusSuccess is the status of a job run that completed.
+	RunStatusSuccess string = "success"
+	// RunStatusFailed is the status of a job run that gave up after its retries.
+	RunStatusFailed string = "failed"
+	// RunStatusSkipped is the status of a job run skipped by the circuit breaker.
+	RunStatusSkipped string = "skipped"
+)
+
+// RunRecord is the record of a server job run stored in the run history.
+type RunRecord struct {
+	// ID is the unique identifier of the run.
+	ID string `json:"id"`
+	// Job is the name of the job.
+	Job string `json:"job"`
+	// Account is the name of the account of the job. Empty for the default account.
+	Account string `json:"account,omitempty"`
+	// Status is the status of the run. Allowed values are success, failed, and skipped.
+	Status string `json:"status"`
+	// Start is the time the run started.
+	Start time.Time `json:"start"`
+	// End is the time the run ended.
+	End time.Time `json:"end"`
+	// Attempts is the number of attempts of the run.
+	Attempts int `json:"attempts"`
+	// CatchUp is true if the run collected the data of a scheduled run missed while the server was not running.
+	CatchUp bool `json:"catchUp,omitempty"`
+	// Window is the time window of the data fetched from the Whoop API. Nil if the run does not fetch data.
+	Window *RunWindow `json:"window,omitempty"`
+	// Records contains the number of records fetched per collection, such as sleep or recovery.
+	Records map[string]int `json:"records,omitempty"`
+	// Export is the result of the data export. Nil if no data was exported.
+	Export *RunExport `json:"export,omitempty"`
+	// Notifications contains the results of the notifications sent by the run.
+	Notifications []RunNotification `json:"notifications,omitempty"`
+	// Error is the error of the run. Empty if the run completed.
+	Error string `json:"error,omitempty"`
+}
+
+// RunWindow is the time window of the data fetched by a run.
+type RunWindow struct {
+	// Start is the start of the window.
+	Start string `json:"start"`
+	// End is the end of the window.
+	End string `json:"end"`
+}
+
+// RunExport is the result of the data export of a run.
+type RunExport struct {
+	// Method is the export method, such as file or s3.
+	Method string `json:"method"`
+	// Destination is the directory or the S3 bucket the data is exported to.
+	Destination string `json:"destination"`
+	// Key is the file name or the S3 object key of the exported data.
+	Key string `json:"key"`
+	// Bytes is the number of bytes written.
+	Bytes int `json:"bytes"`
+}
+
+// RunNotification is the result of a notification sent by a run.
+type RunNotification struct {
+	// Event is the event of the notification.
+	Event string `json:"event"`
+	// Delivered is true if the notification was sent.
+	Delivered bool `json:"delivered"`
+	// Error is the error of the notification. Empty if the notification was sent.
+	Error string `json:"error,omitempty"`
+}
+
+// Duration returns the duration of the run.
+func (r RunRecord) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// AddNotification records the result of a notification sent by the run.
+func (r *RunRecord) AddNotification(event string, err error) {
+
+	result := RunNotification{
+		Event:     event,
+		Delivered: err == nil,
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	r.Notifications = append(r.Notifications, result)
+}
+
+// History is the run history of the server jobs. The runs are appended to a JSON lines file.
+type History struct {
+	// path is the path of the history file.
+	path string
+	// mu serializes the writes of the jobs sharing the history.
+	mu sync.Mutex
+}
+
+// NewHistory returns the run history using the configuration. Nil is returned if the run history is disabled.
+func NewHistory(cfg HistoryConfig) *History {
+
+	if cfg.Disabled {
+		return nil
+	}
+
+	return &History{
+		path: HistoryFile(cfg),
+	}
+}
+
+// HistoryFile returns the path of the history file. The default history file is used if no file is configured.
+func HistoryFile(cfg HistoryConfig) string {
+
+	if cfg.File == "" {
+		return DEFAULT_HISTORY_FILE
+	}
+
+	return cfg.File
+}
+
+// Append appends the run to the history file. The directory of the history file is created if it does not exist.
+func (h *History) Append(record RunRecord) error {
+
+	if h == nil {
+		return nil
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	dir := filepath.Dir(h.path)
+	if dir != "." {
+		err = os.MkdirAll(dir, 0750)
+		if err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// ReadHistory reads the runs of the history file in the order they were recorded. A missing history file contains no runs.
+// Invalid lines, such as a line truncated by a crash, are skipped.
+func ReadHistory(filePath string) ([]RunRecord, error) {
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []RunRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record RunRecord
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			slog.Warn("invalid run in the history file skipped", "file", filePath, "line", line, "error", err)
+			continue
+		}
+		records = append(records, record)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the history file %s: %w", filePath, err)
+	}
+
+	return records, nil
+}
+
+// HistoryFilter contains the criteria used to filter the runs of the history. Empty criteria match every run.
+type HistoryFilter struct {
+	// Job matches the runs of the job.
+	Job string
+	// Account matches the runs of the account.
+	Account string
+	// Status matches the runs with the status.
+	Status string
+	// Since matches the runs started at or after the time.
+	Since time.Time
+	// Limit is the maximum number of runs returned. The most recent runs are returned. Zero returns every matching run.
+	Limit int
+}
+
+// FilterRuns returns the runs matching the filter, sorted from the most recent to the oldest.
+func FilterRuns(records []RunRecord, filter HistoryFilter) []RunRecord {
+
+	var matched []RunRecord
+	for _, record := range records {
+		if filter.Job != "" && record.Job != filter.Job {
+			continue
+		}
+		if filter.Account != "" && record.Account != filter.Account {
+			continue
+		}
+		if filter.Status != "" && record.Status != filter.Status {
+			continue
+		}
+		if !filter.Since.IsZero() && record.Start.Before(filter.Since) {
+			continue
+		}
+		matched = append(matched, record)
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Start.After(matched[j].Start)
+	})
+
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+
+	return matched
+}
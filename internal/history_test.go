@@ -0,0 +1,137 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewHistory(t *testing.T) {
+
+	if NewHistory(HistoryConfig{Disabled: true}) != nil {
+		t.Error("expected no history when the history is disabled")
+	}
+
+	h := NewHistory(HistoryConfig{})
+	if h == nil || h.path != DEFAULT_HISTORY_FILE {
+		t.Errorf("expected the default history file, got %+v", h)
+	}
+
+	h = NewHistory(HistoryConfig{File: "/var/lib/mywhoop/history.jsonl"})
+	if h == nil || h.path != "/var/lib/mywhoop/history.jsonl" {
+		t.Errorf("expected the configured history file, got %+v", h)
+	}
+
+	// A nil history ignores the runs
+	var disabled *History
+	err := disabled.Append(RunRecord{})
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestHistoryAppendAndRead(t *testing.T) {
+
+	filePath := filepath.Join(t.TempDir(), "state", "history.jsonl")
+	h := NewHistory(HistoryConfig{File: filePath})
+
+	start := time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)
+	run := RunRecord{
+		ID:       "1",
+		Job:      "mywhoop_data_collection_job",
+		Status:   RunStatusSuccess,
+		Start:    start,
+		End:      start.Add(5 * time.Second),
+		Attempts: 1,
+		Records:  map[string]int{"sleep": 1},
+		Export:   &RunExport{Method: "file", Destination: "data", Key: "user.json", Bytes: 42},
+	}
+	run.AddNotification("success", nil)
+	run.AddNotification("alerts", errors.New("ntfy unavailable"))
+
+	err := h.Append(run)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = h.Append(RunRecord{ID: "2", Job: "mywhoop_token_refresh_job", Status: RunStatusFailed, Start: start.Add(time.Hour), Error: "unauthorized"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A truncated line is skipped
+	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = f.WriteString("{\"id\":\"3\",\"job\"")
+	f.Close()
+
+	records, err := ReadHistory(filePath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(records))
+	}
+
+	got := records[0]
+	if got.ID != "1" || got.Duration() != 5*time.Second || got.Export.Bytes != 42 || got.Records["sleep"] != 1 {
+		t.Errorf("unexpected run %+v", got)
+	}
+
+	if len(got.Notifications) != 2 || !got.Notifications[0].Delivered || got.Notifications[1].Delivered || got.Notifications[1].Error != "ntfy unavailable" {
+		t.Errorf("unexpected notifications %+v", got.Notifications)
+	}
+
+	records, err = ReadHistory(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil || len(records) != 0 {
+		t.Errorf("expected no runs and no error for a missing file, got %v and %v", records, err)
+	}
+}
+
+func TestFilterRuns(t *testing.T) {
+
+	start := time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)
+	records := []RunRecord{
+		{ID: "1", Job: "mywhoop_data_collection_job", Status: RunStatusSuccess, Start: start},
+		{ID: "2", Job: "mywhoop_token_refresh_job", Status: RunStatusFailed, Start: start.Add(time.Hour)},
+		{ID: "3", Job: "mywhoop_data_collection_job", Account: "alice", Status: RunStatusFailed, Start: start.Add(24 * time.Hour)},
+		{ID: "4", Job: "mywhoop_data_collection_job", Status: RunStatusSkipped, Start: start.Add(48 * time.Hour)},
+	}
+
+	tests := []struct {
+		name     string
+		filter   HistoryFilter
+		expected []string
+	}{
+		{name: "no filter", filter: HistoryFilter{}, expected: []string{"4", "3", "2", "1"}},
+		{name: "job", filter: HistoryFilter{Job: "mywhoop_data_collection_job"}, expected: []string{"4", "3", "1"}},
+		{name: "account", filter: HistoryFilter{Account: "alice"}, expected: []string{"3"}},
+		{name: "status", filter: HistoryFilter{Status: RunStatusFailed}, expected: []string{"3", "2"}},
+		{name: "since", filter: HistoryFilter{Since: start.Add(time.Hour)}, expected: []string{"4", "3", "2"}},
+		{name: "limit", filter: HistoryFilter{Limit: 2}, expected: []string{"4", "3"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			runs := FilterRuns(records, tc.filter)
+
+			if len(runs) != len(tc.expected) {
+				t.Fatalf("expected %d runs, got %d", len(tc.expected), len(runs))
+			}
+
+			for i, id := range tc.expected {
+				if runs[i].ID != id {
+					t.Errorf("expected run %s at position %d, got %s", id, i, runs[i].ID)
+				}
+			}
+		})
+	}
+}
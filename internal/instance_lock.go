@@ -0,0 +1,100 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrInstanceLocked is returned by AcquireInstanceLock when another server holds the lock.
+var ErrInstanceLocked = errors.New("another MyWhoop server is running")
+
+// InstanceLockFile returns the path of the lock file. If no file is configured, the default lock file is created in the directory of the credentials file,
+// so that the servers sharing a credentials file share the lock regardless of their working directory.
+func InstanceLockFile(cfg InstanceLock, credentialsFile string) string {
+
+	if cfg.File == "" {
+		return filepath.Join(filepath.Dir(credentialsFile), DEFAULT_SERVER_LOCK_FILE)
+	}
+
+	return cfg.File
+}
+
+// AcquireInstanceLock acquires the single-instance lock of the server without waiting and writes the process ID to the lock file.
+// The default lock file is created next to the credentials file.
+// ErrInstanceLocked is returned if another server holds the lock. Nil is returned if the lock is disabled.
+// The lock is released by the operating system if the process exits without releasing it, so a stale lock file does not prevent the server from starting.
+func AcquireInstanceLock(cfg InstanceLock, credentialsFile string) (*FileLock, error) {
+
+	if cfg.Disabled {
+		return nil, nil
+	}
+
+	filePath := InstanceLockFile(cfg, credentialsFile)
+
+	dir := filepath.Dir(filePath)
+	if dir != "." {
+		err := os.MkdirAll(dir, 0750)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the lock file %s: %w", filePath, err)
+	}
+
+	err = tryLockFile(f)
+	if err != nil {
+		f.Close()
+
+		if !errors.Is(err, errFileLocked) {
+			return nil, fmt.Errorf("unable to lock %s: %w", filePath, err)
+		}
+
+		pid := lockHolder(filePath)
+		if pid == "" {
+			return nil, fmt.Errorf("%w. The lock file %s is held by another process", ErrInstanceLocked, filePath)
+		}
+
+		return nil, fmt.Errorf("%w with the process ID %s. The lock file %s is held by the process", ErrInstanceLocked, pid, filePath)
+	}
+
+	lock := &FileLock{file: f}
+
+	err = f.Truncate(0)
+	if err == nil {
+		_, err = f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	if err != nil {
+		lock.Unlock()
+		return nil, fmt.Errorf("unable to write the process ID to the lock file %s: %w", filePath, err)
+	}
+
+	return lock, nil
+}
+
+// lockHolder returns the process ID written to the lock file. An empty string is returned if the process ID can't be read,
+// such as on Windows where the locked region of the file can't be read by other processes.
+func lockHolder(filePath string) string {
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return ""
+	}
+
+	pid := strings.TrimSpace(string(content))
+	_, err = strconv.Atoi(pid)
+	if err != nil {
+		return ""
+	}
+
+	return pid
+}
@@ -0,0 +1,84 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestInstanceLockFile(t *testing.T) {
+
+	if got := InstanceLockFile(InstanceLock{}, DEFAULT_CREDENTIALS_FILE); got != DEFAULT_SERVER_LOCK_FILE {
+		t.Errorf("Expected the default lock file but got: %s", got)
+	}
+
+	expected := filepath.Join("/var/lib/mywhoop", DEFAULT_SERVER_LOCK_FILE)
+	if got := InstanceLockFile(InstanceLock{}, "/var/lib/mywhoop/token.json"); got != expected {
+		t.Errorf("Expected the lock file next to the credentials file but got: %s", got)
+	}
+
+	if got := InstanceLockFile(InstanceLock{File: "/run/mywhoop/mywhoop.lock"}, DEFAULT_CREDENTIALS_FILE); got != "/run/mywhoop/mywhoop.lock" {
+		t.Errorf("Expected the configured lock file but got: %s", got)
+	}
+}
+
+func TestAcquireInstanceLock(t *testing.T) {
+
+	cfg := InstanceLock{File: filepath.Join(t.TempDir(), "run", "mywhoop.lock")}
+
+	lock, err := AcquireInstanceLock(cfg, DEFAULT_CREDENTIALS_FILE)
+	if err != nil {
+		t.Fatalf("Failed to acquire the lock: %v", err)
+	}
+
+	content, err := os.ReadFile(cfg.File)
+	if err != nil {
+		t.Fatalf("Failed to read the lock file: %v", err)
+	}
+
+	if strings.TrimSpace(string(content)) != strconv.Itoa(os.Getpid()) {
+		t.Errorf("Expected the process ID in the lock file but got: %q", content)
+	}
+
+	// A second server can't acquire the lock
+	_, err = AcquireInstanceLock(cfg, DEFAULT_CREDENTIALS_FILE)
+	if !errors.Is(err, ErrInstanceLocked) {
+		t.Fatalf("Expected ErrInstanceLocked but got: %v", err)
+	}
+
+	err = lock.Unlock()
+	if err != nil {
+		t.Errorf("Failed to release the lock: %v", err)
+	}
+
+	// The lock file left by the previous server does not prevent the server from starting
+	lock, err = AcquireInstanceLock(cfg, DEFAULT_CREDENTIALS_FILE)
+	if err != nil {
+		t.Fatalf("Failed to acquire the released lock: %v", err)
+	}
+
+	err = lock.Unlock()
+	if err != nil {
+		t.Errorf("Failed to release the lock: %v", err)
+	}
+}
+
+func TestAcquireInstanceLockDisabled(t *testing.T) {
+
+	lock, err := AcquireInstanceLock(InstanceLock{Disabled: true, File: filepath.Join(t.TempDir(), "mywhoop.lock")}, DEFAULT_CREDENTIALS_FILE)
+	if err != nil || lock != nil {
+		t.Errorf("Expected no lock when the lock is disabled but got: %v, %v", lock, err)
+	}
+
+	// Releasing a disabled lock is a no-op
+	err = lock.Unlock()
+	if err != nil {
+		t.Errorf("Expected no error but got: %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ENV_STRICT_INTERPOLATION is the environment variable that makes the configuration file interpolation fail on undefined variables.
+const ENV_STRICT_INTERPOLATION string = "MYWHOOP_STRICT_ENV"
+
+// interpolationRegex matches the $$ escape sequence, ${VAR}, and ${VAR:-default} references.
+var interpolationRegex = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// InterpolateEnv replaces the ${VAR} and ${VAR:-default} references in the values of the configuration file content with the values of the environment variables.
+// The content is decoded first, so references in YAML comments and in field names are left untouched, and the interpolated values are encoded again
+// with the quoting they require. Only the string values of JSON files are interpolated.
+// The default value is used if the variable is undefined or empty. Use $$ to write a literal $ character.
+// If strict is true, an error listing the undefined variables without a default value is returned. Otherwise, undefined variables are replaced with an empty string.
+func InterpolateEnv(content []byte, fileType string, strict bool) ([]byte, error) {
+
+	var (
+		undefined []string
+		out       []byte
+		err       error
+	)
+
+	switch fileType {
+	case "yaml":
+		out, err = interpolateYaml(content, &undefined)
+	case "json":
+		out, err = interpolateJson(content, &undefined)
+	default:
+		return nil, fmt.Errorf("unsupported file type %q", fileType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if strict && len(undefined) > 0 {
+		return nil, fmt.Errorf("the configuration file references undefined environment variables: %s", strings.Join(undefined, ", "))
+	}
+
+	return out, nil
+}
+
+// interpolateYaml interpolates the scalar values of the YAML content and encodes the content again.
+// The content is returned unchanged if it does not reference any variable.
+func interpolateYaml(content []byte, undefined *[]string) ([]byte, error) {
+
+	var root yaml.Node
+	err := yaml.Unmarshal(content, &root)
+	if err != nil {
+		return nil, err
+	}
+
+	if !interpolateYamlNode(&root, undefined) {
+		return content, nil
+	}
+
+	return yaml.Marshal(&root)
+}
+
+// interpolateYamlNode interpolates the scalar values of the node tree. The mapping keys are not interpolated.
+// The tag of an untagged plain scalar is resolved again from the interpolated value, so that ${PORT} can provide a number.
+// Returns true if a value changed.
+func interpolateYamlNode(node *yaml.Node, undefined *[]string) bool {
+
+	changed := false
+
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			changed = interpolateYamlNode(child, undefined) || changed
+		}
+
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			changed = interpolateYamlNode(node.Content[i], undefined) || changed
+		}
+
+	case yaml.ScalarNode:
+		value := expandEnv(node.Value, undefined)
+		if value == node.Value {
+			return false
+		}
+		node.Value = value
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+		changed = true
+	}
+
+	return changed
+}
+
+// interpolateJson interpolates the string values of the JSON content and encodes the content again.
+// The content is returned unchanged if it does not reference any variable.
+func interpolateJson(content []byte, undefined *[]string) ([]byte, error) {
+
+	var value any
+
+	dc := json.NewDecoder(bytes.NewReader(content))
+	dc.UseNumber()
+	err := dc.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+
+	value, changed := interpolateJsonValue(value, undefined)
+	if !changed {
+		return content, nil
+	}
+
+	return json.MarshalIndent(value, "", "  ")
+}
+
+// interpolateJsonValue interpolates the string values of the decoded JSON value. The object keys are not interpolated.
+// Returns the interpolated value and true if a value changed.
+func interpolateJsonValue(value any, undefined *[]string) (any, bool) {
+
+	changed := false
+
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			item, itemChanged := interpolateJsonValue(item, undefined)
+			v[key] = item
+			changed = itemChanged || changed
+		}
+
+	case []any:
+		for i, item := range v {
+			item, itemChanged := interpolateJsonValue(item, undefined)
+			v[i] = item
+			changed = itemChanged || changed
+		}
+
+	case string:
+		expanded := expandEnv(v, undefined)
+		return expanded, expanded != v
+	}
+
+	return value, changed
+}
+
+// expandEnv replaces the $$ escape sequences and the variable references in the value. The undefined variables without a default value are appended to undefined.
+func expandEnv(value string, undefined *[]string) string {
+
+	if !strings.Contains(value, "$") {
+		return value
+	}
+
+	return interpolationRegex.ReplaceAllStringFunc(value, func(match string) string {
+
+		if match == "$$" {
+			return "$"
+		}
+
+		groups := interpolationRegex.FindStringSubmatch(match)
+		name := groups[1]
+		hasDefault := groups[2] != ""
+
+		envValue, ok := os.LookupEnv(name)
+		switch {
+		case hasDefault && envValue == "":
+			return groups[3]
+		case !ok:
+			*undefined = append(*undefined, name)
+			return ""
+		default:
+			return envValue
+		}
+	})
+}
+
+// strictInterpolation returns true if the MYWHOOP_STRICT_ENV environment variable enables strict interpolation.
+func strictInterpolation() (bool, error) {
+
+	value := os.Getenv(ENV_STRICT_INTERPOLATION)
+	if value == "" {
+		return false, nil
+	}
+
+	strict, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for the env variable %s. A boolean value is expected: %w", ENV_STRICT_INTERPOLATION, err)
+	}
+
+	return strict, nil
+}
+
+// readConfigFileContent reads the configuration file and interpolates the environment variable references.
+func readConfigFileContent(file, fileType string) ([]byte, error) {
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	strict, err := strictInterpolation()
+	if err != nil {
+		return nil, err
+	}
+
+	return InterpolateEnv(content, fileType, strict)
+}
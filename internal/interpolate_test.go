@@ -0,0 +1,229 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestExpandEnv(t *testing.T) {
+
+	t.Setenv("MYWHOOP_TEST_BUCKET", "my-bucket")
+	t.Setenv("MYWHOOP_TEST_EMPTY", "")
+	os.Unsetenv("MYWHOOP_TEST_UNDEFINED")
+
+	tests := []struct {
+		description string
+		input       string
+		expected    string
+		undefined   []string
+	}{
+		{
+			description: "Defined variable",
+			input:       "${MYWHOOP_TEST_BUCKET}",
+			expected:    "my-bucket",
+		},
+		{
+			description: "Default value for an undefined variable",
+			input:       "${MYWHOOP_TEST_UNDEFINED:-fallback}",
+			expected:    "fallback",
+		},
+		{
+			description: "Default value for an empty variable",
+			input:       "${MYWHOOP_TEST_EMPTY:-fallback}",
+			expected:    "fallback",
+		},
+		{
+			description: "Empty default value",
+			input:       "${MYWHOOP_TEST_UNDEFINED:-}",
+			expected:    "",
+		},
+		{
+			description: "Defined variable ignores the default value",
+			input:       "${MYWHOOP_TEST_BUCKET:-fallback}",
+			expected:    "my-bucket",
+		},
+		{
+			description: "Undefined variable",
+			input:       "s3://${MYWHOOP_TEST_UNDEFINED}",
+			expected:    "s3://",
+			undefined:   []string{"MYWHOOP_TEST_UNDEFINED"},
+		},
+		{
+			description: "Empty variable is defined",
+			input:       "${MYWHOOP_TEST_EMPTY}",
+			expected:    "",
+		},
+		{
+			description: "Escaped dollar sign",
+			input:       "pa$$word ${MYWHOOP_TEST_BUCKET}",
+			expected:    "pa$word my-bucket",
+		},
+		{
+			description: "Bare variables are not expanded",
+			input:       "$MYWHOOP_TEST_BUCKET",
+			expected:    "$MYWHOOP_TEST_BUCKET",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var undefined []string
+			got := expandEnv(test.input, &undefined)
+
+			if got != test.expected {
+				t.Errorf("Expected %s but got %s", test.expected, got)
+			}
+
+			if strings.Join(undefined, ",") != strings.Join(test.undefined, ",") {
+				t.Errorf("Expected the undefined variables %v but got %v", test.undefined, undefined)
+			}
+		})
+	}
+}
+
+func TestInterpolateEnv(t *testing.T) {
+
+	t.Setenv("MYWHOOP_TEST_BUCKET", "my-bucket")
+	t.Setenv("MYWHOOP_TEST_PORT", "8080")
+	t.Setenv("MYWHOOP_TEST_QUOTED", "a: \"b\" # c\nd")
+	os.Unsetenv("MYWHOOP_TEST_UNDEFINED")
+
+	type document struct {
+		Bucket string `yaml:"bucket" json:"bucket"`
+		Port   int    `yaml:"port" json:"port"`
+		Text   string `yaml:"text" json:"text"`
+	}
+
+	tests := []struct {
+		description   string
+		input         string
+		fileType      string
+		strict        bool
+		expected      document
+		errorExpected bool
+	}{
+		{
+			description: "YAML values",
+			input:       "bucket: ${MYWHOOP_TEST_BUCKET}\nport: ${MYWHOOP_TEST_PORT}\n",
+			fileType:    "yaml",
+			expected:    document{Bucket: "my-bucket", Port: 8080},
+		},
+		{
+			description: "YAML value requiring quotes",
+			input:       "bucket: ${MYWHOOP_TEST_BUCKET}\ntext: ${MYWHOOP_TEST_QUOTED}\n",
+			fileType:    "yaml",
+			expected:    document{Bucket: "my-bucket", Text: "a: \"b\" # c\nd"},
+		},
+		{
+			description: "YAML comments are not interpolated in strict mode",
+			input:       "# bucket: ${MYWHOOP_TEST_UNDEFINED}\nbucket: ${MYWHOOP_TEST_BUCKET} # ${MYWHOOP_TEST_UNDEFINED}\n",
+			fileType:    "yaml",
+			strict:      true,
+			expected:    document{Bucket: "my-bucket"},
+		},
+		{
+			description:   "YAML undefined variable in strict mode",
+			input:         "bucket: ${MYWHOOP_TEST_UNDEFINED}\n",
+			fileType:      "yaml",
+			strict:        true,
+			errorExpected: true,
+		},
+		{
+			description: "JSON string values",
+			input:       "{\"bucket\": \"${MYWHOOP_TEST_BUCKET}\", \"port\": 8080, \"text\": \"${MYWHOOP_TEST_QUOTED}\"}",
+			fileType:    "json",
+			expected:    document{Bucket: "my-bucket", Port: 8080, Text: "a: \"b\" # c\nd"},
+		},
+		{
+			description:   "JSON undefined variable in strict mode",
+			input:         "{\"bucket\": \"${MYWHOOP_TEST_UNDEFINED}\"}",
+			fileType:      "json",
+			strict:        true,
+			errorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			got, err := InterpolateEnv([]byte(test.input), test.fileType, test.strict)
+			if test.errorExpected {
+				if err == nil {
+					t.Fatalf("Expected an error but got nil")
+				}
+				if !strings.Contains(err.Error(), "MYWHOOP_TEST_UNDEFINED") {
+					t.Errorf("Expected the error to list the undefined variable but got %s", err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+
+			var doc document
+			if test.fileType == "yaml" {
+				err = yaml.Unmarshal(got, &doc)
+			} else {
+				err = json.Unmarshal(got, &doc)
+			}
+			if err != nil {
+				t.Fatalf("Expected the interpolated content to decode but got %v: \n%s", err, string(got))
+			}
+
+			if doc != test.expected {
+				t.Errorf("Expected %+v but got %+v", test.expected, doc)
+			}
+		})
+	}
+}
+
+func TestGenerateConfigStructInterpolation(t *testing.T) {
+
+	t.Setenv("MYWHOOP_TEST_NTFY", "https://ntfy.example.com")
+	t.Setenv("MYWHOOP_TEST_SECRET", "BBBBBBBBBBBBBBBBBBBBB")
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	content := "credentials:\n  clientSecret: ${MYWHOOP_TEST_SECRET}\nexport:\n  method: file\n  fileExport:\n    filePath: ${MYWHOOP_TEST_PATH:-data/}\nnotification:\n  method: ntfy\n  ntfy:\n    serverEndpoint: ${MYWHOOP_TEST_NTFY}\n    subscriptionID: ${MYWHOOP_TEST_TOPIC}\n"
+	err := os.WriteFile(configFile, []byte(content), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+
+	cfg, err := GenerateConfigStruct(configFile)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	if cfg.Notification.Ntfy.ServerEndpoint != "https://ntfy.example.com" {
+		t.Errorf("Expected https://ntfy.example.com but got %s", cfg.Notification.Ntfy.ServerEndpoint)
+	}
+
+	if cfg.Export.FileExport.FilePath != "data/" {
+		t.Errorf("Expected data/ but got %s", cfg.Export.FileExport.FilePath)
+	}
+
+	// Interpolated secrets must be redacted
+	if RedactSecrets(cfg).Credentials.ClientSecret != REDACTED_VALUE {
+		t.Errorf("Expected the interpolated client secret to be redacted")
+	}
+
+	t.Setenv(ENV_STRICT_INTERPOLATION, "true")
+	_, err = GenerateConfigStruct(configFile)
+	if err == nil || !strings.Contains(err.Error(), "MYWHOOP_TEST_TOPIC") {
+		t.Errorf("Expected an error listing MYWHOOP_TEST_TOPIC in strict mode but got %v", err)
+	}
+
+	t.Setenv(ENV_STRICT_INTERPOLATION, "maybe")
+	_, err = GenerateConfigStruct(configFile)
+	if err == nil {
+		t.Errorf("Expected an error due to the invalid %s value", ENV_STRICT_INTERPOLATION)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// DEFAULT_DATA_COLLECTION_JOB is the name of the data collection job used when no jobs are configured.
+const DEFAULT_DATA_COLLECTION_JOB string = "mywhoop_data_collection_job"
+
+var (
+	// reservedJobNames contains the names of the built-in server jobs. A configured job can't use these names.
+	reservedJobNames = []string{
+		DEFAULT_DATA_COLLECTION_JOB,
+		"mywhoop_token_refresh_job",
+		"mywhoop_startup_token_refresh_job",
+		"mywhoop_notification_flush_job",
+	}
+	// Collections contains the collections of the Whoop API fetched by the data collection jobs.
+	Collections = []string{"sleep", "recovery", "workout", "cycle"}
+)
+
+// ResolveJobs returns the data collection jobs to schedule. If no jobs are configured, a single unnamed job
+// collecting every collection of the last 24 hours using the server crontab is returned.
+func (c ConfigurationData) ResolveJobs() []ServerJob {
+
+	if len(c.Server.Jobs) == 0 {
+		return []ServerJob{{}}
+	}
+
+	return c.Server.Jobs
+}
+
+// JobName returns the name of the job. The unnamed job is the default data collection job.
+func (j ServerJob) JobName() string {
+
+	if j.Name == "" {
+		return DEFAULT_DATA_COLLECTION_JOB
+	}
+
+	return j.Name
+}
+
+// Schedule returns the crontab of the job. The crontab of the server is used if the job has no crontab.
+func (j ServerJob) Schedule(server Server) string {
+
+	if j.Crontab != "" {
+		return j.Crontab
+	}
+
+	if server.Crontab != "" {
+		return server.Crontab
+	}
+
+	return DEFAULT_SERVER_CRON_SCHEDULE
+}
+
+// WindowDuration returns the lookback window of the job. Default is 24 hours.
+func (j ServerJob) WindowDuration() time.Duration {
+
+	if j.Window <= 0 {
+		return 24 * time.Hour
+	}
+
+	return time.Duration(j.Window) * time.Hour
+}
+
+// SendsSummary returns true if the daily summary is added to the success notification of the job.
+// By default, only the jobs collecting the data of a day send the summary, so that the summary is not repeated by shorter or longer jobs.
+func (j ServerJob) SendsSummary() bool {
+
+	if j.Summary != nil {
+		return *j.Summary
+	}
+
+	return j.WindowDuration() == 24*time.Hour
+}
+
+// EvaluatesAlerts returns true if the health alerts are evaluated against the data collected by the job.
+// By default, only the jobs collecting the data of a day evaluate the alerts, so that the same alerts are not raised by every job.
+func (j ServerJob) EvaluatesAlerts() bool {
+
+	if j.Alerts != nil {
+		return *j.Alerts
+	}
+
+	return j.WindowDuration() == 24*time.Hour
+}
+
+// CollectionSet returns the collections fetched by the job. Every collection is fetched if the job has no collections.
+func (j ServerJob) CollectionSet() []string {
+
+	if len(j.Collections) == 0 {
+		return Collections
+	}
+
+	return j.Collections
+}
+
+// ForJob returns the configuration of the job. The job export and format settings take precedence over the account settings.
+// The exported files of named jobs are prefixed with the job name, so that the jobs do not overwrite each other's data.
+// The exported files of the job export are prefixed with the account name for named accounts.
+func (c ConfigurationData) ForJob(job ServerJob, accountName string) ConfigurationData {
+
+	cfg := c
+
+	if job.Name == "" {
+		return cfg
+	}
+
+	if job.Export != nil {
+		cfg.Export = *job.Export
+
+		if accountName != "" {
+			cfg.Export.FileExport.FileNamePrefix = accountPrefix(accountName, cfg.Export.FileExport.FileNamePrefix)
+			cfg.Export.AWSS3.FileConfig.FileNamePrefix = accountPrefix(accountName, cfg.Export.AWSS3.FileConfig.FileNamePrefix)
+		}
+	}
+
+	if job.Format != "" {
+		cfg.Export.FileExport.FileType = job.Format
+		cfg.Export.AWSS3.FileConfig.FileType = job.Format
+	}
+
+	cfg.Export.FileExport.FileNamePrefix = jobPrefix(cfg.Export.FileExport.FileNamePrefix, job.Name)
+	cfg.Export.AWSS3.FileConfig.FileNamePrefix = jobPrefix(cfg.Export.AWSS3.FileConfig.FileNamePrefix, job.Name)
+
+	return cfg
+}
+
+// jobPrefix returns the file name prefix followed by the job name.
+func jobPrefix(prefix, name string) string {
+
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "_" + name
+}
+
+// validateServerCrontab validates the crontab of the server.
+func validateServerCrontab(config ConfigurationData) error {
+
+	if config.Server.Crontab == "" {
+		return nil
+	}
+
+	_, err := cron.ParseStandard(config.Server.Crontab)
+	if err != nil {
+		return fmt.Errorf("invalid server crontab %q: %w", config.Server.Crontab, err)
+	}
+
+	return nil
+}
+
+// validateJobs validates the job names and the crontabs of the jobs.
+func validateJobs(config ConfigurationData) error {
+
+	for _, job := range config.Server.Jobs {
+
+		if !accountNameRegex.MatchString(job.Name) {
+			return fmt.Errorf("invalid job name %q. Only letters, numbers, dashes and underscores are allowed", job.Name)
+		}
+
+		if slices.Contains(reservedJobNames, job.Name) {
+			return fmt.Errorf("the job name %q is reserved for a built-in server job", job.Name)
+		}
+
+		if job.Crontab != "" {
+			_, err := cron.ParseStandard(job.Crontab)
+			if err != nil {
+				return fmt.Errorf("invalid crontab %q of the job %q: %w", job.Crontab, job.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/karl-cardenas-coding/mywhoop/export"
+)
+
+func TestResolveJobs(t *testing.T) {
+
+	cfg := ConfigurationData{}
+
+	got := cfg.ResolveJobs()
+	if len(got) != 1 || got[0].JobName() != DEFAULT_DATA_COLLECTION_JOB {
+		t.Fatalf("Expected the default data collection job but got %v", got)
+	}
+
+	if got[0].Schedule(Server{Crontab: "0 6 * * *"}) != "0 6 * * *" {
+		t.Errorf("Expected the default job to use the server crontab but got %s", got[0].Schedule(Server{Crontab: "0 6 * * *"}))
+	}
+
+	if got[0].Schedule(Server{}) != DEFAULT_SERVER_CRON_SCHEDULE {
+		t.Errorf("Expected the default crontab but got %s", got[0].Schedule(Server{}))
+	}
+
+	cfg.Server.Jobs = []ServerJob{{Name: "hourly", Crontab: "0 * * * *"}, {Name: "weekly"}}
+	got = cfg.ResolveJobs()
+	if len(got) != 2 || got[0].JobName() != "hourly" || got[0].Schedule(cfg.Server) != "0 * * * *" {
+		t.Errorf("Expected the configured jobs but got %v", got)
+	}
+}
+
+func TestServerJobDefaults(t *testing.T) {
+
+	job := ServerJob{}
+	if job.WindowDuration() != 24*time.Hour {
+		t.Errorf("Expected a 24 hours window but got %s", job.WindowDuration())
+	}
+
+	if !slices.Equal(job.CollectionSet(), Collections) {
+		t.Errorf("Expected every collection but got %v", job.CollectionSet())
+	}
+
+	job = ServerJob{Window: 6, Collections: []string{"sleep", "recovery"}}
+	if job.WindowDuration() != 6*time.Hour {
+		t.Errorf("Expected a 6 hours window but got %s", job.WindowDuration())
+	}
+
+	if !slices.Equal(job.CollectionSet(), []string{"sleep", "recovery"}) {
+		t.Errorf("Expected the configured collections but got %v", job.CollectionSet())
+	}
+}
+
+func TestServerJobSummaryAndAlerts(t *testing.T) {
+
+	enabled, disabled := true, false
+
+	tests := []struct {
+		description string
+		job         ServerJob
+		expected    bool
+	}{
+		{description: "Default job", job: ServerJob{}, expected: true},
+		{description: "Daily job", job: ServerJob{Name: "daily", Window: 24}, expected: true},
+		{description: "Hourly job", job: ServerJob{Name: "hourly", Window: 6}, expected: false},
+		{description: "Weekly job", job: ServerJob{Name: "weekly", Window: 720}, expected: false},
+		{description: "Hourly job enabled", job: ServerJob{Name: "hourly", Window: 6, Summary: &enabled, Alerts: &enabled}, expected: true},
+		{description: "Daily job disabled", job: ServerJob{Name: "daily", Summary: &disabled, Alerts: &disabled}, expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if test.job.SendsSummary() != test.expected {
+				t.Errorf("Expected the summary switch to be %t", test.expected)
+			}
+
+			if test.job.EvaluatesAlerts() != test.expected {
+				t.Errorf("Expected the alerts switch to be %t", test.expected)
+			}
+		})
+	}
+}
+
+func TestForJob(t *testing.T) {
+
+	cfg := ConfigurationData{
+		Export: ConfigExport{
+			Method: "file",
+			FileExport: export.FileExport{
+				FilePath:       "data/",
+				FileType:       "json",
+				FileNamePrefix: "alice_daily",
+			},
+		},
+	}
+
+	defaultJob := cfg.ForJob(ServerJob{}, "alice")
+	if defaultJob.Export.FileExport.FileNamePrefix != "alice_daily" {
+		t.Errorf("Expected the default job to keep the account prefix but got %s", defaultJob.Export.FileExport.FileNamePrefix)
+	}
+
+	hourly := cfg.ForJob(ServerJob{Name: "hourly", Format: "xlsx"}, "alice")
+	if hourly.Export.FileExport.FileNamePrefix != "alice_daily_hourly" {
+		t.Errorf("Expected alice_daily_hourly but got %s", hourly.Export.FileExport.FileNamePrefix)
+	}
+
+	if hourly.Export.FileExport.FileType != "xlsx" {
+		t.Errorf("Expected the xlsx format override but got %s", hourly.Export.FileExport.FileType)
+	}
+
+	weekly := cfg.ForJob(ServerJob{
+		Name: "weekly",
+		Export: &ConfigExport{
+			Method: "s3",
+			AWSS3: export.AWS_S3{
+				Bucket: "reconciliation",
+			},
+		},
+	}, "alice")
+	if weekly.Export.Method != "s3" || weekly.Export.AWSS3.FileConfig.FileNamePrefix != "alice_weekly" {
+		t.Errorf("Expected the s3 export override with the alice_weekly prefix but got %v", weekly.Export)
+	}
+
+	// The account configuration must not be modified
+	if cfg.Export.FileExport.FileNamePrefix != "alice_daily" || cfg.Export.FileExport.FileType != "json" {
+		t.Errorf("Expected the account export to be unchanged but got %v", cfg.Export.FileExport)
+	}
+}
+
+func TestValidateJobs(t *testing.T) {
+
+	tests := []struct {
+		description   string
+		jobs          []ServerJob
+		errorExpected bool
+	}{
+		{
+			description:   "Test valid jobs",
+			jobs:          []ServerJob{{Name: "hourly", Crontab: "0 * * * *", Window: 6}, {Name: "weekly-reconciliation", Crontab: "0 3 * * 0", Window: 720}},
+			errorExpected: false,
+		},
+		{
+			description:   "Test invalid job name",
+			jobs:          []ServerJob{{Name: "hourly job"}},
+			errorExpected: true,
+		},
+		{
+			description:   "Test reserved job name",
+			jobs:          []ServerJob{{Name: "mywhoop_token_refresh_job"}},
+			errorExpected: true,
+		},
+		{
+			description:   "Test invalid crontab",
+			jobs:          []ServerJob{{Name: "hourly", Crontab: "every hour"}},
+			errorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			err := validateJobs(ConfigurationData{Server: Server{Jobs: test.jobs}})
+			if err != nil && !test.errorExpected {
+				t.Errorf("Expected no error but got %v", err)
+			}
+
+			if err == nil && test.errorExpected {
+				t.Errorf("Expected an error but got none")
+			}
+		})
+	}
+}
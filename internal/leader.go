@@ -0,0 +1,234 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrLeaseConflict is returned by a lease store when a conditional write fails because another replica updated the lease.
+	ErrLeaseConflict = errors.New("the lease was updated by another replica")
+	// ErrNotLeader is returned by the elector when another replica holds the lease.
+	ErrNotLeader = errors.New("this replica is not the leader")
+)
+
+// Lease is the leader election lease stored in the lease store.
+type Lease struct {
+	// Holder is the identity of the replica holding the lease.
+	Holder string `json:"holder"`
+	// RenewedAt is the time the lease was last renewed.
+	RenewedAt time.Time `json:"renewedAt"`
+	// ExpiresAt is the time the lease expires if it is not renewed.
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// leaseStore stores the lease with conditional writes. The version is an opaque value, such as an S3 ETag, that changes on every write.
+type leaseStore interface {
+	// Read returns the lease and its version. False is returned if no lease exists.
+	Read(ctx context.Context) (Lease, string, bool, error)
+	// Create creates the lease if no lease exists and returns the version of the lease. ErrLeaseConflict is returned if a lease exists.
+	Create(ctx context.Context, lease Lease) (string, error)
+	// Replace replaces the lease if its version matches and returns the new version. ErrLeaseConflict is returned if the version does not match.
+	Replace(ctx context.Context, lease Lease, version string) (string, error)
+	// Delete deletes the lease if its version matches.
+	Delete(ctx context.Context, version string) error
+}
+
+// LeaseElector elects a single leader among the replicas of the server using a lease. The replica holding an unexpired lease is the leader.
+// The leader renews the lease every third of the lease duration. The other replicas acquire the lease once it expires.
+// The elector implements the gocron Elector interface, so the scheduler only runs the jobs on the leader.
+type LeaseElector struct {
+	// store is the store of the lease.
+	store leaseStore
+	// identity identifies the replica in the lease.
+	identity string
+	// duration is the time the lease is valid without renewal.
+	duration time.Duration
+	// now returns the current time.
+	now func() time.Time
+
+	// mu protects version and expiresAt.
+	mu sync.Mutex
+	// version is the version of the lease held by the replica.
+	version string
+	// expiresAt is the time the lease held by the replica expires. Zero if the replica is not the leader.
+	expiresAt time.Time
+}
+
+// newLeaseElector returns an elector using the lease store and the configuration. Default values are used for the unset settings.
+func newLeaseElector(store leaseStore, cfg LeaderElection) *LeaseElector {
+
+	e := &LeaseElector{
+		store:    store,
+		identity: cfg.Identity,
+		duration: DEFAULT_LEADER_LEASE_DURATION,
+		now:      time.Now,
+	}
+
+	if e.identity == "" {
+		e.identity = defaultIdentity()
+	}
+
+	if cfg.LeaseDuration > 0 {
+		e.duration = time.Duration(cfg.LeaseDuration) * time.Second
+	}
+
+	return e
+}
+
+// defaultIdentity returns the host name followed by the process ID.
+func defaultIdentity() string {
+
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "mywhoop"
+	}
+
+	return host + "-" + strconv.Itoa(os.Getpid())
+}
+
+// Identity returns the identity of the replica.
+func (e *LeaseElector) Identity() string {
+	return e.identity
+}
+
+// IsLeader returns nil if the replica holds the lease. The replica attempts to acquire the lease if it is not the leader.
+func (e *LeaseElector) IsLeader(ctx context.Context) error {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.leading() {
+		return nil
+	}
+
+	return e.acquire(ctx)
+}
+
+// Run renews the lease every third of the lease duration until the context is cancelled. The lease is released when the context is cancelled,
+// so that another replica takes over without waiting for the lease to expire.
+func (e *LeaseElector) Run(ctx context.Context) {
+
+	ticker := time.NewTicker(e.duration / 3)
+	defer ticker.Stop()
+
+	e.renew(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-ticker.C:
+			e.renew(ctx)
+		}
+	}
+}
+
+// renew acquires or renews the lease. Errors are logged as the lease is renewed again at the next tick.
+func (e *LeaseElector) renew(ctx context.Context) {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err := e.acquire(ctx)
+	if err != nil && !errors.Is(err, ErrNotLeader) {
+		slog.Error("unable to renew the leader election lease", "identity", e.identity, "error", err)
+	}
+}
+
+// leading returns true if the replica holds an unexpired lease. The caller must hold the mutex.
+func (e *LeaseElector) leading() bool {
+	return !e.expiresAt.IsZero() && e.now().Before(e.expiresAt)
+}
+
+// acquire creates the lease, renews the lease held by the replica, or takes over an expired lease.
+// ErrNotLeader is returned if another replica holds the lease. The caller must hold the mutex.
+func (e *LeaseElector) acquire(ctx context.Context) error {
+
+	wasLeader := e.leading()
+
+	current, version, found, err := e.store.Read(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to read the leader election lease: %w", err)
+	}
+
+	now := e.now()
+	lease := Lease{
+		Holder:    e.identity,
+		RenewedAt: now,
+		ExpiresAt: now.Add(e.duration),
+	}
+
+	switch {
+	case !found:
+		version, err = e.store.Create(ctx, lease)
+	case current.Holder == e.identity || !now.Before(current.ExpiresAt):
+		version, err = e.store.Replace(ctx, lease, version)
+	default:
+		e.lost(wasLeader, current.Holder)
+		return fmt.Errorf("%w. The lease is held by %s until %s", ErrNotLeader, current.Holder, current.ExpiresAt.Format(time.RFC3339))
+	}
+
+	if errors.Is(err, ErrLeaseConflict) {
+		e.lost(wasLeader, "")
+		return fmt.Errorf("%w. %s", ErrNotLeader, err)
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to write the leader election lease: %w", err)
+	}
+
+	e.version = version
+	e.expiresAt = lease.ExpiresAt
+
+	if !wasLeader {
+		slog.Info("Leader election lease acquired. This replica runs the server jobs", "identity", e.identity, "expires_at", lease.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// lost clears the lease held by the replica. The caller must hold the mutex.
+func (e *LeaseElector) lost(wasLeader bool, holder string) {
+
+	if wasLeader {
+		slog.Warn("Leader election lease lost. The server jobs are skipped on this replica", "identity", e.identity, "holder", holder)
+	}
+
+	e.version = ""
+	e.expiresAt = time.Time{}
+}
+
+// release deletes the lease held by the replica.
+func (e *LeaseElector) release() {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.leading() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.duration/3)
+	defer cancel()
+
+	err := e.store.Delete(ctx, e.version)
+	if err != nil {
+		slog.Error("unable to release the leader election lease", "identity", e.identity, "error", err)
+	} else {
+		slog.Info("Leader election lease released", "identity", e.identity)
+	}
+
+	e.version = ""
+	e.expiresAt = time.Time{}
+}
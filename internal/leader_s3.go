@@ -0,0 +1,202 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// s3LeaseAPI contains the S3 operations used by the S3 lease store.
+type s3LeaseAPI interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+// s3LeaseStore stores the lease in an S3 object. The lease is created with the If-None-Match header and replaced or deleted with the If-Match header,
+// so that S3 rejects the write if another replica updated the lease since it was read. The ETag of the object is the version of the lease.
+type s3LeaseStore struct {
+	// client is the S3 client.
+	client s3LeaseAPI
+	// bucket is the bucket of the lease object.
+	bucket string
+	// key is the key of the lease object.
+	key string
+}
+
+// NewS3LeaseElector returns a lease elector storing the lease in the S3 bucket of the configuration.
+func NewS3LeaseElector(ctx context.Context, cfg LeaderElection, client *http.Client) (*LeaseElector, error) {
+
+	if cfg.Bucket == "" {
+		return nil, errors.New("the S3 bucket of the leader election is required")
+	}
+
+	opts := []func(*config.LoadOptions) error{
+		config.WithHTTPClient(client),
+	}
+
+	if cfg.Region != "" {
+		opts = append(opts, config.WithRegion(cfg.Region))
+	}
+
+	// The AWS_PROFILE environment variable takes precedence over the configured profile
+	profile := cfg.Profile
+	envValue := os.Getenv("AWS_PROFILE")
+	if envValue != "" {
+		profile = envValue
+	}
+
+	if profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the AWS configuration of the leader election: %w", err)
+	}
+
+	s3Client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return newLeaseElector(newS3LeaseStore(s3Client, cfg), cfg), nil
+}
+
+// newS3LeaseStore returns the S3 lease store of the configuration. The default key is used if no key is configured.
+func newS3LeaseStore(client s3LeaseAPI, cfg LeaderElection) *s3LeaseStore {
+
+	key := cfg.Key
+	if key == "" {
+		key = DEFAULT_LEADER_ELECTION_KEY
+	}
+
+	return &s3LeaseStore{
+		client: client,
+		bucket: cfg.Bucket,
+		key:    key,
+	}
+}
+
+// Read returns the lease stored in the S3 object and the ETag of the object.
+func (s *s3LeaseStore) Read(ctx context.Context) (Lease, string, bool, error) {
+
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return Lease{}, "", false, nil
+		}
+		return Lease{}, "", false, err
+	}
+	defer out.Body.Close()
+
+	content, err := io.ReadAll(out.Body)
+	if err != nil {
+		return Lease{}, "", false, err
+	}
+
+	var lease Lease
+	err = json.Unmarshal(content, &lease)
+	if err != nil {
+		// An invalid lease is replaced as if it expired
+		lease = Lease{}
+	}
+
+	return lease, aws.ToString(out.ETag), true, nil
+}
+
+// Create creates the S3 object if it does not exist.
+func (s *s3LeaseStore) Create(ctx context.Context, lease Lease) (string, error) {
+
+	return s.put(ctx, lease, func(input *s3.PutObjectInput) []func(*s3.Options) {
+		input.IfNoneMatch = aws.String("*")
+		return nil
+	})
+}
+
+// Replace replaces the S3 object if its ETag matches the version.
+func (s *s3LeaseStore) Replace(ctx context.Context, lease Lease, version string) (string, error) {
+
+	return s.put(ctx, lease, func(input *s3.PutObjectInput) []func(*s3.Options) {
+		return []func(*s3.Options){withIfMatch(version)}
+	})
+}
+
+// Delete deletes the S3 object if its ETag matches the version.
+func (s *s3LeaseStore) Delete(ctx context.Context, version string) error {
+
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+	}, withIfMatch(version))
+
+	return leaseError(err)
+}
+
+// put writes the lease to the S3 object. The condition sets the conditional write of the request.
+func (s *s3LeaseStore) put(ctx context.Context, lease Lease, condition func(input *s3.PutObjectInput) []func(*s3.Options)) (string, error) {
+
+	content, err := json.Marshal(lease)
+	if err != nil {
+		return "", err
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.key),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String("application/json"),
+	}
+
+	out, err := s.client.PutObject(ctx, input, condition(input)...)
+	if err != nil {
+		return "", leaseError(err)
+	}
+
+	return aws.ToString(out.ETag), nil
+}
+
+// withIfMatch adds the If-Match header to the request. The header is set through the middleware as the S3 client version in use does not expose it.
+func withIfMatch(etag string) func(*s3.Options) {
+	return func(o *s3.Options) {
+		o.APIOptions = append(o.APIOptions, smithyhttp.SetHeaderValue("If-Match", etag))
+	}
+}
+
+// leaseError returns ErrLeaseConflict if S3 rejected the conditional write.
+func leaseError(err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	var responseErr *smithyhttp.ResponseError
+	if errors.As(err, &responseErr) {
+		switch responseErr.HTTPStatusCode() {
+		case http.StatusPreconditionFailed, http.StatusConflict, http.StatusNotFound:
+			return fmt.Errorf("%w: %s", ErrLeaseConflict, err)
+		}
+	}
+
+	return err
+}
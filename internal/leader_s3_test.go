@@ -0,0 +1,186 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	testcontainers "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/localstack"
+)
+
+// mockS3LeaseAPI returns the configured responses of the S3 operations.
+type mockS3LeaseAPI struct {
+	getOut *s3.GetObjectOutput
+	getErr error
+	putErr error
+}
+
+func (m *mockS3LeaseAPI) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	return m.getOut, m.getErr
+}
+
+func (m *mockS3LeaseAPI) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	if m.putErr != nil {
+		return nil, m.putErr
+	}
+	return &s3.PutObjectOutput{ETag: aws.String(`"etag"`)}, nil
+}
+
+func (m *mockS3LeaseAPI) DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+// responseError returns an S3 error with the HTTP status code.
+func responseError(status int) error {
+	return &smithyhttp.ResponseError{
+		Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+		Err:      errors.New("api error"),
+	}
+}
+
+func TestS3LeaseStoreRead(t *testing.T) {
+
+	store := newS3LeaseStore(&mockS3LeaseAPI{getErr: &types.NoSuchKey{}}, LeaderElection{Bucket: "mywhoop"})
+	if store.key != DEFAULT_LEADER_ELECTION_KEY {
+		t.Errorf("Expected the default key but got: %s", store.key)
+	}
+
+	_, _, found, err := store.Read(context.Background())
+	if err != nil || found {
+		t.Errorf("Expected no lease but got: %v, %v", found, err)
+	}
+
+	store.client = &mockS3LeaseAPI{getOut: &s3.GetObjectOutput{
+		Body: io.NopCloser(strings.NewReader(`{"holder":"replica-a","expiresAt":"2024-06-01T13:01:00Z"}`)),
+		ETag: aws.String(`"etag"`),
+	}}
+
+	lease, version, found, err := store.Read(context.Background())
+	if err != nil || !found || lease.Holder != "replica-a" || version != `"etag"` {
+		t.Errorf("Unexpected lease: %+v, %s, %v, %v", lease, version, found, err)
+	}
+}
+
+func TestLeaseError(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		err      error
+		conflict bool
+	}{
+		{name: "precondition failed", err: responseError(http.StatusPreconditionFailed), conflict: true},
+		{name: "conditional request conflict", err: responseError(http.StatusConflict), conflict: true},
+		{name: "access denied", err: responseError(http.StatusForbidden)},
+		{name: "network error", err: errors.New("connection refused")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := leaseError(tc.err)
+			if errors.Is(err, ErrLeaseConflict) != tc.conflict {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+
+	if leaseError(nil) != nil {
+		t.Error("Expected no error")
+	}
+
+	store := newS3LeaseStore(&mockS3LeaseAPI{putErr: responseError(http.StatusPreconditionFailed)}, LeaderElection{Bucket: "mywhoop", Key: "leases/mywhoop.json"})
+	_, err := store.Create(context.Background(), Lease{Holder: "replica-a"})
+	if !errors.Is(err, ErrLeaseConflict) {
+		t.Errorf("Expected ErrLeaseConflict but got: %v", err)
+	}
+}
+
+func TestS3LeaseElection(t *testing.T) {
+
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+
+	localstackContainer, err := localstack.Run(ctx,
+		"localstack/localstack:4.0",
+		testcontainers.WithEnv(map[string]string{
+			"SERVICES": "s3"}),
+	)
+	if err != nil {
+		t.Fatalf("failed to start container: %s", err)
+	}
+
+	defer func() {
+		if err := localstackContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	}()
+
+	endpoint, err := localstackContainer.PortEndpoint(ctx, "4566/tcp", "http")
+	if err != nil {
+		t.Fatalf("failed to get the LocalStack endpoint: %s", err)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_PROFILE", "")
+
+	cfg := LeaderElection{
+		Enabled:       true,
+		Bucket:        "mywhoop",
+		Region:        "us-east-1",
+		Endpoint:      endpoint,
+		LeaseDuration: 30,
+	}
+
+	cfg.Identity = "replica-a"
+	a, err := NewS3LeaseElector(ctx, cfg, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("failed to create the elector: %s", err)
+	}
+
+	cfg.Identity = "replica-b"
+	b, err := NewS3LeaseElector(ctx, cfg, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("failed to create the elector: %s", err)
+	}
+
+	_, err = a.store.(*s3LeaseStore).client.(*s3.Client).CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String(cfg.Bucket)})
+	if err != nil {
+		t.Fatalf("failed to create the bucket: %s", err)
+	}
+
+	err = a.IsLeader(ctx)
+	if err != nil {
+		t.Fatalf("Expected replica-a to acquire the lease but got: %v", err)
+	}
+
+	err = b.IsLeader(ctx)
+	if !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("Expected replica-b not to be the leader but got: %v", err)
+	}
+
+	// A stale version is rejected by the conditional write
+	_, err = b.store.Replace(ctx, Lease{Holder: "replica-b"}, `"stale"`)
+	if !errors.Is(err, ErrLeaseConflict) {
+		t.Fatalf("Expected ErrLeaseConflict but got: %v", err)
+	}
+
+	a.renew(ctx)
+	a.release()
+
+	err = b.IsLeader(ctx)
+	if err != nil {
+		t.Fatalf("Expected replica-b to acquire the released lease but got: %v", err)
+	}
+}
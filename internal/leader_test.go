@@ -0,0 +1,228 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryLeaseStore is a lease store with the conditional write semantics of S3.
+type memoryLeaseStore struct {
+	mu       sync.Mutex
+	lease    *Lease
+	version  int
+	readErr  error
+	replaced int
+}
+
+func (m *memoryLeaseStore) Read(ctx context.Context) (Lease, string, bool, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.readErr != nil {
+		return Lease{}, "", false, m.readErr
+	}
+
+	if m.lease == nil {
+		return Lease{}, "", false, nil
+	}
+
+	return *m.lease, strconv.Itoa(m.version), true, nil
+}
+
+func (m *memoryLeaseStore) Create(ctx context.Context, lease Lease) (string, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.lease != nil {
+		return "", ErrLeaseConflict
+	}
+
+	m.lease = &lease
+	m.version++
+
+	return strconv.Itoa(m.version), nil
+}
+
+func (m *memoryLeaseStore) Replace(ctx context.Context, lease Lease, version string) (string, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.lease == nil || version != strconv.Itoa(m.version) {
+		return "", ErrLeaseConflict
+	}
+
+	m.lease = &lease
+	m.version++
+	m.replaced++
+
+	return strconv.Itoa(m.version), nil
+}
+
+func (m *memoryLeaseStore) Delete(ctx context.Context, version string) error {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.lease == nil || version != strconv.Itoa(m.version) {
+		return ErrLeaseConflict
+	}
+
+	m.lease = nil
+
+	return nil
+}
+
+func TestNewLeaseElector(t *testing.T) {
+
+	e := newLeaseElector(&memoryLeaseStore{}, LeaderElection{})
+	if e.duration != DEFAULT_LEADER_LEASE_DURATION || e.Identity() == "" {
+		t.Errorf("Expected the default settings but got: %s, %s", e.duration, e.Identity())
+	}
+
+	e = newLeaseElector(&memoryLeaseStore{}, LeaderElection{Identity: "replica-a", LeaseDuration: 30})
+	if e.duration != 30*time.Second || e.Identity() != "replica-a" {
+		t.Errorf("Expected the configured settings but got: %s, %s", e.duration, e.Identity())
+	}
+}
+
+func TestLeaseElector(t *testing.T) {
+
+	store := &memoryLeaseStore{}
+	now := time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	a := newLeaseElector(store, LeaderElection{Identity: "replica-a", LeaseDuration: 60})
+	a.now = clock
+	b := newLeaseElector(store, LeaderElection{Identity: "replica-b", LeaseDuration: 60})
+	b.now = clock
+
+	ctx := context.Background()
+
+	err := a.IsLeader(ctx)
+	if err != nil {
+		t.Fatalf("Expected replica-a to acquire the lease but got: %v", err)
+	}
+
+	err = b.IsLeader(ctx)
+	if !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("Expected replica-b not to be the leader but got: %v", err)
+	}
+
+	// The leader renews the lease before it expires
+	now = now.Add(50 * time.Second)
+	a.renew(ctx)
+	if store.lease.Holder != "replica-a" || store.replaced != 1 {
+		t.Fatalf("Expected replica-a to renew the lease but got: %+v", store.lease)
+	}
+
+	now = now.Add(50 * time.Second)
+	err = b.IsLeader(ctx)
+	if !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("Expected the renewed lease to be held by replica-a but got: %v", err)
+	}
+
+	// replica-b takes over the lease once it expires
+	now = now.Add(2 * time.Minute)
+	err = b.IsLeader(ctx)
+	if err != nil {
+		t.Fatalf("Expected replica-b to take over the expired lease but got: %v", err)
+	}
+
+	err = a.IsLeader(ctx)
+	if !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("Expected replica-a to lose the lease but got: %v", err)
+	}
+
+	// The released lease is acquired without waiting for the lease to expire
+	b.release()
+	if store.lease != nil {
+		t.Fatalf("Expected the lease to be deleted but got: %+v", store.lease)
+	}
+
+	err = a.IsLeader(ctx)
+	if err != nil {
+		t.Fatalf("Expected replica-a to acquire the released lease but got: %v", err)
+	}
+}
+
+func TestLeaseElectorConflict(t *testing.T) {
+
+	store := &memoryLeaseStore{}
+	e := newLeaseElector(store, LeaderElection{Identity: "replica-a"})
+
+	// Another replica created the lease between the read and the write
+	store.lease = &Lease{Holder: "replica-b", ExpiresAt: time.Now().Add(-time.Second)}
+	store.version = 1
+
+	conflicting := &conflictingLeaseStore{memoryLeaseStore: store}
+	e.store = conflicting
+
+	err := e.IsLeader(context.Background())
+	if !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("Expected a conflicting write to lose the election but got: %v", err)
+	}
+
+	store.readErr = errors.New("access denied")
+	err = e.IsLeader(context.Background())
+	if err == nil || errors.Is(err, ErrNotLeader) {
+		t.Fatalf("Expected the store error but got: %v", err)
+	}
+}
+
+// conflictingLeaseStore is a lease store updated by another replica before every write.
+type conflictingLeaseStore struct {
+	*memoryLeaseStore
+}
+
+func (c *conflictingLeaseStore) Replace(ctx context.Context, lease Lease, version string) (string, error) {
+
+	c.mu.Lock()
+	c.version++
+	c.mu.Unlock()
+
+	return c.memoryLeaseStore.Replace(ctx, lease, version)
+}
+
+func TestLeaseElectorRun(t *testing.T) {
+
+	store := &memoryLeaseStore{}
+	e := newLeaseElector(store, LeaderElection{Identity: "replica-a", LeaseDuration: 3})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.Run(ctx)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		store.mu.Lock()
+		held := store.lease != nil
+		store.mu.Unlock()
+		if held {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the elector to acquire the lease")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+
+	if store.lease != nil {
+		t.Errorf("Expected the lease to be released but got: %+v", store.lease)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// errFileLocked is returned by tryLockFile when the lock is held by another process.
+var errFileLocked = errors.New("file is locked by another process")
+
+// FileLock is an exclusive advisory lock shared between MyWhoop processes.
+// The lock is held on a dedicated lock file next to the protected file. A dedicated file is required because
+// the protected file is replaced through an atomic rename, which would otherwise release the lock.
+// The lock file is never removed. Removing it after the unlock would let a waiting process lock the unlinked file
+// while a new process locks a freshly created one, and both would hold the lock at the same time.
+type FileLock struct {
+	file *os.File
+}
+
+// lockFilePath returns the path of the lock file protecting the provided file.
+func lockFilePath(filePath string) string {
+	return filePath + ".lock"
+}
+
+// LockFile acquires an exclusive lock protecting the provided file. The function waits until the lock is available,
+// the context is cancelled, or the DEFAULT_FILE_LOCK_TIMEOUT is exceeded.
+func LockFile(ctx context.Context, filePath string) (*FileLock, error) {
+
+	lockPath := lockFilePath(filePath)
+
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open the lock file %s: %w", lockPath, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DEFAULT_FILE_LOCK_TIMEOUT)
+	defer cancel()
+
+	ticker := time.NewTicker(DEFAULT_FILE_LOCK_RETRY_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		err = tryLockFile(f)
+		if err == nil {
+			return &FileLock{file: f}, nil
+		}
+
+		if !errors.Is(err, errFileLocked) {
+			f.Close()
+			return nil, fmt.Errorf("unable to lock %s: %w", lockPath, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, fmt.Errorf("timed out waiting for the lock %s held by another MyWhoop process: %w", lockPath, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Unlock releases the lock.
+func (l *FileLock) Unlock() error {
+
+	if l == nil || l.file == nil {
+		return nil
+	}
+
+	err := unlockFile(l.file)
+	closeErr := l.file.Close()
+	l.file = nil
+
+	return errors.Join(err, closeErr)
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory and renames it to the provided path.
+// Readers either observe the previous content or the new content, but never a partially written file.
+func writeFileAtomic(filePath string, data []byte, perm os.FileMode) error {
+
+	dir := filepath.Dir(filePath)
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	cleanUp := func() {
+		tmp.Close()
+		os.Remove(tmpName)
+	}
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		cleanUp()
+		return err
+	}
+
+	err = tmp.Sync()
+	if err != nil {
+		cleanUp()
+		return err
+	}
+
+	err = tmp.Chmod(perm)
+	if err != nil {
+		cleanUp()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	err = os.Rename(tmpName, filePath)
+	if err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+
+	return nil
+}
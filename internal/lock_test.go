@@ -0,0 +1,145 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockFile(t *testing.T) {
+
+	filePath := filepath.Join(t.TempDir(), "token.json")
+
+	lock, err := LockFile(context.Background(), filePath)
+	if err != nil {
+		t.Fatalf("Failed to acquire the lock: %v", err)
+	}
+
+	_, err = os.Stat(lockFilePath(filePath))
+	if err != nil {
+		t.Errorf("Expected the lock file to be created but got: %v", err)
+	}
+
+	// A second lock must wait until the first lock is released
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	_, err = LockFile(ctx, filePath)
+	if err == nil {
+		t.Fatalf("Expected an error while the lock is held but got none")
+	}
+
+	err = lock.Unlock()
+	if err != nil {
+		t.Errorf("Failed to release the lock: %v", err)
+	}
+
+	lock, err = LockFile(context.Background(), filePath)
+	if err != nil {
+		t.Fatalf("Failed to acquire the released lock: %v", err)
+	}
+
+	err = lock.Unlock()
+	if err != nil {
+		t.Errorf("Failed to release the lock: %v", err)
+	}
+
+	// Unlocking twice is a no-op
+	err = lock.Unlock()
+	if err != nil {
+		t.Errorf("Expected no error when unlocking twice but got: %v", err)
+	}
+}
+
+func TestLockFileSerializesWriters(t *testing.T) {
+
+	filePath := filepath.Join(t.TempDir(), "counter")
+
+	err := os.WriteFile(filePath, []byte{0}, 0600)
+	if err != nil {
+		t.Fatalf("Failed to create the counter file: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			lock, err := LockFile(context.Background(), filePath)
+			if err != nil {
+				t.Errorf("Failed to acquire the lock: %v", err)
+				return
+			}
+			defer lock.Unlock()
+
+			content, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Errorf("Failed to read the counter file: %v", err)
+				return
+			}
+
+			time.Sleep(5 * time.Millisecond)
+
+			err = writeFileAtomic(filePath, []byte{content[0] + 1}, 0600)
+			if err != nil {
+				t.Errorf("Failed to write the counter file: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read the counter file: %v", err)
+	}
+
+	if content[0] != 10 {
+		t.Errorf("Expected the counter to be 10 but got %d", content[0])
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "token.json")
+
+	err := writeFileAtomic(filePath, []byte("first"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write the file: %v", err)
+	}
+
+	err = writeFileAtomic(filePath, []byte("second"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to overwrite the file: %v", err)
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read the file: %v", err)
+	}
+
+	if string(content) != "second" {
+		t.Errorf("Expected second but got %s", string(content))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read the directory: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary files to remain but got %d entries", len(entries))
+	}
+
+	err = writeFileAtomic(filepath.Join(dir, "missing", "token.json"), []byte("first"), 0600)
+	if err == nil {
+		t.Errorf("Expected an error when the directory does not exist but got none")
+	}
+}
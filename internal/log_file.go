@@ -0,0 +1,216 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeLayout is the layout of the rotation time in the name of the rotated log files.
+const backupTimeLayout string = "2006-01-02T15-04-05.000"
+
+// RotatingFile is a log file rotated once it exceeds its maximum size or once it has been written to for its maximum age.
+// The rotated files are named after the time of the rotation and the oldest rotated files are deleted.
+type RotatingFile struct {
+	// mu serializes the writes and the rotations.
+	mu sync.Mutex
+	// path is the path of the log file.
+	path string
+	// maxSize is the size in bytes the file is rotated at.
+	maxSize int64
+	// maxAge is the time the file is rotated after. The file is not rotated by age if zero.
+	maxAge time.Duration
+	// maxBackups is the number of rotated files kept. Every rotated file is kept if negative.
+	maxBackups int
+	// file is the open log file.
+	file *os.File
+	// size is the size of the open log file.
+	size int64
+	// opened is the time the log file was opened.
+	opened time.Time
+	// now returns the current time.
+	now func() time.Time
+}
+
+// NewRotatingFile opens the log file of the configuration. The directory of the log file is created if it does not exist.
+func NewRotatingFile(cfg LogFile) (*RotatingFile, error) {
+
+	f := &RotatingFile{
+		path:       cfg.Path,
+		maxSize:    int64(cfg.MaxSize) * 1024 * 1024,
+		maxAge:     time.Duration(cfg.MaxAge) * time.Hour,
+		maxBackups: cfg.MaxBackups,
+		now:        time.Now,
+	}
+
+	if f.path == "" {
+		f.path = DEFAULT_LOG_FILE
+	}
+
+	if f.maxSize <= 0 {
+		f.maxSize = int64(DEFAULT_LOG_MAX_SIZE) * 1024 * 1024
+	}
+
+	switch {
+	case cfg.MaxAge < 0:
+		f.maxAge = 0
+	case cfg.MaxAge == 0:
+		f.maxAge = DEFAULT_LOG_MAX_AGE
+	}
+
+	if f.maxBackups == 0 {
+		f.maxBackups = DEFAULT_LOG_MAX_BACKUPS
+	}
+
+	err := f.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Write writes the log record to the log file. The log file is rotated before the write if the record exceeds its maximum size or if the file exceeded its maximum age.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	expired := f.maxAge > 0 && f.now().Sub(f.opened) >= f.maxAge
+	if f.size > 0 && (f.size+int64(len(p)) > f.maxSize || expired) {
+		err := f.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the log file.
+func (f *RotatingFile) Close() error {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+
+	return err
+}
+
+// open opens the log file in append mode.
+func (f *RotatingFile) open() error {
+
+	dir := filepath.Dir(f.path)
+	if dir != "." {
+		err := os.MkdirAll(dir, 0750)
+		if err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.opened = f.now()
+
+	return nil
+}
+
+// rotate renames the log file after the time of the rotation, opens a new log file, and deletes the oldest rotated files.
+// The log file is closed before it is renamed, as an open file can't be renamed on Windows.
+func (f *RotatingFile) rotate() error {
+
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(f.path, f.backupName(f.now()))
+	if err != nil {
+		return err
+	}
+
+	err = f.open()
+	if err != nil {
+		return err
+	}
+
+	return f.removeBackups()
+}
+
+// backupName returns the name of the file rotated at the time. The time is inserted before the extension of the log file.
+func (f *RotatingFile) backupName(t time.Time) string {
+
+	ext := filepath.Ext(f.path)
+
+	return strings.TrimSuffix(f.path, ext) + "-" + t.Format(backupTimeLayout) + ext
+}
+
+// removeBackups deletes the oldest rotated files beyond the maximum number of rotated files.
+func (f *RotatingFile) removeBackups() error {
+
+	if f.maxBackups < 0 {
+		return nil
+	}
+
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext) + "-"
+	matches, err := filepath.Glob(base + "*" + ext)
+	if err != nil {
+		return err
+	}
+
+	// Only the files named after a rotation time are rotated files
+	var backups []string
+	for _, match := range matches {
+		_, err := time.Parse(backupTimeLayout, strings.TrimSuffix(strings.TrimPrefix(match, base), ext))
+		if err == nil {
+			backups = append(backups, match)
+		}
+	}
+
+	if len(backups) <= f.maxBackups {
+		return nil
+	}
+
+	// The rotation time in the name sorts the rotated files from the oldest to the most recent
+	slices.Sort(backups)
+	for _, backup := range backups[:len(backups)-f.maxBackups] {
+		err = os.Remove(backup)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
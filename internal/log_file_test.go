@@ -0,0 +1,108 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewRotatingFile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "logs", "mywhoop.log")
+
+	f, err := NewRotatingFile(LogFile{Path: path})
+	if err != nil {
+		t.Fatalf("Failed to open the log file: %v", err)
+	}
+	defer f.Close()
+
+	if f.maxSize != int64(DEFAULT_LOG_MAX_SIZE)*1024*1024 || f.maxAge != DEFAULT_LOG_MAX_AGE || f.maxBackups != DEFAULT_LOG_MAX_BACKUPS {
+		t.Errorf("Expected the default settings but got: %d, %s, %d", f.maxSize, f.maxAge, f.maxBackups)
+	}
+
+	disabled, err := NewRotatingFile(LogFile{Path: path, MaxAge: -1, MaxBackups: -1})
+	if err != nil {
+		t.Fatalf("Failed to open the log file: %v", err)
+	}
+	defer disabled.Close()
+
+	if disabled.maxAge != 0 || disabled.maxBackups != -1 {
+		t.Errorf("Expected the age-based rotation to be disabled but got: %s, %d", disabled.maxAge, disabled.maxBackups)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mywhoop.log")
+	now := time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)
+
+	// An unrelated file sharing the prefix of the rotated files is never deleted
+	unrelated := filepath.Join(dir, "mywhoop-state.log")
+	err := os.WriteFile(unrelated, []byte("keep"), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write the file: %v", err)
+	}
+
+	f, err := NewRotatingFile(LogFile{Path: path, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("Failed to open the log file: %v", err)
+	}
+	defer f.Close()
+
+	f.now = func() time.Time { return now }
+	f.opened = now
+	f.maxSize = 10
+
+	record := []byte("record\n")
+	for i := 0; i < 4; i++ {
+		_, err = f.Write(record)
+		if err != nil {
+			t.Fatalf("Failed to write the record: %v", err)
+		}
+		now = now.Add(time.Second)
+	}
+
+	// Every record exceeding the size rotates the file. The two most recent rotated files are kept.
+	backups, _ := filepath.Glob(filepath.Join(dir, "mywhoop-2024-*.log"))
+	if len(backups) != 2 || !strings.HasSuffix(backups[1], "mywhoop-2024-06-01T13-00-03.000.log") {
+		t.Errorf("Expected the two most recent rotated files but got: %v", backups)
+	}
+
+	content, _ := os.ReadFile(path)
+	if string(content) != string(record) {
+		t.Errorf("Expected a single record in the log file but got: %q", content)
+	}
+
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Errorf("Expected the unrelated file to be kept but got: %v", err)
+	}
+
+	// The file is rotated once it exceeds its maximum age
+	f.maxSize = 1024
+	now = now.Add(DEFAULT_LOG_MAX_AGE)
+	_, err = f.Write(record)
+	if err != nil {
+		t.Fatalf("Failed to write the record: %v", err)
+	}
+
+	content, _ = os.ReadFile(path)
+	if string(content) != string(record) {
+		t.Errorf("Expected the expired file to be rotated but got: %q", content)
+	}
+
+	err = f.Close()
+	if err != nil {
+		t.Errorf("Failed to close the log file: %v", err)
+	}
+
+	_, err = f.Write(record)
+	if err == nil {
+		t.Error("Expected an error writing to a closed log file")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+const (
+	// LOG_COMPONENT_KEY is the key of the attribute naming the component of a log record.
+	LOG_COMPONENT_KEY string = "component"
+	// LogComponentAPI is the component of the Whoop API requests.
+	LogComponentAPI string = "api"
+	// LogComponentExport is the component of the data exporters.
+	LogComponentExport string = "export"
+	// LogComponentNotify is the component of the notification methods.
+	LogComponentNotify string = "notify"
+	// LogComponentScheduler is the component of the server scheduler.
+	LogComponentScheduler string = "scheduler"
+)
+
+// logAttrsKey is the context key of the log attributes.
+type logAttrsKey struct{}
+
+// WithLogAttrs returns a context carrying the log attributes. The attributes are added to the records logged with the context,
+// so that the records of a job run can be correlated. An attribute replaces the attribute of the context with the same key.
+func WithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+
+	merged := slices.Clone(LogAttrs(ctx))
+	for _, attr := range attrs {
+		i := slices.IndexFunc(merged, func(a slog.Attr) bool { return a.Key == attr.Key })
+		if i >= 0 {
+			merged[i] = attr
+			continue
+		}
+		merged = append(merged, attr)
+	}
+
+	return context.WithValue(ctx, logAttrsKey{}, merged)
+}
+
+// LogAttrs returns the log attributes of the context.
+func LogAttrs(ctx context.Context) []slog.Attr {
+
+	if ctx == nil {
+		return nil
+	}
+
+	attrs, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+
+	return attrs
+}
+
+// ComponentLogger returns the default logger labelled with the component. The level of the component applies to the records of the logger.
+// The records are handled by the default logger at the time they are logged, so that the logger follows a reload of the log configuration.
+func ComponentLogger(component string) *slog.Logger {
+	return RunLogger(context.Background(), component)
+}
+
+// apiLogger returns the logger of the Whoop API requests.
+func apiLogger() *slog.Logger {
+	return ComponentLogger(LogComponentAPI)
+}
+
+// RunLogger returns a logger labelled with the component that adds the log attributes of the context to its records, such as the job and the run ID of a job run.
+// The logger is handed to the exporters and notification methods, which do not receive the context of the run.
+// The records are handled by the default logger at the time they are logged, so that the logger follows a reload of the log configuration.
+func RunLogger(ctx context.Context, component string) *slog.Logger {
+	return slog.New(&runHandler{attrs: LogAttrs(ctx)}).With(LOG_COMPONENT_KEY, component)
+}
+
+// runHandler forwards the records to the handler of the default logger with a context carrying the log attributes of a job run.
+type runHandler struct {
+	// attrs are the log attributes of the run.
+	attrs []slog.Attr
+	// with are the attributes and groups added to the logger, applied in order to the handler of the default logger.
+	with []func(slog.Handler) slog.Handler
+}
+
+// handler returns the handler of the default logger with the attributes and groups of the logger.
+func (h *runHandler) handler() slog.Handler {
+
+	handler := slog.Default().Handler()
+	for _, with := range h.with {
+		handler = with(handler)
+	}
+
+	return handler
+}
+
+// Enabled reports whether the handler of the default logger handles records of the level.
+func (h *runHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler().Enabled(ctx, level)
+}
+
+// Handle adds the log attributes of the run to the context and forwards the record to the handler of the default logger.
+func (h *runHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler().Handle(WithLogAttrs(ctx, h.attrs...), r)
+}
+
+// WithAttrs returns a handler adding the attributes to the records.
+func (h *runHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &runHandler{
+		attrs: h.attrs,
+		with:  append(slices.Clip(h.with), func(handler slog.Handler) slog.Handler { return handler.WithAttrs(attrs) }),
+	}
+}
+
+// WithGroup returns a handler adding the group to the records.
+func (h *runHandler) WithGroup(name string) slog.Handler {
+	return &runHandler{
+		attrs: h.attrs,
+		with:  append(slices.Clip(h.with), func(handler slog.Handler) slog.Handler { return handler.WithGroup(name) }),
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+)
+
+func TestWithLogAttrs(t *testing.T) {
+
+	if LogAttrs(context.Background()) != nil {
+		t.Error("Expected no attributes in an empty context")
+	}
+
+	run := WithLogAttrs(context.Background(), slog.String("job", "hourly"), slog.String("run_id", "1234"))
+	collection := WithLogAttrs(run, slog.String("collection", "sleep"), slog.String("job", "weekly"))
+
+	got := LogAttrs(collection)
+	if len(got) != 3 || got[0].Value.String() != "weekly" || got[2].Key != "collection" {
+		t.Errorf("Expected the merged attributes but got: %v", got)
+	}
+
+	// The parent context is not modified
+	if len(LogAttrs(run)) != 2 || LogAttrs(run)[0].Value.String() != "hourly" {
+		t.Errorf("Expected the parent attributes to be unchanged but got: %v", LogAttrs(run))
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// notificationConfig has the fields of NotificationConfig without the custom decoding and encoding methods.
+type notificationConfig NotificationConfig
+
+// errNestedNotificationChannels is returned when a notification channel is itself a list of channels.
+var errNestedNotificationChannels = errors.New("notification channels can't contain a list of channels")
+
+// UnmarshalYAML decodes a single notification channel or a list of notification channels.
+// The decoding function provided by the YAML decoder is used so that unknown fields are still rejected.
+func (n *NotificationConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+
+	var raw interface{}
+	err := unmarshal(&raw)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := raw.([]interface{}); !ok {
+		return unmarshal((*notificationConfig)(n))
+	}
+
+	var channels []NotificationConfig
+	err = unmarshal(&channels)
+	if err != nil {
+		return err
+	}
+
+	return n.setChannels(channels)
+}
+
+// UnmarshalJSON decodes a single notification channel or a list of notification channels. Unknown fields are rejected.
+func (n *NotificationConfig) UnmarshalJSON(data []byte) error {
+
+	dc := json.NewDecoder(bytes.NewReader(data))
+	dc.DisallowUnknownFields()
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '[' {
+		return dc.Decode((*notificationConfig)(n))
+	}
+
+	var channels []NotificationConfig
+	err := dc.Decode(&channels)
+	if err != nil {
+		return err
+	}
+
+	return n.setChannels(channels)
+}
+
+// MarshalYAML encodes the notification channels as a list, or the notification configuration as a single channel.
+func (n NotificationConfig) MarshalYAML() (interface{}, error) {
+
+	if len(n.Channels) > 0 {
+		return n.Channels, nil
+	}
+
+	return notificationConfig(n), nil
+}
+
+// MarshalJSON encodes the notification channels as a list, or the notification configuration as a single channel.
+func (n NotificationConfig) MarshalJSON() ([]byte, error) {
+
+	if len(n.Channels) > 0 {
+		return json.Marshal(n.Channels)
+	}
+
+	return json.Marshal(notificationConfig(n))
+}
+
+// acceptsList marks the configuration types that accept a single item or a list of items. The JSON Schema of these types allows both forms.
+func (n NotificationConfig) acceptsList() {}
+
+// setChannels replaces the notification configuration with the list of channels.
+func (n *NotificationConfig) setChannels(channels []NotificationConfig) error {
+
+	for i, channel := range channels {
+		if len(channel.Channels) > 0 {
+			return fmt.Errorf("notification channel %d: %w", i+1, errNestedNotificationChannels)
+		}
+	}
+
+	*n = NotificationConfig{Channels: channels}
+
+	return nil
+}
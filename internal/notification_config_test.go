@@ -0,0 +1,166 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestNotificationConfigYaml(t *testing.T) {
+
+	tests := []struct {
+		description      string
+		content          string
+		expectedError    string
+		expectedMethod   string
+		expectedChannels []string
+	}{
+		{
+			description:    "Single channel",
+			content:        "notification:\n  method: ntfy\n  ntfy:\n    serverEndpoint: https://ntfy.sh\n",
+			expectedMethod: "ntfy",
+		},
+		{
+			description:      "List of channels",
+			content:          "notification:\n  - method: ntfy\n    events: errors\n  - method: email\n    events: success\n",
+			expectedChannels: []string{"ntfy", "email"},
+		},
+		{
+			description:   "Unknown field in a channel",
+			content:       "notification:\n  - method: ntfy\n    evnts: errors\n",
+			expectedError: "field evnts not found",
+		},
+		{
+			description:   "Unknown field in a single channel",
+			content:       "notification:\n  methd: ntfy\n",
+			expectedError: "field methd not found",
+		},
+		{
+			description:   "Nested list of channels",
+			content:       "notification:\n  - - method: ntfy\n",
+			expectedError: errNestedNotificationChannels.Error(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var cfg ConfigurationData
+			dc := yaml.NewDecoder(strings.NewReader(test.content))
+			dc.KnownFields(true)
+			err := dc.Decode(&cfg)
+
+			if test.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+					t.Fatalf("Expected an error containing %q but got %v", test.expectedError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Expected no error but got %v", err)
+			}
+
+			if cfg.Notification.Method != test.expectedMethod {
+				t.Errorf("Expected method %q but got %q", test.expectedMethod, cfg.Notification.Method)
+			}
+
+			var methods []string
+			for _, channel := range cfg.Notification.Channels {
+				methods = append(methods, channel.Method)
+			}
+
+			if strings.Join(methods, ",") != strings.Join(test.expectedChannels, ",") {
+				t.Errorf("Expected channels %v but got %v", test.expectedChannels, methods)
+			}
+		})
+	}
+}
+
+func TestNotificationConfigJson(t *testing.T) {
+
+	tests := []struct {
+		description      string
+		content          string
+		expectedError    bool
+		expectedMethod   string
+		expectedChannels int
+	}{
+		{
+			description:    "Single channel",
+			content:        `{"notification": {"method": "slack", "events": "all"}}`,
+			expectedMethod: "slack",
+		},
+		{
+			description:      "List of channels",
+			content:          `{"notification": [{"method": "slack"}, {"method": "discord", "events": "success"}]}`,
+			expectedChannels: 2,
+		},
+		{
+			description:   "Unknown field in a channel",
+			content:       `{"notification": [{"method": "slack", "evnts": "all"}]}`,
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			var cfg ConfigurationData
+			err := json.Unmarshal([]byte(test.content), &cfg)
+			if (err != nil) != test.expectedError {
+				t.Fatalf("Expected error: %v but got %v", test.expectedError, err)
+			}
+
+			if cfg.Notification.Method != test.expectedMethod {
+				t.Errorf("Expected method %q but got %q", test.expectedMethod, cfg.Notification.Method)
+			}
+
+			if len(cfg.Notification.Channels) != test.expectedChannels {
+				t.Errorf("Expected %d channels but got %d", test.expectedChannels, len(cfg.Notification.Channels))
+			}
+		})
+	}
+}
+
+func TestNotificationConfigMarshal(t *testing.T) {
+
+	cfg := ConfigurationData{
+		Notification: NotificationConfig{
+			Channels: []NotificationConfig{
+				{Method: "ntfy", Events: "errors"},
+				{Method: "email", Events: "success"},
+			},
+		},
+	}
+
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	var decoded ConfigurationData
+	dc := yaml.NewDecoder(bytes.NewReader(out))
+	dc.KnownFields(true)
+	err = dc.Decode(&decoded)
+	if err != nil {
+		t.Fatalf("Expected the marshalled YAML to decode but got %v", err)
+	}
+
+	if len(decoded.Notification.Channels) != 2 || decoded.Notification.Channels[1].Events != "success" {
+		t.Errorf("Expected the channels to round trip but got %+v", decoded.Notification)
+	}
+
+	out, err = json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	if !strings.Contains(string(out), `"notification":[{"method":"ntfy"`) {
+		t.Errorf("Expected the notification channels to be encoded as a list but got %s", out)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"reflect"
+	"strings"
+)
+
+// REDACTED_VALUE replaces the value of secrets in logs and in the output of the config show command.
+const REDACTED_VALUE string = "**REDACTED**"
+
+// RedactSecrets returns a copy of the configuration with the values of the fields tagged with secret:"true" redacted.
+// Secret references, such as env:MY_VARIABLE or file:/run/secrets/whoop_client_secret, are not secrets and are preserved.
+func RedactSecrets(cfg ConfigurationData) ConfigurationData {
+
+	return redactValue(reflect.ValueOf(cfg)).Interface().(ConfigurationData)
+}
+
+// redactValue returns a deep copy of the value with the secret fields redacted.
+func redactValue(v reflect.Value) reflect.Value {
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(redactValue(v.Elem()))
+		return out
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redactValue(v.Index(i)))
+		}
+		return out
+
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			if field.Tag.Get("secret") == "true" && field.Type.Kind() == reflect.String {
+				out.Field(i).SetString(redactString(v.Field(i).String()))
+				continue
+			}
+
+			if field.Tag.Get("secret") == "true" && field.Type.Kind() == reflect.Map && field.Type.Elem().Kind() == reflect.String {
+				out.Field(i).Set(redactMap(v.Field(i)))
+				continue
+			}
+
+			out.Field(i).Set(redactValue(v.Field(i)))
+		}
+		return out
+
+	default:
+		return v
+	}
+}
+
+// redactMap returns a copy of the map of strings with every value redacted.
+func redactMap(v reflect.Value) reflect.Value {
+
+	if v.IsNil() {
+		return v
+	}
+
+	out := reflect.MakeMapWithSize(v.Type(), v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		out.SetMapIndex(iter.Key(), reflect.ValueOf(redactString(iter.Value().String())).Convert(v.Type().Elem()))
+	}
+
+	return out
+}
+
+// redactString redacts a secret value. Empty values and secret references are returned as is.
+func redactString(value string) string {
+
+	if value == "" || strings.HasPrefix(value, "env:") || strings.HasPrefix(value, "file:") {
+		return value
+	}
+
+	return REDACTED_VALUE
+}
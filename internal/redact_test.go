@@ -0,0 +1,74 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"testing"
+)
+
+func TestRedactSecrets(t *testing.T) {
+
+	cfg := ConfigurationData{
+		Credentials: Credentials{
+			ClientID:     "AAAAAAAAAAAAAAAAAAA",
+			ClientSecret: "BBBBBBBBBBBBBBBBBBBBB",
+		},
+		Accounts: []Account{
+			{
+				Name: "alice",
+				Credentials: Credentials{
+					ClientSecret: "env:ALICE_CLIENT_SECRET",
+				},
+			},
+			{
+				Name: "bob",
+				Credentials: Credentials{
+					ClientSecret: "CCCCCCCCCCCCCCCCCCCC",
+				},
+				Notification: &NotificationConfig{},
+			},
+		},
+	}
+	cfg.Notification.Ntfy.Password = "password"
+	cfg.Accounts[1].Notification.Ntfy.AccessToken = "token"
+	cfg.Notification.Webhook.Headers = map[string]string{
+		"Authorization": "Bearer token",
+		"X-Api-Key":     "env:HOME_ASSISTANT_TOKEN",
+	}
+
+	got := RedactSecrets(cfg)
+
+	if got.Credentials.ClientID != "AAAAAAAAAAAAAAAAAAA" {
+		t.Errorf("Expected the client ID to be preserved but got %s", got.Credentials.ClientID)
+	}
+
+	if got.Credentials.ClientSecret != REDACTED_VALUE {
+		t.Errorf("Expected the client secret to be redacted but got %s", got.Credentials.ClientSecret)
+	}
+
+	if got.Notification.Ntfy.Password != REDACTED_VALUE {
+		t.Errorf("Expected the ntfy password to be redacted but got %s", got.Notification.Ntfy.Password)
+	}
+
+	if got.Accounts[0].Credentials.ClientSecret != "env:ALICE_CLIENT_SECRET" {
+		t.Errorf("Expected the secret reference to be preserved but got %s", got.Accounts[0].Credentials.ClientSecret)
+	}
+
+	if got.Accounts[1].Notification.Ntfy.AccessToken != REDACTED_VALUE {
+		t.Errorf("Expected the account ntfy access token to be redacted but got %s", got.Accounts[1].Notification.Ntfy.AccessToken)
+	}
+
+	if got.Notification.Webhook.Headers["Authorization"] != REDACTED_VALUE {
+		t.Errorf("Expected the webhook header to be redacted but got %s", got.Notification.Webhook.Headers["Authorization"])
+	}
+
+	if got.Notification.Webhook.Headers["X-Api-Key"] != "env:HOME_ASSISTANT_TOKEN" {
+		t.Errorf("Expected the webhook header reference to be preserved but got %s", got.Notification.Webhook.Headers["X-Api-Key"])
+	}
+
+	// The original configuration must not be modified
+	if cfg.Credentials.ClientSecret != "BBBBBBBBBBBBBBBBBBBBB" || cfg.Accounts[1].Notification.Ntfy.AccessToken != "token" || cfg.Notification.Webhook.Headers["Authorization"] != "Bearer token" {
+		t.Errorf("Expected the original configuration to be preserved")
+	}
+}
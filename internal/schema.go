@@ -0,0 +1,217 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+const (
+	// JSON_SCHEMA_DRAFT is the JSON Schema draft the MyWhoop configuration schema complies with.
+	JSON_SCHEMA_DRAFT string = "http://json-schema.org/draft-07/schema#"
+	// JSON_SCHEMA_ID is the published location of the MyWhoop configuration schema.
+	JSON_SCHEMA_ID string = "https://raw.githubusercontent.com/karl-cardenas-coding/mywhoop/main/docs/schema/mywhoop.schema.json"
+)
+
+// JSONSchema is the subset of the JSON Schema specification used to describe the MyWhoop configuration file.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+}
+
+// GenerateJSONSchema generates the JSON Schema of the MyWhoop configuration file from the ConfigurationData struct.
+// Field descriptions are looked up in the provided map using the "<TypeName>.<FieldName>" key. Use LoadFieldDescriptions to generate the map from the Go source files.
+func GenerateJSONSchema(descriptions map[string]string) ([]byte, error) {
+
+	schema := schemaForType(reflect.TypeOf(ConfigurationData{}), descriptions)
+	schema.Schema = JSON_SCHEMA_DRAFT
+	schema.ID = JSON_SCHEMA_ID
+	schema.Title = "MyWhoop configuration"
+	schema.Description = "The configuration file of MyWhoop. Visit https://github.com/karl-cardenas-coding/mywhoop/blob/main/docs/configuration_reference.md for more information."
+
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(out, '\n'), nil
+}
+
+// listConfig is implemented by the configuration types that accept a single item or a list of items.
+type listConfig interface {
+	acceptsList()
+}
+
+// listConfigType is the reflection type of the listConfig interface.
+var listConfigType = reflect.TypeOf((*listConfig)(nil)).Elem()
+
+// schemaForType returns the JSON Schema of a Go type.
+func schemaForType(t reflect.Type, descriptions map[string]string) *JSONSchema {
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array", Items: schemaForType(t.Elem(), descriptions)}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: schemaForType(t.Elem(), descriptions)}
+	case reflect.Struct:
+		if t.Implements(listConfigType) {
+			item := schemaForStruct(t, descriptions)
+			return &JSONSchema{AnyOf: []*JSONSchema{item, {Type: "array", Items: item}}}
+		}
+		return schemaForStruct(t, descriptions)
+	default:
+		return &JSONSchema{}
+	}
+}
+
+// schemaForStruct returns the JSON Schema of a struct. Fields without a configuration file name are skipped.
+func schemaForStruct(t reflect.Type, descriptions map[string]string) *JSONSchema {
+
+	schema := &JSONSchema{
+		Type:                 "object",
+		Properties:           make(map[string]*JSONSchema),
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := configFieldName(field)
+		if name == "" {
+			continue
+		}
+
+		property := schemaForType(field.Type, descriptions)
+		property.Description = descriptions[t.Name()+"."+field.Name]
+
+		rules := strings.Split(field.Tag.Get("validate"), ",")
+		for _, rule := range rules {
+			switch {
+			case rule == "required":
+				schema.Required = append(schema.Required, name)
+			case strings.HasPrefix(rule, "oneof="):
+				for _, value := range parseOneOf(strings.TrimPrefix(rule, "oneof=")) {
+					property.Enum = append(property.Enum, value)
+				}
+			}
+		}
+
+		schema.Properties[name] = property
+	}
+
+	return schema
+}
+
+// configFieldName returns the name of the struct field in the configuration file. An empty string is returned for fields that can't be configured.
+func configFieldName(field reflect.StructField) string {
+
+	if !field.IsExported() {
+		return ""
+	}
+
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+
+	if name == "" {
+		name = strings.Split(field.Tag.Get("yaml"), ",")[0]
+	}
+
+	if name == "-" || name == "" {
+		return ""
+	}
+
+	return name
+}
+
+// parseOneOf parses the values of a validator oneof rule. Values are separated by spaces and a pair of single quotes represents an empty string.
+func parseOneOf(rule string) []string {
+
+	var values []string
+	for _, value := range strings.Fields(rule) {
+		values = append(values, strings.Trim(value, "'"))
+	}
+
+	return values
+}
+
+// LoadFieldDescriptions parses the Go source files in the provided directories and returns the doc comments of the struct fields.
+// The map keys use the "<TypeName>.<FieldName>" format expected by GenerateJSONSchema.
+func LoadFieldDescriptions(dirs ...string) (map[string]string, error) {
+
+	descriptions := make(map[string]string)
+	fset := token.NewFileSet()
+
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			if strings.HasSuffix(file, "_test.go") {
+				continue
+			}
+
+			f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+
+			ast.Inspect(f, func(n ast.Node) bool {
+				spec, ok := n.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+
+				st, ok := spec.Type.(*ast.StructType)
+				if !ok {
+					return true
+				}
+
+				for _, field := range st.Fields.List {
+					if field.Doc == nil {
+						continue
+					}
+					text := strings.Join(strings.Fields(field.Doc.Text()), " ")
+					for _, name := range field.Names {
+						descriptions[spec.Name.Name+"."+name.Name] = text
+					}
+				}
+
+				return false
+			})
+		}
+	}
+
+	return descriptions, nil
+}
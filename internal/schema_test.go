@@ -0,0 +1,76 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestGenerateJSONSchema(t *testing.T) {
+
+	got, err := GenerateJSONSchema(map[string]string{})
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	var schema JSONSchema
+	err = json.Unmarshal(got, &schema)
+	if err != nil {
+		t.Fatalf("Expected a valid JSON document but got %v", err)
+	}
+
+	if schema.Schema != JSON_SCHEMA_DRAFT {
+		t.Errorf("Expected %s but got %s", JSON_SCHEMA_DRAFT, schema.Schema)
+	}
+
+	export, ok := schema.Properties["export"]
+	if !ok {
+		t.Fatalf("Expected the export property in the schema")
+	}
+
+	method := export.Properties["method"]
+	if method == nil || len(method.Enum) == 0 {
+		t.Fatalf("Expected the export method to be an enum")
+	}
+
+	if export.AdditionalProperties != false {
+		t.Errorf("Expected additional properties to be rejected but got %v", export.AdditionalProperties)
+	}
+
+	s3, ok := export.Properties["awsS3"]
+	if !ok {
+		t.Fatalf("Expected the awsS3 property in the schema")
+	}
+
+	if _, ok := s3.Properties["S3Client"]; ok {
+		t.Errorf("Expected the S3 client to be excluded from the schema")
+	}
+
+}
+
+func TestPublishedJSONSchema(t *testing.T) {
+
+	descriptions, err := LoadFieldDescriptions(".", "../export", "../notifications")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	want, err := GenerateJSONSchema(descriptions)
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	got, err := os.ReadFile("../docs/schema/mywhoop.schema.json")
+	if err != nil {
+		t.Fatalf("Expected no error but got %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("The published JSON schema is out of date. Run make schema to regenerate it.")
+	}
+
+}
@@ -0,0 +1,99 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// JobState is the state of a server job persisted between the runs of the server.
+type JobState struct {
+	// LastSuccess is the scheduled time of the last successful run of the job.
+	LastSuccess time.Time `json:"lastSuccess"`
+	// WindowEnd is the end of the time window of the data collected by the last successful run.
+	WindowEnd time.Time `json:"windowEnd"`
+}
+
+// StateFile returns the path of the state file. The default state file is used if no file is configured.
+func StateFile(cfg CatchUp) string {
+
+	if cfg.StateFile == "" {
+		return DEFAULT_SERVER_STATE_FILE
+	}
+
+	return cfg.StateFile
+}
+
+// readStates reads the states of the state file keyed by job name. A missing state file contains no states.
+func readStates(filePath string) (map[string]JobState, error) {
+
+	states := map[string]JobState{}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return states, nil
+		}
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, &states)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the state file %s: %w", filePath, err)
+	}
+
+	return states, nil
+}
+
+// ReadJobState returns the state of the job stored in the state file. False is returned if the job has no state.
+func ReadJobState(filePath, job string) (JobState, bool, error) {
+
+	states, err := readStates(filePath)
+	if err != nil {
+		return JobState{}, false, err
+	}
+
+	state, ok := states[job]
+
+	return state, ok, nil
+}
+
+// WriteJobState stores the state of the job in the state file. The states of the other jobs are preserved.
+// The state file is locked while it is updated because several accounts and MyWhoop processes can share the file.
+func WriteJobState(ctx context.Context, filePath, job string, state JobState) error {
+
+	dir := filepath.Dir(filePath)
+	if dir != "." {
+		err := os.MkdirAll(dir, 0750)
+		if err != nil {
+			return err
+		}
+	}
+
+	lock, err := LockFile(ctx, filePath)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	states, err := readStates(filePath)
+	if err != nil {
+		return err
+	}
+
+	states[job] = state
+
+	content, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(filePath, content, 0600)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStateFile(t *testing.T) {
+
+	if got := StateFile(CatchUp{}); got != DEFAULT_SERVER_STATE_FILE {
+		t.Errorf("expected the default state file, got %s", got)
+	}
+
+	if got := StateFile(CatchUp{StateFile: "/var/lib/mywhoop/state.json"}); got != "/var/lib/mywhoop/state.json" {
+		t.Errorf("expected the configured state file, got %s", got)
+	}
+}
+
+func TestJobState(t *testing.T) {
+
+	filePath := filepath.Join(t.TempDir(), "state", "mywhoop_state.json")
+
+	_, ok, err := ReadJobState(filePath, "mywhoop_data_collection_job")
+	if err != nil || ok {
+		t.Fatalf("expected no state in a missing state file, got %v, %v", ok, err)
+	}
+
+	alice := JobState{
+		LastSuccess: time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC),
+		WindowEnd:   time.Date(2024, 6, 1, 13, 0, 5, 0, time.UTC),
+	}
+	bob := JobState{
+		LastSuccess: time.Date(2024, 6, 2, 13, 0, 0, 0, time.UTC),
+		WindowEnd:   time.Date(2024, 6, 2, 13, 0, 5, 0, time.UTC),
+	}
+
+	err = WriteJobState(context.Background(), filePath, "mywhoop_data_collection_job_alice", alice)
+	if err != nil {
+		t.Fatalf("unable to write the state: %v", err)
+	}
+
+	err = WriteJobState(context.Background(), filePath, "mywhoop_data_collection_job_bob", bob)
+	if err != nil {
+		t.Fatalf("unable to write the state: %v", err)
+	}
+
+	// The states of the other jobs are preserved
+	state, ok, err := ReadJobState(filePath, "mywhoop_data_collection_job_alice")
+	if err != nil || !ok || !state.LastSuccess.Equal(alice.LastSuccess) || !state.WindowEnd.Equal(alice.WindowEnd) {
+		t.Errorf("unexpected state %+v, %v, %v", state, ok, err)
+	}
+
+	state, ok, err = ReadJobState(filePath, "mywhoop_data_collection_job_bob")
+	if err != nil || !ok || !state.LastSuccess.Equal(bob.LastSuccess) {
+		t.Errorf("unexpected state %+v, %v, %v", state, ok, err)
+	}
+}
+
+func TestReadJobStateInvalid(t *testing.T) {
+
+	filePath := filepath.Join(t.TempDir(), "mywhoop_state.json")
+
+	err := os.WriteFile(filePath, []byte("not json"), 0600)
+	if err != nil {
+		t.Fatalf("unable to write the state file: %v", err)
+	}
+
+	_, _, err = ReadJobState(filePath, "mywhoop_data_collection_job")
+	if err == nil {
+		t.Error("expected an error for an invalid state file")
+	}
+}
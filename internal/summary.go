@@ -0,0 +1,413 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// scoreStateScored is the score state of Whoop records that have been scored.
+const scoreStateScored = "SCORED"
+
+// defaultSummaryTemplate is the template used to render the daily summary if no template file is configured.
+const defaultSummaryTemplate = `{{- with .Sleep }}
+😴 Sleep: {{ printf "%.0f" .Performance }}% performance, {{ duration .Asleep }} asleep, {{ duration .InBed }} in bed of {{ duration .Needed }} needed
+{{- end }}
+{{- with .Recovery }}
+💚 Recovery: {{ printf "%.0f" .Score }}%, HRV {{ printf "%.1f" .HRV }} ms, RHR {{ printf "%.0f" .RestingHeartRate }} bpm
+{{- end }}
+{{- with .Strain }}
+🔥 Strain: {{ printf "%.1f" .Strain }}, {{ printf "%.0f" .Calories }} kcal
+{{- end }}
+{{- range .Workouts }}
+🏋️ {{ .Sport }}: {{ .Count }}x, {{ duration .Duration }}, max strain {{ printf "%.1f" .Strain }}
+{{- end }}`
+
+// sports maps the Whoop sport IDs to the sport names.
+var sports = map[int]string{
+	-1:  "Activity",
+	0:   "Running",
+	1:   "Cycling",
+	16:  "Baseball",
+	17:  "Basketball",
+	18:  "Rowing",
+	19:  "Fencing",
+	20:  "Field Hockey",
+	21:  "Football",
+	22:  "Golf",
+	24:  "Ice Hockey",
+	25:  "Lacrosse",
+	27:  "Rugby",
+	28:  "Sailing",
+	29:  "Skiing",
+	30:  "Soccer",
+	31:  "Softball",
+	32:  "Squash",
+	33:  "Swimming",
+	34:  "Tennis",
+	35:  "Track & Field",
+	36:  "Volleyball",
+	37:  "Water Polo",
+	38:  "Wrestling",
+	39:  "Boxing",
+	42:  "Dance",
+	43:  "Pilates",
+	44:  "Yoga",
+	45:  "Weightlifting",
+	47:  "Cross Country Skiing",
+	48:  "Functional Fitness",
+	49:  "Duathlon",
+	51:  "Gymnastics",
+	52:  "Hiking/Rucking",
+	53:  "Horseback Riding",
+	55:  "Kayaking",
+	56:  "Martial Arts",
+	57:  "Mountain Biking",
+	59:  "Powerlifting",
+	60:  "Rock Climbing",
+	61:  "Paddleboarding",
+	62:  "Triathlon",
+	63:  "Walking",
+	64:  "Surfing",
+	65:  "Elliptical",
+	66:  "Stairmaster",
+	70:  "Meditation",
+	71:  "Other",
+	73:  "Diving",
+	82:  "Ultimate",
+	83:  "Climber",
+	84:  "Jumping Rope",
+	85:  "Australian Football",
+	86:  "Skateboarding",
+	87:  "Coaching",
+	88:  "Ice Bath",
+	89:  "Commuting",
+	90:  "Gaming",
+	91:  "Snowboarding",
+	92:  "Motocross",
+	93:  "Caddying",
+	94:  "Obstacle Course Racing",
+	95:  "Motor Racing",
+	96:  "HIIT",
+	97:  "Spin",
+	98:  "Jiu Jitsu",
+	99:  "Manual Labor",
+	100: "Cricket",
+	101: "Pickleball",
+	102: "Inline Skating",
+	103: "Box Fitness",
+	104: "Spikeball",
+	105: "Wheelchair Pushing",
+	106: "Paddle Tennis",
+	107: "Barre",
+	108: "Stage Performance",
+	109: "High Stress Work",
+	110: "Parkour",
+	111: "Gaelic Football",
+	112: "Hurling/Camogie",
+	113: "Circus Arts",
+	121: "Massage Therapy",
+	123: "Strength Trainer",
+	125: "Watching Sports",
+	126: "Assault Bike",
+	127: "Kickboxing",
+	128: "Stretching",
+	230: "Table Tennis",
+	231: "Badminton",
+	233: "Netball",
+	234: "Sauna",
+	235: "Disc Golf",
+	236: "Yard Work",
+	237: "Air Compression",
+	238: "Percussive Massage",
+	239: "Paintball",
+	240: "Ice Skating",
+	241: "Handball",
+}
+
+// Summary is the daily summary of the Whoop data. The summary is rendered with a Go template and sent with the success notifications of the data collection job.
+type Summary struct {
+	// Sleep is the summary of the last main sleep. Nil if no scored sleep is available.
+	Sleep *SleepSummary
+	// Recovery is the summary of the last recovery. Nil if no scored recovery is available.
+	Recovery *RecoverySummary
+	// Strain is the summary of the last completed physiological cycle. Nil if no scored cycle is available.
+	Strain *StrainSummary
+	// Workouts are the summaries of the scored workouts grouped by sport.
+	Workouts []WorkoutSummary
+}
+
+// SleepSummary is the summary of a sleep.
+type SleepSummary struct {
+	// Start is the start time of the sleep.
+	Start time.Time
+	// End is the end time of the sleep.
+	End time.Time
+	// Performance is the sleep performance percentage.
+	Performance float64
+	// Efficiency is the sleep efficiency percentage.
+	Efficiency float64
+	// Consistency is the sleep consistency percentage.
+	Consistency float64
+	// InBed is the time spent in bed.
+	InBed time.Duration
+	// Asleep is the time spent in light, slow wave, and REM sleep.
+	Asleep time.Duration
+	// Needed is the sleep needed, including the baseline, the sleep debt, the recent strain, and the recent naps.
+	Needed time.Duration
+	// RespiratoryRate is the respiratory rate during the sleep.
+	RespiratoryRate float64
+}
+
+// RecoverySummary is the summary of a recovery.
+type RecoverySummary struct {
+	// Score is the recovery score percentage.
+	Score float64
+	// HRV is the heart rate variability in milliseconds.
+	HRV float64
+	// RestingHeartRate is the resting heart rate in beats per minute.
+	RestingHeartRate float64
+	// SpO2 is the blood oxygen percentage.
+	SpO2 float64
+	// SkinTemp is the skin temperature in degrees Celsius.
+	SkinTemp float64
+	// Calibrating is true while Whoop is calibrating the recovery of the user.
+	Calibrating bool
+}
+
+// StrainSummary is the summary of a physiological cycle.
+type StrainSummary struct {
+	// Start is the start time of the cycle.
+	Start time.Time
+	// End is the end time of the cycle.
+	End time.Time
+	// Strain is the day strain.
+	Strain float64
+	// Kilojoule is the energy burned during the cycle in kilojoules.
+	Kilojoule float64
+	// Calories is the energy burned during the cycle in kilocalories.
+	Calories float64
+	// AverageHeartRate is the average heart rate during the cycle.
+	AverageHeartRate int
+	// MaxHeartRate is the maximum heart rate during the cycle.
+	MaxHeartRate int
+}
+
+// WorkoutSummary is the summary of the workouts of a sport.
+type WorkoutSummary struct {
+	// Sport is the name of the sport.
+	Sport string
+	// Count is the number of workouts.
+	Count int
+	// Duration is the total duration of the workouts.
+	Duration time.Duration
+	// Strain is the highest strain of the workouts.
+	Strain float64
+	// Kilojoule is the total energy burned during the workouts in kilojoules.
+	Kilojoule float64
+	// DistanceMeter is the total distance of the workouts in meters.
+	DistanceMeter float64
+}
+
+// NewSummary returns the summary of the Whoop data of the user.
+// Naps and records that have not been scored are ignored.
+func NewSummary(user User) Summary {
+
+	var summary Summary
+
+	sleep := latestSleep(user)
+	if sleep != nil {
+		stages := sleep.Score.StageSummary
+		needed := sleep.Score.SleepNeeded
+		summary.Sleep = &SleepSummary{
+			Start:           sleep.Start,
+			End:             sleep.End,
+			Performance:     sleep.Score.SleepPerformancePercentage,
+			Efficiency:      sleep.Score.SleepEfficiencyPercentage,
+			Consistency:     sleep.Score.SleepConsistencyPercentage,
+			InBed:           milliseconds(stages.TotalInBedTimeMilli),
+			Asleep:          milliseconds(stages.TotalLightSleepTimeMilli + stages.TotalSlowWaveSleepTimeMilli + stages.TotalRemSleepTimeMilli),
+			Needed:          milliseconds(needed.BaselineMilli + needed.NeedFromSleepDebtMilli + needed.NeedFromRecentStrainMilli + needed.NeedFromRecentNapMilli),
+			RespiratoryRate: sleep.Score.RespiratoryRate,
+		}
+	}
+
+	recovery := latestRecovery(user, sleep)
+	if recovery != nil {
+		summary.Recovery = &RecoverySummary{
+			Score:            recovery.Score.RecoveryScore,
+			HRV:              recovery.Score.HrvRmssdMilli,
+			RestingHeartRate: recovery.Score.RestingHeartRate,
+			SpO2:             recovery.Score.Spo2Percentage,
+			SkinTemp:         recovery.Score.SkinTempCelsius,
+			Calibrating:      recovery.Score.UserCalibrating,
+		}
+	}
+
+	var cycle *CycleRecords
+	for i, record := range user.CycleCollection.Records {
+		if record.ScoreState != scoreStateScored {
+			continue
+		}
+		// A completed cycle takes precedence over the ongoing cycle, which has no end time.
+		switch {
+		case cycle == nil:
+			cycle = &user.CycleCollection.Records[i]
+		case cycle.End.IsZero() && !record.End.IsZero():
+			cycle = &user.CycleCollection.Records[i]
+		case !record.End.IsZero() && record.End.After(cycle.End):
+			cycle = &user.CycleCollection.Records[i]
+		}
+	}
+
+	if cycle != nil {
+		summary.Strain = &StrainSummary{
+			Start:            cycle.Start,
+			End:              cycle.End,
+			Strain:           cycle.Score.Strain,
+			Kilojoule:        cycle.Score.Kilojoule,
+			Calories:         cycle.Score.Kilojoule / 4.184,
+			AverageHeartRate: cycle.Score.AverageHeartRate,
+			MaxHeartRate:     cycle.Score.MaxHeartRate,
+		}
+	}
+
+	workouts := make(map[string]*WorkoutSummary)
+	for _, record := range user.WorkoutCollection.Records {
+		if record.ScoreState != scoreStateScored {
+			continue
+		}
+
+		sport := SportName(record.SportID)
+		workout, ok := workouts[sport]
+		if !ok {
+			workout = &WorkoutSummary{Sport: sport}
+			workouts[sport] = workout
+		}
+
+		workout.Count++
+		workout.Duration += record.End.Sub(record.Start)
+		workout.Kilojoule += record.Score.Kilojoule
+		workout.DistanceMeter += record.Score.DistanceMeter
+		if record.Score.Strain > workout.Strain {
+			workout.Strain = record.Score.Strain
+		}
+	}
+
+	for _, workout := range workouts {
+		summary.Workouts = append(summary.Workouts, *workout)
+	}
+
+	sort.Slice(summary.Workouts, func(i, j int) bool {
+		return summary.Workouts[i].Sport < summary.Workouts[j].Sport
+	})
+
+	return summary
+}
+
+// latestSleep returns the last scored main sleep of the user. Naps are ignored. Nil is returned if no scored sleep is available.
+func latestSleep(user User) *SleepCollectionRecords {
+
+	var sleep *SleepCollectionRecords
+	for i, record := range user.SleepCollection.SleepCollectionRecords {
+		if record.Nap || record.ScoreState != scoreStateScored {
+			continue
+		}
+		if sleep == nil || record.End.After(sleep.End) {
+			sleep = &user.SleepCollection.SleepCollectionRecords[i]
+		}
+	}
+
+	return sleep
+}
+
+// latestRecovery returns the recovery of the sleep, or the most recent scored recovery if the sleep has no recovery. Nil is returned if no scored recovery is available.
+func latestRecovery(user User, sleep *SleepCollectionRecords) *RecoveryRecords {
+
+	var recovery *RecoveryRecords
+	for i, record := range user.RecoveryCollection.RecoveryRecords {
+		if record.ScoreState != scoreStateScored {
+			continue
+		}
+		if sleep != nil && record.SleepID == sleep.ID {
+			return &user.RecoveryCollection.RecoveryRecords[i]
+		}
+		if recovery == nil || record.CreatedAt.After(recovery.CreatedAt) {
+			recovery = &user.RecoveryCollection.RecoveryRecords[i]
+		}
+	}
+
+	return recovery
+}
+
+// SportName returns the name of the Whoop sport ID.
+func SportName(id int) string {
+
+	if name, ok := sports[id]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Sport %d", id)
+}
+
+// ParseSummaryTemplate parses the Go template file used to render the daily summary. The built-in template is used if no file is provided.
+// The duration function is available to format durations, such as 7h32m.
+func ParseSummaryTemplate(filePath string) (*template.Template, error) {
+
+	text := defaultSummaryTemplate
+
+	if filePath != "" {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the summary template: %w", err)
+		}
+		text = string(content)
+	}
+
+	tmpl, err := template.New("summary").Funcs(template.FuncMap{
+		"duration": formatDuration,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the summary template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// Render renders the summary with the template.
+func (s Summary) Render(tmpl *template.Template) (string, error) {
+
+	var out strings.Builder
+
+	err := tmpl.Execute(&out, s)
+	if err != nil {
+		return "", fmt.Errorf("unable to render the summary template: %w", err)
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// milliseconds converts milliseconds to a duration.
+func milliseconds(value int) time.Duration {
+	return time.Duration(value) * time.Millisecond
+}
+
+// formatDuration formats the duration in hours and minutes, such as 7h32m.
+func formatDuration(d time.Duration) string {
+
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) - hours*60
+
+	if hours == 0 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+
+	return fmt.Sprintf("%dh%02dm", hours, minutes)
+}
@@ -0,0 +1,294 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewSummary(t *testing.T) {
+
+	now := time.Date(2024, 7, 31, 8, 0, 0, 0, time.UTC)
+
+	user := User{
+		SleepCollection: SleepCollection{
+			SleepCollectionRecords: []SleepCollectionRecords{
+				{
+					ID:         1,
+					Start:      now.Add(-9 * time.Hour),
+					End:        now.Add(-1 * time.Hour),
+					ScoreState: "SCORED",
+					Score: Score{
+						SleepPerformancePercentage: 92,
+						StageSummary: StageSummary{
+							TotalInBedTimeMilli:         8 * 3600000,
+							TotalLightSleepTimeMilli:    4 * 3600000,
+							TotalSlowWaveSleepTimeMilli: 3600000,
+							TotalRemSleepTimeMilli:      2 * 3600000,
+						},
+						SleepNeeded: SleepNeeded{
+							BaselineMilli:          8 * 3600000,
+							NeedFromSleepDebtMilli: 1800000,
+							NeedFromRecentNapMilli: -600000,
+						},
+					},
+				},
+				{
+					ID:         2,
+					Start:      now.Add(-30 * time.Minute),
+					End:        now.Add(-10 * time.Minute),
+					Nap:        true,
+					ScoreState: "SCORED",
+				},
+				{
+					ID:         3,
+					Start:      now.Add(-33 * time.Hour),
+					End:        now.Add(-25 * time.Hour),
+					ScoreState: "SCORED",
+				},
+			},
+		},
+		RecoveryCollection: RecoveryCollection{
+			RecoveryRecords: []RecoveryRecords{
+				{
+					SleepID:    3,
+					CreatedAt:  now.Add(time.Hour),
+					ScoreState: "SCORED",
+					Score:      RecoveryScore{RecoveryScore: 20},
+				},
+				{
+					SleepID:    1,
+					CreatedAt:  now,
+					ScoreState: "SCORED",
+					Score:      RecoveryScore{RecoveryScore: 67, HrvRmssdMilli: 54.3, RestingHeartRate: 52},
+				},
+			},
+		},
+		CycleCollection: CycleCollection{
+			Records: []CycleRecords{
+				{
+					Start:      now.Add(-time.Hour),
+					ScoreState: "SCORED",
+					Score:      CycleScore{Strain: 2.1},
+				},
+				{
+					Start:      now.Add(-25 * time.Hour),
+					End:        now.Add(-time.Hour),
+					ScoreState: "SCORED",
+					Score:      CycleScore{Strain: 12.4, Kilojoule: 10104.4},
+				},
+			},
+		},
+		WorkoutCollection: WorkoutCollection{
+			Records: []WorkoutRecords{
+				{
+					SportID:    0,
+					Start:      now.Add(-20 * time.Hour),
+					End:        now.Add(-20*time.Hour + 30*time.Minute),
+					ScoreState: "SCORED",
+					Score:      WorkoutScore{Strain: 9.5},
+				},
+				{
+					SportID:    0,
+					Start:      now.Add(-10 * time.Hour),
+					End:        now.Add(-10*time.Hour + 15*time.Minute),
+					ScoreState: "SCORED",
+					Score:      WorkoutScore{Strain: 11.2},
+				},
+				{
+					SportID:    44,
+					Start:      now.Add(-12 * time.Hour),
+					End:        now.Add(-12*time.Hour + time.Hour),
+					ScoreState: "SCORED",
+					Score:      WorkoutScore{Strain: 4},
+				},
+				{
+					SportID:    1,
+					ScoreState: "PENDING_SCORE",
+				},
+			},
+		},
+	}
+
+	summary := NewSummary(user)
+
+	if summary.Sleep == nil {
+		t.Fatal("Expected a sleep summary, got nil")
+	}
+	if summary.Sleep.Performance != 92 {
+		t.Errorf("Expected the last main sleep, got a sleep performance of %v", summary.Sleep.Performance)
+	}
+	if summary.Sleep.Asleep != 7*time.Hour {
+		t.Errorf("Expected 7h asleep, got %v", summary.Sleep.Asleep)
+	}
+	if summary.Sleep.Needed != 8*time.Hour+20*time.Minute {
+		t.Errorf("Expected 8h20m needed, got %v", summary.Sleep.Needed)
+	}
+
+	if summary.Recovery == nil || summary.Recovery.Score != 67 {
+		t.Errorf("Expected the recovery of the last sleep, got %+v", summary.Recovery)
+	}
+
+	if summary.Strain == nil || summary.Strain.Strain != 12.4 {
+		t.Errorf("Expected the strain of the last completed cycle, got %+v", summary.Strain)
+	}
+
+	expected := []WorkoutSummary{
+		{Sport: "Running", Count: 2, Duration: 45 * time.Minute, Strain: 11.2},
+		{Sport: "Yoga", Count: 1, Duration: time.Hour, Strain: 4},
+	}
+	if len(summary.Workouts) != len(expected) {
+		t.Fatalf("Expected %d workout summaries, got %d", len(expected), len(summary.Workouts))
+	}
+	for i := range expected {
+		if summary.Workouts[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], summary.Workouts[i])
+		}
+	}
+
+	tmpl, err := ParseSummaryTemplate("")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	out, err := summary.Render(tmpl)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expectedOut := "😴 Sleep: 92% performance, 7h00m asleep, 8h00m in bed of 8h20m needed\n" +
+		"💚 Recovery: 67%, HRV 54.3 ms, RHR 52 bpm\n" +
+		"🔥 Strain: 12.4, 2415 kcal\n" +
+		"🏋️ Running: 2x, 45m, max strain 11.2\n" +
+		"🏋️ Yoga: 1x, 1h00m, max strain 4.0"
+	if out != expectedOut {
+		t.Errorf("Expected summary:\n%s\ngot:\n%s", expectedOut, out)
+	}
+}
+
+func TestNewSummaryEmpty(t *testing.T) {
+
+	summary := NewSummary(User{})
+
+	if summary.Sleep != nil || summary.Recovery != nil || summary.Strain != nil || len(summary.Workouts) != 0 {
+		t.Errorf("Expected an empty summary, got %+v", summary)
+	}
+
+	tmpl, err := ParseSummaryTemplate("")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	out, err := summary.Render(tmpl)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if out != "" {
+		t.Errorf("Expected an empty summary, got %q", out)
+	}
+}
+
+func TestParseSummaryTemplate(t *testing.T) {
+
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.tmpl")
+	err := os.WriteFile(valid, []byte(`{{ with .Strain }}Strain {{ printf "%.1f" .Strain }}{{ end }}`), 0600)
+	if err != nil {
+		t.Fatalf("unable to write the template: %v", err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.tmpl")
+	err = os.WriteFile(invalid, []byte(`{{ with .Strain }`), 0600)
+	if err != nil {
+		t.Fatalf("unable to write the template: %v", err)
+	}
+
+	tests := []struct {
+		description   string
+		filePath      string
+		errorExpected bool
+		expected      string
+	}{
+		{
+			description: "Custom template",
+			filePath:    valid,
+			expected:    "Strain 8.3",
+		},
+		{
+			description:   "Invalid template",
+			filePath:      invalid,
+			errorExpected: true,
+		},
+		{
+			description:   "Missing template",
+			filePath:      filepath.Join(dir, "missing.tmpl"),
+			errorExpected: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			tmpl, err := ParseSummaryTemplate(tc.filePath)
+			if (err != nil) != tc.errorExpected {
+				t.Fatalf("Expected error %v, got %v", tc.errorExpected, err)
+			}
+
+			if tc.errorExpected {
+				return
+			}
+
+			out, err := Summary{Strain: &StrainSummary{Strain: 8.3}}.Render(tmpl)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if out != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, out)
+			}
+		})
+	}
+}
+
+func TestSportName(t *testing.T) {
+
+	tests := []struct {
+		id       int
+		expected string
+	}{
+		{0, "Running"},
+		{44, "Yoga"},
+		{-1, "Activity"},
+		{9999, "Sport 9999"},
+	}
+
+	for _, tc := range tests {
+		if got := SportName(tc.id); got != tc.expected {
+			t.Errorf("Expected %s, got %s", tc.expected, got)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{45 * time.Minute, "45m"},
+		{7*time.Hour + 12*time.Minute, "7h12m"},
+		{8*time.Hour + 29*time.Second, "8h00m"},
+		{0, "0m"},
+	}
+
+	for _, tc := range tests {
+		if got := formatDuration(tc.duration); got != tc.expected {
+			t.Errorf("Expected %s, got %s", tc.expected, got)
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package internal
 
 import (
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -196,43 +197,270 @@ type AuthRequest struct {
 
 type ConfigurationData struct {
 	// Credentials is the configuration settings for Whoop API authentication credentials
-	Credentials Credentials `yaml:"credentials"`
+	Credentials Credentials `yaml:"credentials" json:"credentials"`
 	// Debug flag. Allowed values are DEBUG, WARN, INFO, TRACE
-	Debug string `yaml:"debug"`
+	Debug string `yaml:"debug" json:"debug"`
 	// Export is the configuration block for setting up data exporters
-	Export ConfigExport `yaml:"export" validate:"required"`
+	Export ConfigExport `yaml:"export" json:"export" validate:"required"`
 	// Notification is the configuration block for setting up notifications
-	Notification NotificationConfig `yaml:"notification"`
+	Notification NotificationConfig `yaml:"notification" json:"notification"`
 	// Server is the configuration settings for server mode
-	Server Server `yaml:"server"`
+	Server Server `yaml:"server" json:"server"`
+	// NotificationDelivery is the configuration of the retries, de-duplication, rate limit, and spool of the notifications.
+	NotificationDelivery NotificationDelivery `yaml:"notificationDelivery" json:"notificationDelivery"`
+	// History is the configuration of the run history of the server jobs.
+	History HistoryConfig `yaml:"history" json:"history"`
+	// Summary is the configuration of the daily summary sent with the success notifications of the data collection job.
+	Summary SummaryConfig `yaml:"summary" json:"summary"`
+	// Alerts is the configuration of the health alerts evaluated after each data collection in server mode.
+	Alerts AlertsConfig `yaml:"alerts" json:"alerts"`
+	// Accounts is a list of Whoop accounts to manage. Each account uses its own credentials and can override the export and notification settings.
+	Accounts []Account `yaml:"accounts" json:"accounts" validate:"unique=Name,dive"`
+	// Log is the configuration of the log format, destination, and component levels.
+	Log LogConfig `yaml:"log" json:"log"`
+}
+
+// Account is a Whoop account managed by MyWhoop. The account uses its own credentials and can override the export and notification settings.
+type Account struct {
+	// Name is the unique name of the account. The name is used to label the exported data and notifications. Only letters, numbers, dashes and underscores are allowed.
+	Name string `yaml:"name" json:"name" validate:"required"`
+	// Credentials is the credentials store of the account. By default, a local file by the name of "token_<name>.json" is used.
+	Credentials Credentials `yaml:"credentials" json:"credentials"`
+	// Export overrides the top-level export configuration for the account.
+	Export *ConfigExport `yaml:"export" json:"export"`
+	// Notification overrides the top-level notification configuration for the account.
+	Notification *NotificationConfig `yaml:"notification" json:"notification"`
 }
 
 type ConfigExport struct {
-	Method     string            `yaml:"method" validate:"oneof=file s3"`
-	FileExport export.FileExport `yaml:"fileExport" validate:"required_if=Method file"`
-	AWSS3      export.AWS_S3     `yaml:"awsS3" validate:"required_if=Method s3"`
+	Method     string            `yaml:"method" json:"method" validate:"oneof=file s3"`
+	FileExport export.FileExport `yaml:"fileExport" json:"fileExport" validate:"required_if=Method file"`
+	AWSS3      export.AWS_S3     `yaml:"awsS3" json:"awsS3" validate:"required_if=Method s3"`
 	// Add more supported export methods here
 }
 
+// NotificationConfig is the configuration of a notification channel. The notification block of the configuration file accepts a single channel or a list of channels.
 type NotificationConfig struct {
-	// Method is the notification method to use. If no method is specified, then no external notification is sent.
-	Method string `yaml:"method" validate:"oneof=ntfy ''"`
+	// Method is the notification method to use. If no method is specified, then notifications are written to stdout.
+	Method string `yaml:"method" json:"method" validate:"oneof=ntfy slack discord email webhook gotify pushover telegram matrix stdout ''"`
+	// Events overrides the events filter of the notification method. Supported events are errors, success, alerts, or all. Use it to send different events to each channel of a list.
+	Events string `yaml:"events" json:"events" validate:"oneof=errors success alerts all ''"`
 	// Ntfy is the configuration settings for the Ntfy notification service.
-	Ntfy notifications.Ntfy `yaml:"ntfy" validate:"required_if=Method ntfy"`
+	Ntfy notifications.Ntfy `yaml:"ntfy" json:"ntfy" validate:"required_if=Method ntfy"`
+	// Slack is the configuration settings for the Slack notification service.
+	Slack notifications.Slack `yaml:"slack" json:"slack"`
+	// Discord is the configuration settings for the Discord notification service.
+	Discord notifications.Discord `yaml:"discord" json:"discord"`
+	// Email is the configuration settings for the email notification service.
+	Email notifications.Email `yaml:"email" json:"email" validate:"required_if=Method email"`
+	// Webhook is the configuration settings for the generic webhook notification service.
+	Webhook notifications.Webhook `yaml:"webhook" json:"webhook" validate:"required_if=Method webhook"`
+	// Gotify is the configuration settings for the Gotify notification service.
+	Gotify notifications.Gotify `yaml:"gotify" json:"gotify" validate:"required_if=Method gotify"`
+	// Pushover is the configuration settings for the Pushover notification service.
+	Pushover notifications.Pushover `yaml:"pushover" json:"pushover"`
+	// Telegram is the configuration settings for the Telegram notification service.
+	Telegram notifications.Telegram `yaml:"telegram" json:"telegram" validate:"required_if=Method telegram"`
+	// Matrix is the configuration settings for the Matrix notification service.
+	Matrix notifications.Matrix `yaml:"matrix" json:"matrix" validate:"required_if=Method matrix"`
+	// Stdout is the configuration settings for the stdout notifications. Stdout is used if no method is specified.
+	Stdout notifications.Stdout `yaml:"stdout" json:"stdout"`
+	// Channels contains the notification channels when the notification block is a list. Every channel receives the notifications matching its events filter.
+	Channels []NotificationConfig `yaml:"-" json:"-" validate:"dive"`
+}
+
+// NotificationDelivery is the configuration of the delivery of the notifications. The settings apply to every notification channel.
+type NotificationDelivery struct {
+	// MaxRetries is the number of times a failed notification is retried. Default is 3. Set to -1 to disable retries.
+	MaxRetries int `yaml:"maxRetries" json:"maxRetries" validate:"min=-1"`
+	// DedupWindow is the window in minutes during which identical error and alert notifications are only sent once. Default is 60 minutes. Set to -1 to disable the de-duplication.
+	DedupWindow int `yaml:"dedupWindow" json:"dedupWindow" validate:"min=-1"`
+	// RateLimit is the maximum number of notifications a channel sends per hour. Notifications above the limit are dropped. Default is 0, which disables the rate limit.
+	RateLimit int `yaml:"rateLimit" json:"rateLimit" validate:"min=0"`
+	// SpoolDir is the directory where undelivered notifications are stored. The stored notifications are sent every 15 minutes and after a restart. Undelivered notifications are dropped if no directory is provided.
+	SpoolDir string `yaml:"spoolDir" json:"spoolDir"`
+}
+
+// LogConfig is the configuration of the logs. The level of the logs is set with the debug field or the --debug flag.
+type LogConfig struct {
+	// Format is the format of the log records. Supported formats are text, json, and logfmt. Default is text.
+	Format string `yaml:"format" json:"format" validate:"oneof=text json logfmt ''"`
+	// Destination is where the log records are written. Supported destinations are stdout, stderr, and file. Default is stdout.
+	Destination string `yaml:"destination" json:"destination" validate:"oneof=stdout stderr file ''"`
+	// File is the configuration of the log file used with the file destination.
+	File LogFile `yaml:"file" json:"file"`
+	// Levels overrides the log level of the components.
+	Levels LogLevels `yaml:"levels" json:"levels"`
+}
+
+// LogFile is the configuration of a log file rotated by size and age. The rotated files are named after the time of the rotation.
+type LogFile struct {
+	// Path is the path of the log file. Default is mywhoop.log.
+	Path string `yaml:"path" json:"path"`
+	// MaxSize is the size in megabytes the log file is rotated at. Default is 10 megabytes.
+	MaxSize int `yaml:"maxSize" json:"maxSize" validate:"min=0"`
+	// MaxAge is the time in hours the log file is rotated after. Default is 24 hours. Set to -1 to disable the age-based rotation.
+	MaxAge int `yaml:"maxAge" json:"maxAge" validate:"min=-1"`
+	// MaxBackups is the number of rotated files kept. The oldest rotated files are deleted. Default is 7. Set to -1 to keep every rotated file.
+	MaxBackups int `yaml:"maxBackups" json:"maxBackups" validate:"min=-1"`
+}
+
+// LogLevels overrides the log level of the components. Allowed values are DEBUG, INFO, WARN, and ERROR. The level of the debug field is used if empty.
+type LogLevels struct {
+	// API is the log level of the Whoop API requests.
+	API string `yaml:"api" json:"api" validate:"oneof=DEBUG INFO WARN ERROR debug info warn error ''"`
+	// Export is the log level of the data exporters.
+	Export string `yaml:"export" json:"export" validate:"oneof=DEBUG INFO WARN ERROR debug info warn error ''"`
+	// Notify is the log level of the notification methods.
+	Notify string `yaml:"notify" json:"notify" validate:"oneof=DEBUG INFO WARN ERROR debug info warn error ''"`
+	// Scheduler is the log level of the server scheduler.
+	Scheduler string `yaml:"scheduler" json:"scheduler" validate:"oneof=DEBUG INFO WARN ERROR debug info warn error ''"`
+}
+
+// HistoryConfig is the configuration of the run history. The runs of the server jobs are recorded to a JSON lines file.
+type HistoryConfig struct {
+	// File is the path of the history file. Default is history.jsonl.
+	File string `yaml:"file" json:"file"`
+	// Disabled disables the run history. Default is false.
+	Disabled bool `yaml:"disabled" json:"disabled"`
+}
+
+// SummaryConfig is the configuration of the daily summary.
+type SummaryConfig struct {
+	// Template is the path to a Go template file used to render the daily summary. A built-in template is used if no file is provided.
+	Template string `yaml:"template" json:"template"`
+}
+
+// AlertsConfig contains the rules of the health alerts. A rule is disabled if its threshold is zero.
+type AlertsConfig struct {
+	// RecoveryBelow sends an alert if the recovery score percentage is below the value.
+	RecoveryBelow float64 `yaml:"recoveryBelow" json:"recoveryBelow" validate:"min=0,max=100"`
+	// HRVDropPercent sends an alert if the HRV is more than the percentage below the average HRV of the previous 7 days.
+	HRVDropPercent float64 `yaml:"hrvDropPercent" json:"hrvDropPercent" validate:"min=0,max=100"`
+	// SpO2Below sends an alert if the blood oxygen percentage is below the value.
+	SpO2Below float64 `yaml:"spo2Below" json:"spo2Below" validate:"min=0,max=100"`
+	// SkinTempDeviation sends an alert if the skin temperature deviates by more than the value in degrees Celsius from the average skin temperature of the previous 7 days.
+	SkinTempDeviation float64 `yaml:"skinTempDeviation" json:"skinTempDeviation" validate:"min=0"`
+	// SleepDebtAbove sends an alert if the sleep needed to pay back the sleep debt is above the value in minutes.
+	SleepDebtAbove int `yaml:"sleepDebtAbove" json:"sleepDebtAbove" validate:"min=0"`
 }
 
 type Server struct {
 	// Set to true to enable server mode. Default is false.
-	Enabled bool `yaml:"enabled"`
+	Enabled bool `yaml:"enabled" json:"enabled"`
 	// A cron tab string to schedule the server to run at specific times. Default is every 24 hours at 1300 hours -  0 13 * * *.
-	Crontab string `yaml:"crontab"`
+	Crontab string `yaml:"crontab" json:"crontab"`
 	//JWTRefreshDuration is the duration to refresh the JWT token in minutes. Default is 45 minutes. This value must be greater than 0 and less than 59 minutes.
-	JWTRefreshDuration int `yaml:"jwtRefreshDuration"`
+	JWTRefreshDuration int `yaml:"jwtRefreshDuration" json:"jwtRefreshDuration"`
+	// WatchConfig reloads the configuration when the configuration file changes. The configuration can always be reloaded by sending the SIGHUP signal. Default is false.
+	WatchConfig bool `yaml:"watchConfig" json:"watchConfig"`
+	// Supervisor is the configuration of the retries and the circuit breaker of the server jobs.
+	Supervisor Supervisor `yaml:"supervisor" json:"supervisor"`
+	// CatchUp is the configuration of the collection of the scheduled runs missed while the server was not running.
+	CatchUp CatchUp `yaml:"catchUp" json:"catchUp"`
+	// Lock is the configuration of the lock that prevents two servers on the same host from running the jobs.
+	Lock InstanceLock `yaml:"lock" json:"lock"`
+	// LeaderElection is the configuration of the leader election of the servers running as multiple replicas. Only the leader runs the jobs.
+	LeaderElection LeaderElection `yaml:"leaderElection" json:"leaderElection"`
+	// Jobs is the list of data collection jobs. If no jobs are configured, a single job collects the data of the last 24 hours using the crontab.
+	Jobs []ServerJob `yaml:"jobs" json:"jobs" validate:"unique=Name,dive"`
+}
+
+// ServerJob is a scheduled data collection job. Each job collects its own window and collections and exports the data using its own format and exporter.
+type ServerJob struct {
+	// Name is the unique name of the job. The name labels the exported data, the notifications, and the run history. Only letters, numbers, dashes and underscores are allowed.
+	Name string `yaml:"name" json:"name" validate:"required"`
+	// Crontab is the cron tab string scheduling the job. Default is the crontab of the server.
+	Crontab string `yaml:"crontab" json:"crontab"`
+	// Window is the lookback window of the job in hours. Default is 24 hours.
+	Window int `yaml:"window" json:"window" validate:"min=0"`
+	// Collections is the list of collections fetched by the job. Supported collections are sleep, recovery, workout, and cycle. Default is all collections.
+	Collections []string `yaml:"collections" json:"collections" validate:"dive,oneof=sleep recovery workout cycle"`
+	// Format overrides the file type of the exported data. Supported formats are json and xlsx.
+	Format string `yaml:"format" json:"format" validate:"oneof=json xlsx ''"`
+	// Export overrides the export configuration for the job.
+	Export *ConfigExport `yaml:"export" json:"export"`
+	// Summary adds the daily summary to the success notification of the job. Default is true for the jobs with a window of 24 hours and false otherwise.
+	Summary *bool `yaml:"summary" json:"summary"`
+	// Alerts evaluates the health alerts against the data collected by the job. Default is true for the jobs with a window of 24 hours and false otherwise.
+	Alerts *bool `yaml:"alerts" json:"alerts"`
+}
+
+// InstanceLock is the configuration of the single-instance lock of the server. The server holds an exclusive lock on the lock file and writes its process ID to it.
+// A second server using the same lock file refuses to start.
+type InstanceLock struct {
+	// File is the lock file. Default is mywhoop.lock in the directory of the credentials file.
+	File string `yaml:"file" json:"file"`
+	// Disabled disables the single-instance lock. Default is false.
+	Disabled bool `yaml:"disabled" json:"disabled"`
+}
+
+// LeaderElection is the configuration of the lease-based leader election of the server. The lease is an S3 object updated with conditional writes.
+// The replica holding the lease is the leader and runs the jobs. The other replicas take over the lease when it expires.
+type LeaderElection struct {
+	// Enabled enables the leader election. Default is false.
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Bucket is the S3 bucket the lease object is stored in.
+	Bucket string `yaml:"bucket" json:"bucket" validate:"required_if=Enabled true"`
+	// Key is the key of the lease object. Default is mywhoop/leader.json.
+	Key string `yaml:"key" json:"key"`
+	// Region is the AWS region of the bucket. The AWS_DEFAULT_REGION environment variable is used if empty.
+	Region string `yaml:"region" json:"region"`
+	// Profile is the AWS profile used to access the bucket. The AWS_PROFILE environment variable takes precedence.
+	Profile string `yaml:"profile" json:"profile"`
+	// Endpoint is a custom S3 endpoint, such as a LocalStack or MinIO URL. Path-style addressing is used with a custom endpoint.
+	Endpoint string `yaml:"endpoint" json:"endpoint"`
+	// LeaseDuration is the time in seconds the lease is valid without renewal. The leader renews the lease every third of the duration. Default is 60 seconds.
+	LeaseDuration int `yaml:"leaseDuration" json:"leaseDuration" validate:"min=0"`
+	// Identity identifies the replica in the lease. Default is the host name followed by the process ID.
+	Identity string `yaml:"identity" json:"identity"`
+}
+
+// CatchUp is the configuration of the catch-up collection. The time of the last successful data collection is stored in the state file.
+// On start, the server computes the runs of the crontab missed since the last successful collection and collects the data of the missed window before resuming the schedule.
+type CatchUp struct {
+	// Policy is the catch-up policy. Allowed values are skip, which ignores the missed runs, once, which collects the whole missed window in a single run, and each, which runs every missed run with its own window. Default is once.
+	Policy string `yaml:"policy" json:"policy" validate:"oneof=skip once each ''"`
+	// StateFile is the file the time of the last successful data collection is stored in. Default is mywhoop_state.json.
+	StateFile string `yaml:"stateFile" json:"stateFile"`
+}
+
+// Supervisor contains the retry policies of the server jobs and the circuit breaker that pauses a job after repeated failures.
+type Supervisor struct {
+	// DataCollection is the retry policy of the data collection job.
+	DataCollection RetryPolicy `yaml:"dataCollection" json:"dataCollection"`
+	// TokenRefresh is the retry policy of the token refresh jobs.
+	TokenRefresh RetryPolicy `yaml:"tokenRefresh" json:"tokenRefresh"`
+	// CircuitBreaker is the configuration of the circuit breaker of the server jobs.
+	CircuitBreaker CircuitBreaker `yaml:"circuitBreaker" json:"circuitBreaker"`
+}
+
+// RetryPolicy is the retry policy of a server job run.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts of a job run before the run gives up. Default is 3. Set to 1 to disable retries.
+	MaxAttempts int `yaml:"maxAttempts" json:"maxAttempts" validate:"min=0"`
+	// Backoff is the initial interval in seconds between the attempts of a job run. The interval doubles after every attempt. Default is 30 seconds.
+	Backoff int `yaml:"backoff" json:"backoff" validate:"min=0"`
+	// GiveUp is the action taken when a job run gives up. Allowed values are skip, which waits for the next scheduled run, and exit, which shuts down the server. Default is skip.
+	GiveUp string `yaml:"giveUp" json:"giveUp" validate:"oneof=skip exit ''"`
+}
+
+// CircuitBreaker is the configuration of the circuit breaker of the server jobs.
+// The circuit breaker opens after consecutive failed runs of a job and skips the runs of the job until the cooldown elapses.
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failed runs of a job that open the circuit breaker. Default is 3. Set to -1 to disable the circuit breaker.
+	FailureThreshold int `yaml:"failureThreshold" json:"failureThreshold" validate:"min=-1"`
+	// Cooldown is the time in minutes the circuit breaker stays open before the job runs again. Default is 360 minutes.
+	Cooldown int `yaml:"cooldown" json:"cooldown" validate:"min=0"`
 }
 
 type Credentials struct {
 	// The file path to the credentials file. By default, a local file by the name of "token.json" is looked for.
-	CredentialsFile string `yaml:"credentialsFile"`
+	CredentialsFile string `yaml:"credentialsFile" json:"credentialsFile"`
+	// ClientID is the client ID of the Whoop application. The value can be a secret reference, such as env:MY_VARIABLE or file:/run/secrets/whoop_client_id.
+	ClientID string `yaml:"clientID" json:"clientID"`
+	// ClientSecret is the client secret of the Whoop application. The value can be a secret reference, such as env:MY_VARIABLE or file:/run/secrets/whoop_client_secret.
+	ClientSecret string `yaml:"clientSecret" json:"clientSecret" secret:"true"`
 }
 
 /* Event
@@ -246,6 +474,7 @@ type Event string
 const (
 	EventErrors  Event = "errors"
 	EventSuccess Event = "success"
+	EventAlerts  Event = "alerts"
 	EventAll     Event = "all"
 )
 
@@ -257,6 +486,8 @@ func EventFromString(s string) Event {
 		return EventErrors
 	case "success":
 		return EventSuccess
+	case "alerts":
+		return EventAlerts
 	case "all":
 		return EventAll
 	default:
@@ -276,6 +507,26 @@ type Export interface {
 	CleanUp() error
 }
 
+// ExportLocator is implemented by exporters that can report the location of the exported data, such as a file path or a URL.
+type ExportLocator interface {
+	// Location returns the location of the last exported data. An empty string is returned if no data has been exported.
+	Location() string
+}
+
+// DatedExport is implemented by exporters that name the exported data after a date in server mode.
+type DatedExport interface {
+	// SetDate sets the date used to name the exported data. A zero date restores the current date.
+	SetDate(date time.Time)
+	// SetDateLayout sets the layout of the date used to name the exported data. An empty layout restores the default layout.
+	SetDateLayout(layout string)
+}
+
+// LoggedExport is implemented by exporters that accept the logger of a job run, so that the records of the exporter carry the log attributes of the run.
+type LoggedExport interface {
+	// SetLogger sets the logger of the exporter. A nil logger restores the default logger of the exporters.
+	SetLogger(logger *slog.Logger)
+}
+
 // Notification is an interface that defines the methods for a notification service.
 // It requires two method functions SetUp and Send.
 // Consumers can use the Publish method to send notifications using the notification service.
@@ -0,0 +1,150 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	// discordDescriptionLimit is the maximum number of characters of a Discord embed description.
+	discordDescriptionLimit = 4096
+	// discordFieldLimit is the maximum number of characters of a Discord embed field value.
+	discordFieldLimit = 1024
+	// discordColorErrors is the embed color of error notifications.
+	discordColorErrors = 0xE01E5A
+	// discordColorSuccess is the embed color of success notifications.
+	discordColorSuccess = 0x2EB67D
+	// discordColorAlerts is the embed color of alert notifications.
+	discordColorAlerts = 0xECB22E
+	// discordColorDefault is the embed color of other notifications.
+	discordColorDefault = 0x5865F2
+)
+
+// NewDiscord returns a new Discord struct with default values.
+func NewDiscord() *Discord {
+	return &Discord{
+		WebhookURL: "",
+		Events:     "errors",
+		UserName:   "MyWhoop",
+	}
+}
+
+// SetUp sets up the Discord service. The environment variable NOTIFICATION_DISCORD_WEBHOOK_URL is used to set the webhook URL if no webhook URL is configured.
+func (d *Discord) SetUp() error {
+
+	if d.WebhookURL == "" {
+		d.WebhookURL = os.Getenv("NOTIFICATION_DISCORD_WEBHOOK_URL")
+	}
+
+	if d.WebhookURL == "" {
+		return errors.New("no Discord webhook URL provided. Provide the webhook URL through the webhookURL field or the NOTIFICATION_DISCORD_WEBHOOK_URL environment variable")
+	}
+
+	if d.Events == "" {
+		d.Events = "errors"
+	}
+
+	return nil
+}
+
+// Accepts returns true if the events filter of the Discord notification accepts the event.
+func (d *Discord) Accepts(event string) bool {
+	return canSendMsg(d.Events, event)
+}
+
+// Publish sends a notification using the Discord webhook with the provided data.
+func (d *Discord) Publish(client *http.Client, data []byte, event string) error {
+
+	if data == nil {
+		return errors.New("no data provided for external notification")
+	}
+
+	return d.PublishMessage(client, NewMessage(data, event))
+}
+
+// PublishMessage sends the structured message using the Discord webhook. The message is formatted as a Discord embed.
+func (d *Discord) PublishMessage(client *http.Client, msg Message) error {
+
+	if client == nil {
+		msg.Logger().Info("no http client specified for external notification")
+		return errors.New("no http client specified for external notification")
+	}
+
+	if msg.Event == "" {
+		return errors.New("no event provided for external notification")
+	}
+
+	if !d.Accepts(msg.Event) {
+		msg.Logger().Info("event is not eligible for external notification. Notification suppresed.")
+		return nil
+	}
+
+	err := postJSON(msg.Logger(), client, d.WebhookURL, discordPayload(msg, d.UserName))
+	if err != nil {
+		return err
+	}
+
+	msg.Logger().Info("notification sent", "service", "discord")
+
+	return nil
+}
+
+// discordPayload returns the Discord webhook payload of the message.
+func discordPayload(msg Message, userName string) map[string]interface{} {
+
+	field := func(name, value string, inline bool) map[string]interface{} {
+		return map[string]interface{}{"name": name, "value": value, "inline": inline}
+	}
+
+	fields := []interface{}{
+		field("Event", msg.Event, true),
+	}
+
+	if msg.Job != "" {
+		fields = append(fields, field("Job", msg.Job, true))
+	}
+
+	if msg.Account != "" {
+		fields = append(fields, field("Account", msg.Account, true))
+	}
+
+	if msg.Error != "" {
+		fields = append(fields, field("Error context", "```"+truncate(msg.Error, discordFieldLimit-6)+"```", false))
+	}
+
+	color := discordColorDefault
+	switch msg.Event {
+	case "errors":
+		color = discordColorErrors
+	case "success":
+		color = discordColorSuccess
+	case "alerts":
+		color = discordColorAlerts
+	}
+
+	embed := map[string]interface{}{
+		"title":       msg.Title(),
+		"description": truncate(msg.Body, discordDescriptionLimit),
+		"color":       color,
+		"fields":      fields,
+	}
+
+	if !msg.Timestamp.IsZero() {
+		embed["timestamp"] = msg.Timestamp.UTC().Format(time.RFC3339)
+	}
+
+	payload := map[string]interface{}{
+		"embeds": []interface{}{embed},
+	}
+
+	if userName != "" {
+		payload["username"] = userName
+	}
+
+	return payload
+}
@@ -0,0 +1,130 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestDiscordSetUp(t *testing.T) {
+
+	clearEnvVariables()
+
+	discord := NewDiscord()
+	err := discord.SetUp()
+	if err == nil {
+		t.Errorf("Expected error due to missing webhook URL but got nil")
+	}
+
+	os.Setenv("NOTIFICATION_DISCORD_WEBHOOK_URL", "https://discord.com/api/webhooks/1234/abcd")
+
+	err = discord.SetUp()
+	if err != nil {
+		t.Errorf("Error setting up Discord service: %v", err)
+	}
+
+	if discord.UserName != "MyWhoop" {
+		t.Errorf("Expected the default user name, got %v", discord.UserName)
+	}
+
+	clearEnvVariables()
+}
+
+func TestDiscordPublish(t *testing.T) {
+
+	clearEnvVariables()
+
+	var payload struct {
+		Username string `json:"username"`
+		Embeds   []struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Color       int    `json:"color"`
+			Timestamp   string `json:"timestamp"`
+			Fields      []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"fields"`
+		} `json:"embeds"`
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&payload)
+		if err != nil {
+			t.Errorf("Unable to decode the Discord payload: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	discord := NewDiscord()
+	discord.WebhookURL = ts.URL
+
+	err := discord.Publish(&http.Client{}, []byte("Error running the server job. Additional context below: \n connection refused"), "errors")
+	if err != nil {
+		t.Fatalf("Error publishing the Discord notification: %v", err)
+	}
+
+	if payload.Username != "MyWhoop" {
+		t.Errorf("Expected user name MyWhoop, got %v", payload.Username)
+	}
+
+	if len(payload.Embeds) != 1 {
+		t.Fatalf("Expected one embed, got %d", len(payload.Embeds))
+	}
+
+	embed := payload.Embeds[0]
+	if embed.Description != "Error running the server job." {
+		t.Errorf("Expected the message as the embed description, got %q", embed.Description)
+	}
+
+	if embed.Color != discordColorErrors {
+		t.Errorf("Expected the error color, got %d", embed.Color)
+	}
+
+	if embed.Timestamp == "" {
+		t.Errorf("Expected an embed timestamp")
+	}
+
+	fields := make(map[string]string)
+	for _, field := range embed.Fields {
+		fields[field.Name] = field.Value
+	}
+
+	if fields["Event"] != "errors" {
+		t.Errorf("Expected the event field, got %v", fields)
+	}
+
+	if fields["Error context"] != "```connection refused```" {
+		t.Errorf("Expected the error context field, got %v", fields)
+	}
+}
+
+func TestDiscordPublishSuppressed(t *testing.T) {
+
+	clearEnvVariables()
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	discord := NewDiscord()
+	discord.WebhookURL = ts.URL
+
+	err := discord.Publish(&http.Client{}, []byte("Daily data collection complete."), "success")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if requests != 0 {
+		t.Errorf("Expected the success event to be suppressed, got %d requests", requests)
+	}
+}
@@ -0,0 +1,398 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	// emailDialTimeout is the maximum time to wait for the connection to the SMTP server.
+	emailDialTimeout = 30 * time.Second
+	// defaultEmailSubject is the default template of the email subject.
+	defaultEmailSubject = "MyWhoop {{ .Event }} notification"
+	// defaultEmailTextTemplate is the default template of the plain text email body.
+	defaultEmailTextTemplate = `{{ .Title }}
+
+{{ .Body }}
+{{ if .Error }}
+Error context:
+{{ .Error }}
+{{ end }}
+Event: {{ .Event }}
+{{- if .Job }}
+Job: {{ .Job }}
+{{- end }}
+{{- if .Account }}
+Account: {{ .Account }}
+{{- end }}
+Time: {{ .Timestamp.Format "2006-01-02 15:04:05 MST" }}
+`
+	// defaultEmailHTMLTemplate is the default template of the HTML email body.
+	defaultEmailHTMLTemplate = `<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #1d1c1d;">
+  <h2>{{ .Title }}</h2>
+  <p>{{ .Body }}</p>
+  {{- if .Error }}
+  <h3>Error context</h3>
+  <pre style="background: #f6f8fa; padding: 12px; white-space: pre-wrap;">{{ .Error }}</pre>
+  {{- end }}
+  <table style="border-collapse: collapse;">
+    <tr><td style="padding-right: 12px;"><strong>Event</strong></td><td>{{ .Event }}</td></tr>
+    {{- if .Job }}
+    <tr><td style="padding-right: 12px;"><strong>Job</strong></td><td>{{ .Job }}</td></tr>
+    {{- end }}
+    {{- if .Account }}
+    <tr><td style="padding-right: 12px;"><strong>Account</strong></td><td>{{ .Account }}</td></tr>
+    {{- end }}
+    <tr><td style="padding-right: 12px;"><strong>Time</strong></td><td>{{ .Timestamp.Format "2006-01-02 15:04:05 MST" }}</td></tr>
+  </table>
+</body>
+</html>
+`
+)
+
+// emailTemplates contains the parsed templates of the email notification.
+type emailTemplates struct {
+	subject *template.Template
+	text    *template.Template
+	html    *htmltemplate.Template
+}
+
+// NewEmail returns a new Email struct with default values.
+func NewEmail() *Email {
+	return &Email{
+		TLS:     "starttls",
+		Subject: defaultEmailSubject,
+		Events:  "errors",
+	}
+}
+
+// SetUp sets up the email service. The environment variables NOTIFICATION_EMAIL_USERNAME and NOTIFICATION_EMAIL_PASSWORD are used to authenticate with the SMTP server.
+// The subject and body templates are parsed during the set up so that template errors are reported on startup.
+func (e *Email) SetUp() error {
+
+	userName := os.Getenv("NOTIFICATION_EMAIL_USERNAME")
+	if userName != "" {
+		e.UserName = userName
+	}
+
+	pwd := os.Getenv("NOTIFICATION_EMAIL_PASSWORD")
+	if pwd != "" {
+		e.Password = pwd
+	}
+
+	if e.Host == "" {
+		return errors.New("no SMTP host provided for the email notification")
+	}
+
+	if e.From == "" {
+		return errors.New("no sender address provided for the email notification")
+	}
+
+	if len(e.To) == 0 {
+		return errors.New("no recipients provided for the email notification")
+	}
+
+	if e.UserName != "" && e.Password == "" {
+		return errors.New("no SMTP password provided. Provide the password through the NOTIFICATION_EMAIL_PASSWORD environment variable")
+	}
+
+	if e.TLS == "" {
+		e.TLS = "starttls"
+	}
+
+	if e.Port == 0 {
+		e.Port = defaultEmailPort(e.TLS)
+	}
+
+	if e.Subject == "" {
+		e.Subject = defaultEmailSubject
+	}
+
+	if e.Events == "" {
+		e.Events = "errors"
+	}
+
+	templates, err := parseEmailTemplates(e.Subject, e.TextTemplate, e.HTMLTemplate)
+	if err != nil {
+		return err
+	}
+	e.templates = templates
+
+	return nil
+}
+
+// Accepts returns true if the events filter of the email notification accepts the event.
+func (e *Email) Accepts(event string) bool {
+	return canSendMsg(e.Events, event)
+}
+
+// Publish sends an email notification with the provided data. The HTTP client is not used by the email service.
+func (e *Email) Publish(client *http.Client, data []byte, event string) error {
+
+	if data == nil {
+		return errors.New("no data provided for external notification")
+	}
+
+	return e.PublishMessage(client, NewMessage(data, event))
+}
+
+// PublishMessage sends the structured message as an email with an HTML and plain text body.
+func (e *Email) PublishMessage(client *http.Client, msg Message) error {
+
+	if msg.Event == "" {
+		return errors.New("no event provided for external notification")
+	}
+
+	if !e.Accepts(msg.Event) {
+		msg.Logger().Info("event is not eligible for external notification. Notification suppresed.")
+		return nil
+	}
+
+	if e.templates == nil {
+		return errors.New("the email notification is not set up")
+	}
+
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+
+	content, err := e.buildMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	err = e.send(content)
+	if err != nil {
+		msg.Logger().Error("unable to send email notification", "host", e.Host, "error", err)
+		return err
+	}
+
+	msg.Logger().Info("notification sent", "service", "email", "recipients", len(e.To))
+
+	return nil
+}
+
+// buildMessage renders the templates and returns the MIME encoded email.
+func (e *Email) buildMessage(msg Message) ([]byte, error) {
+
+	var subject, text, html bytes.Buffer
+
+	err := e.templates.subject.Execute(&subject, msg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render the email subject: %w", err)
+	}
+
+	err = e.templates.text.Execute(&text, msg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render the plain text email body: %w", err)
+	}
+
+	err = e.templates.html.Execute(&html, msg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render the HTML email body: %w", err)
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{contentType: "text/plain; charset=UTF-8", content: text.Bytes()},
+		{contentType: "text/html; charset=UTF-8", content: html.Bytes()},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write(part.content); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	err = mw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	headers := []string{
+		"From: " + e.From,
+		"To: " + strings.Join(e.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("UTF-8", strings.TrimSpace(subject.String())),
+		"Date: " + msg.Timestamp.Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + mw.Boundary(),
+	}
+	out.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+	out.Write(body.Bytes())
+
+	return out.Bytes(), nil
+}
+
+// send delivers the email to the SMTP server using the configured TLS mode.
+func (e *Email) send(content []byte) error {
+
+	addr := net.JoinHostPort(e.Host, strconv.Itoa(e.Port))
+	tlsConfig := &tls.Config{ServerName: e.Host, MinVersion: tls.VersionTLS12}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	dialer := &net.Dialer{Timeout: emailDialTimeout}
+	if e.TLS == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to connect to the SMTP server %s: %w", addr, err)
+	}
+
+	err = conn.SetDeadline(time.Now().Add(emailDialTimeout))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	c, err := smtp.NewClient(conn, e.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("unable to start the SMTP session: %w", err)
+	}
+	defer c.Close()
+
+	if e.TLS == "starttls" {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("the SMTP server does not support STARTTLS. Set the tls option to tls or none")
+		}
+		err = c.StartTLS(tlsConfig)
+		if err != nil {
+			return fmt.Errorf("unable to start TLS: %w", err)
+		}
+	}
+
+	if e.UserName != "" {
+		err = c.Auth(smtp.PlainAuth("", e.UserName, e.Password, e.Host))
+		if err != nil {
+			return fmt.Errorf("unable to authenticate with the SMTP server: %w", err)
+		}
+	}
+
+	err = c.Mail(e.From)
+	if err != nil {
+		return err
+	}
+
+	for _, recipient := range e.To {
+		err = c.Rcpt(recipient)
+		if err != nil {
+			return fmt.Errorf("the SMTP server rejected the recipient %s: %w", recipient, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(content)
+	if err != nil {
+		w.Close()
+		return err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// defaultEmailPort returns the default SMTP port of the TLS mode.
+func defaultEmailPort(tlsMode string) int {
+
+	switch tlsMode {
+	case "tls":
+		return 465
+	case "none":
+		return 25
+	default:
+		return 587
+	}
+}
+
+// parseEmailTemplates parses the subject template and the body templates. The built-in body templates are used if no template file is provided.
+func parseEmailTemplates(subject, textFile, htmlFile string) (*emailTemplates, error) {
+
+	subjectTmpl, err := template.New("subject").Parse(subject)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the email subject template: %w", err)
+	}
+
+	textContent := defaultEmailTextTemplate
+	if textFile != "" {
+		content, err := os.ReadFile(textFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the plain text email template: %w", err)
+		}
+		textContent = string(content)
+	}
+
+	textTmpl, err := template.New("text").Parse(textContent)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the plain text email template: %w", err)
+	}
+
+	htmlContent := defaultEmailHTMLTemplate
+	if htmlFile != "" {
+		content, err := os.ReadFile(htmlFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the HTML email template: %w", err)
+		}
+		htmlContent = string(content)
+	}
+
+	htmlTmpl, err := htmltemplate.New("html").Parse(htmlContent)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the HTML email template: %w", err)
+	}
+
+	return &emailTemplates{
+		subject: subjectTmpl,
+		text:    textTmpl,
+		html:    htmlTmpl,
+	}, nil
+}
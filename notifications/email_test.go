@@ -0,0 +1,311 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// smtpMail is an email received by the SMTP sink.
+type smtpMail struct {
+	from string
+	to   []string
+	data []byte
+}
+
+// newSMTPSink starts a minimal SMTP server without TLS that records the received emails, similar to MailHog.
+func newSMTPSink(t *testing.T) (string, int, <-chan smtpMail) {
+
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to start the SMTP sink: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan smtpMail, 10)
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, received)
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return "127.0.0.1", addr.Port, received
+}
+
+// serveSMTP handles a single SMTP session of the SMTP sink.
+func serveSMTP(conn net.Conn, received chan<- smtpMail) {
+
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP sink")
+
+	var current smtpMail
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 localhost")
+		case "MAIL":
+			current = smtpMail{from: smtpAddress(line)}
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			current.to = append(current.to, smtpAddress(line))
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			current.data, err = tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			received <- current
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("250 OK")
+		}
+	}
+}
+
+// smtpAddress extracts the email address of a MAIL or RCPT command.
+func smtpAddress(line string) string {
+
+	start := strings.Index(line, "<")
+	end := strings.Index(line, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+
+	return line[start+1 : end]
+}
+
+func TestEmailSetUp(t *testing.T) {
+
+	clearEnvVariables()
+
+	tests := []struct {
+		name          string
+		email         Email
+		env           map[string]string
+		expectedError bool
+		expectedPort  int
+	}{
+		{
+			name:         "starttls defaults",
+			email:        Email{Host: "smtp.example.com", From: "mywhoop@example.com", To: []string{"alice@example.com"}},
+			expectedPort: 587,
+		},
+		{
+			name:         "implicit tls with authentication",
+			email:        Email{Host: "smtp.example.com", TLS: "tls", From: "mywhoop@example.com", To: []string{"alice@example.com"}},
+			env:          map[string]string{"NOTIFICATION_EMAIL_USERNAME": "mywhoop", "NOTIFICATION_EMAIL_PASSWORD": "secret"},
+			expectedPort: 465,
+		},
+		{
+			name:          "missing host",
+			email:         Email{From: "mywhoop@example.com", To: []string{"alice@example.com"}},
+			expectedError: true,
+		},
+		{
+			name:          "missing sender",
+			email:         Email{Host: "smtp.example.com", To: []string{"alice@example.com"}},
+			expectedError: true,
+		},
+		{
+			name:          "missing recipients",
+			email:         Email{Host: "smtp.example.com", From: "mywhoop@example.com"},
+			expectedError: true,
+		},
+		{
+			name:          "user name without password",
+			email:         Email{Host: "smtp.example.com", From: "mywhoop@example.com", To: []string{"alice@example.com"}},
+			env:           map[string]string{"NOTIFICATION_EMAIL_USERNAME": "mywhoop"},
+			expectedError: true,
+		},
+		{
+			name:          "invalid subject template",
+			email:         Email{Host: "smtp.example.com", From: "mywhoop@example.com", To: []string{"alice@example.com"}, Subject: "{{ .Event "},
+			expectedError: true,
+		},
+		{
+			name:          "missing template file",
+			email:         Email{Host: "smtp.example.com", From: "mywhoop@example.com", To: []string{"alice@example.com"}, HTMLTemplate: "does-not-exist.html"},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for key, value := range test.env {
+				t.Setenv(key, value)
+			}
+
+			email := test.email
+			err := email.SetUp()
+			if (err != nil) != test.expectedError {
+				t.Fatalf("Expected error: %v, got: %v", test.expectedError, err)
+			}
+
+			if !test.expectedError && email.Port != test.expectedPort {
+				t.Errorf("Expected port %d, got %d", test.expectedPort, email.Port)
+			}
+		})
+	}
+}
+
+func TestEmailPublish(t *testing.T) {
+
+	clearEnvVariables()
+
+	host, port, received := newSMTPSink(t)
+
+	textTemplate := filepath.Join(t.TempDir(), "email.txt")
+	err := os.WriteFile(textTemplate, []byte("Custom: {{ .Body }} ({{ .Job }})"), 0600)
+	if err != nil {
+		t.Fatalf("unable to write the template: %v", err)
+	}
+
+	email := NewEmail()
+	email.Host = host
+	email.Port = port
+	email.TLS = "none"
+	email.From = "mywhoop@example.com"
+	email.To = []string{"alice@example.com", "bob@example.com"}
+	email.TextTemplate = textTemplate
+	email.Events = "all"
+
+	err = email.SetUp()
+	if err != nil {
+		t.Fatalf("Error setting up the email service: %v", err)
+	}
+
+	err = email.PublishMessage(nil, Message{
+		Event: "errors",
+		Job:   "mywhoop_data_collection_job",
+		Body:  "Failed to export data.",
+		Error: "<access denied>",
+	})
+	if err != nil {
+		t.Fatalf("Error publishing the email notification: %v", err)
+	}
+
+	got := <-received
+
+	if got.from != "mywhoop@example.com" {
+		t.Errorf("Expected sender mywhoop@example.com, got %s", got.from)
+	}
+
+	if strings.Join(got.to, ",") != "alice@example.com,bob@example.com" {
+		t.Errorf("Expected two recipients, got %v", got.to)
+	}
+
+	m, err := mail.ReadMessage(strings.NewReader(string(got.data)))
+	if err != nil {
+		t.Fatalf("unable to parse the email: %v", err)
+	}
+
+	if m.Header.Get("Subject") != "MyWhoop errors notification" {
+		t.Errorf("Expected the default subject, got %s", m.Header.Get("Subject"))
+	}
+
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Expected a multipart/alternative email, got %s", m.Header.Get("Content-Type"))
+	}
+
+	parts := make(map[string]string)
+	mr := multipart.NewReader(m.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unable to read the email part: %v", err)
+		}
+		content, _ := io.ReadAll(part)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(content)
+	}
+
+	if parts["text/plain"] != "Custom: Failed to export data. (mywhoop_data_collection_job)" {
+		t.Errorf("Expected the custom plain text body, got %q", parts["text/plain"])
+	}
+
+	if !strings.Contains(parts["text/html"], "&lt;access denied&gt;") {
+		t.Errorf("Expected the escaped error context in the HTML body, got %q", parts["text/html"])
+	}
+}
+
+func TestEmailPublishSuppressed(t *testing.T) {
+
+	clearEnvVariables()
+
+	email := NewEmail()
+	email.Host = "127.0.0.1"
+	email.Port = 1
+	email.From = "mywhoop@example.com"
+	email.To = []string{"alice@example.com"}
+
+	err := email.SetUp()
+	if err != nil {
+		t.Fatalf("Error setting up the email service: %v", err)
+	}
+
+	// The success event is suppressed so no connection to the SMTP server is attempted
+	err = email.Publish(nil, []byte("Daily data collection complete."), "success")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	err = email.Publish(nil, []byte("Failed to export data."), "errors")
+	if err == nil || !strings.Contains(err.Error(), strconv.Itoa(email.Port)) {
+		t.Errorf("Expected a connection error, got %v", err)
+	}
+}
+
+func TestDefaultEmailTemplates(t *testing.T) {
+
+	templates, err := parseEmailTemplates(defaultEmailSubject, "", "")
+	if err != nil {
+		t.Fatalf("Expected the default templates to parse, got %v", err)
+	}
+
+	var out strings.Builder
+	err = templates.text.Execute(&out, NewMessage([]byte("Failed. Additional context below: boom"), "errors"))
+	if err != nil {
+		t.Fatalf("Expected the default text template to render, got %v", err)
+	}
+
+	for _, expected := range []string{"Failed.", "Error context:", "boom", "Event: errors"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected the rendered template to contain %q, got %q", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// gotifyPriorityErrors is the Gotify priority of error notifications. Priorities of 8 and above are shown as alerts by the Gotify Android app.
+	gotifyPriorityErrors = 8
+	// gotifyPrioritySuccess is the Gotify priority of success notifications.
+	gotifyPrioritySuccess = 4
+)
+
+// NewGotify returns a new Gotify struct with default values.
+func NewGotify() *Gotify {
+	return &Gotify{
+		ServerEndpoint: "",
+		Token:          "",
+		Events:         "errors",
+	}
+}
+
+// SetUp sets up the Gotify service. The environment variable NOTIFICATION_GOTIFY_TOKEN is used to set the application token.
+func (g *Gotify) SetUp() error {
+
+	token := os.Getenv("NOTIFICATION_GOTIFY_TOKEN")
+	if token != "" {
+		g.Token = token
+	}
+
+	if g.ServerEndpoint == "" {
+		return errors.New("no Gotify server endpoint provided")
+	}
+
+	if g.Token == "" {
+		return errors.New("no Gotify application token provided. Provide the token through the NOTIFICATION_GOTIFY_TOKEN environment variable")
+	}
+
+	if g.Events == "" {
+		g.Events = "errors"
+	}
+
+	return nil
+}
+
+// Accepts returns true if the events filter of the Gotify notification accepts the event.
+func (g *Gotify) Accepts(event string) bool {
+	return canSendMsg(g.Events, event)
+}
+
+// Publish sends a notification using the Gotify service with the provided data.
+func (g *Gotify) Publish(client *http.Client, data []byte, event string) error {
+
+	if data == nil {
+		return errors.New("no data provided for external notification")
+	}
+
+	return g.PublishMessage(client, NewMessage(data, event))
+}
+
+// PublishMessage sends the structured message using the Gotify service. The event is mapped to the Gotify priority.
+func (g *Gotify) PublishMessage(client *http.Client, msg Message) error {
+
+	if client == nil {
+		msg.Logger().Info("no http client specified for external notification")
+		return errors.New("no http client specified for external notification")
+	}
+
+	if msg.Event == "" {
+		return errors.New("no event provided for external notification")
+	}
+
+	if !g.Accepts(msg.Event) {
+		msg.Logger().Info("event is not eligible for external notification. Notification suppresed.")
+		return nil
+	}
+
+	payload := map[string]interface{}{
+		"title":    msg.Title(),
+		"message":  msg.Text(),
+		"priority": gotifyPriority(msg.Event),
+	}
+
+	err := sendJSON(msg.Logger(), client, http.MethodPost, strings.TrimSuffix(g.ServerEndpoint, "/")+"/message", map[string]string{"X-Gotify-Key": g.Token}, payload)
+	if err != nil {
+		return err
+	}
+
+	msg.Logger().Info("notification sent", "service", "gotify")
+
+	return nil
+}
+
+// gotifyPriority returns the Gotify priority of the event.
+func gotifyPriority(event string) int {
+
+	if event == "errors" || event == "alerts" {
+		return gotifyPriorityErrors
+	}
+
+	return gotifyPrioritySuccess
+}
@@ -0,0 +1,105 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestGotifySetUp(t *testing.T) {
+
+	clearEnvVariables()
+
+	gotify := NewGotify()
+	gotify.ServerEndpoint = "http://localhost:8080"
+
+	err := gotify.SetUp()
+	if err == nil {
+		t.Errorf("Expected error due to missing token but got nil")
+	}
+
+	os.Setenv("NOTIFICATION_GOTIFY_TOKEN", "AbCdEf")
+
+	err = gotify.SetUp()
+	if err != nil {
+		t.Errorf("Error setting up Gotify service: %v", err)
+	}
+
+	gotify.ServerEndpoint = ""
+	err = gotify.SetUp()
+	if err == nil {
+		t.Errorf("Expected error due to missing server endpoint but got nil")
+	}
+
+	clearEnvVariables()
+}
+
+func TestGotifyPublish(t *testing.T) {
+
+	clearEnvVariables()
+
+	tests := []struct {
+		name             string
+		event            string
+		expectedPriority int
+	}{
+		{
+			name:             "errors",
+			event:            "errors",
+			expectedPriority: gotifyPriorityErrors,
+		},
+		{
+			name:             "success",
+			event:            "success",
+			expectedPriority: gotifyPrioritySuccess,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var payload struct {
+				Title    string `json:"title"`
+				Message  string `json:"message"`
+				Priority int    `json:"priority"`
+			}
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/message" {
+					t.Errorf("Expected the /message path, got %s", r.URL.Path)
+				}
+				if r.Header.Get("X-Gotify-Key") != "AbCdEf" {
+					t.Errorf("Expected the application token header, got %s", r.Header.Get("X-Gotify-Key"))
+				}
+				err := json.NewDecoder(r.Body).Decode(&payload)
+				if err != nil {
+					t.Errorf("Unable to decode the Gotify payload: %v", err)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer ts.Close()
+
+			gotify := NewGotify()
+			gotify.ServerEndpoint = ts.URL + "/"
+			gotify.Token = "AbCdEf"
+			gotify.Events = "all"
+
+			err := gotify.Publish(&http.Client{}, []byte("Daily data collection complete."), test.event)
+			if err != nil {
+				t.Fatalf("Error publishing the Gotify notification: %v", err)
+			}
+
+			if payload.Priority != test.expectedPriority {
+				t.Errorf("Expected priority %d, got %d", test.expectedPriority, payload.Priority)
+			}
+
+			if payload.Message != "Daily data collection complete." {
+				t.Errorf("Expected the message, got %q", payload.Message)
+			}
+		})
+	}
+}
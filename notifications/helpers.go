@@ -3,6 +3,75 @@
 
 package notifications
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// postJSON sends the payload as a JSON document to the URL. The errors are logged with the logger. An error is returned if the server does not respond with a 2xx status code.
+func postJSON(log *slog.Logger, client *http.Client, url string, payload interface{}) error {
+
+	if url == "" {
+		return errors.New("no webhook URL provided for external notification")
+	}
+
+	return sendJSON(log, client, http.MethodPost, url, nil, payload)
+}
+
+// sendJSON sends the payload as a JSON document to the URL using the HTTP method and the additional headers.
+// An error is returned if the server does not respond with a 2xx status code.
+func sendJSON(log *slog.Logger, client *http.Client, method, url string, headers map[string]string, payload interface{}) error {
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("unable to marshal the notification payload: %w", err)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		log.Error("unable to create request for external notification", "error", err)
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	return doRequest(log, client, req)
+}
+
+// doRequest sends the request. An error is returned if the server does not respond with a 2xx status code.
+func doRequest(log *slog.Logger, client *http.Client, req *http.Request) error {
+
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Error("unable to send external notification", "error", err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		log.Error("unable to send external notification", "status code", resp.StatusCode, "response", string(respBody))
+		return fmt.Errorf("unable to send external notification. Status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// formatUnix returns the Unix timestamp of the time as a string.
+func formatUnix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
 // // Publish sends a notification to the user using the specified notification method.
 // func Publish(client *http.Client, notificationMethod Notification, msg []byte, event string) error {
 
@@ -22,3 +91,22 @@ package notifications
 
 // 	return nil
 // }
+
+// defaultLogger is the logger of the notification services used when a message does not provide a logger.
+var defaultLogger atomic.Pointer[slog.Logger]
+
+// SetDefaultLogger sets the logger of the notification services used when a message does not provide a logger, such as a logger labelled with the notify component.
+// The default logger of the slog package is used if nil.
+func SetDefaultLogger(logger *slog.Logger) {
+	defaultLogger.Store(logger)
+}
+
+// logger returns the default logger of the notification services.
+func logger() *slog.Logger {
+
+	if l := defaultLogger.Load(); l != nil {
+		return l
+	}
+
+	return slog.Default()
+}
@@ -0,0 +1,118 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// matrixTransactionCounter makes the transaction IDs of the messages sent by the process unique.
+var matrixTransactionCounter atomic.Uint64
+
+// NewMatrix returns a new Matrix struct with default values.
+func NewMatrix() *Matrix {
+	return &Matrix{
+		HomeServer:  "",
+		RoomID:      "",
+		AccessToken: "",
+		Events:      "errors",
+	}
+}
+
+// SetUp sets up the Matrix service. The environment variable NOTIFICATION_MATRIX_ACCESS_TOKEN is used to set the access token.
+func (m *Matrix) SetUp() error {
+
+	token := os.Getenv("NOTIFICATION_MATRIX_ACCESS_TOKEN")
+	if token != "" {
+		m.AccessToken = token
+	}
+
+	if m.HomeServer == "" || m.RoomID == "" {
+		return errors.New("no Matrix homeserver or room ID provided")
+	}
+
+	if m.AccessToken == "" {
+		return errors.New("no Matrix access token provided. Provide the token through the NOTIFICATION_MATRIX_ACCESS_TOKEN environment variable")
+	}
+
+	if m.Events == "" {
+		m.Events = "errors"
+	}
+
+	return nil
+}
+
+// Accepts returns true if the events filter of the Matrix notification accepts the event.
+func (m *Matrix) Accepts(event string) bool {
+	return canSendMsg(m.Events, event)
+}
+
+// Publish sends a notification to the Matrix room with the provided data.
+func (m *Matrix) Publish(client *http.Client, data []byte, event string) error {
+
+	if data == nil {
+		return errors.New("no data provided for external notification")
+	}
+
+	return m.PublishMessage(client, NewMessage(data, event))
+}
+
+// PublishMessage sends the structured message to the Matrix room. Error notifications are sent as text messages, which notify the room members.
+// Success notifications are sent as notices, which clients do not alert on by default.
+func (m *Matrix) PublishMessage(client *http.Client, msg Message) error {
+
+	if client == nil {
+		msg.Logger().Info("no http client specified for external notification")
+		return errors.New("no http client specified for external notification")
+	}
+
+	if msg.Event == "" {
+		return errors.New("no event provided for external notification")
+	}
+
+	if !m.Accepts(msg.Event) {
+		msg.Logger().Info("event is not eligible for external notification. Notification suppresed.")
+		return nil
+	}
+
+	payload := map[string]interface{}{
+		"msgtype":        matrixMessageType(msg.Event),
+		"body":           msg.Title() + "\n" + msg.Text(),
+		"format":         "org.matrix.custom.html",
+		"formatted_body": strings.Join(msg.htmlLines(), "<br>"),
+	}
+
+	txnID := fmt.Sprintf("mywhoop-%d-%d", time.Now().UnixNano(), matrixTransactionCounter.Add(1))
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimSuffix(m.HomeServer, "/"),
+		url.PathEscape(m.RoomID),
+		url.PathEscape(txnID),
+	)
+
+	err := sendJSON(msg.Logger(), client, http.MethodPut, endpoint, map[string]string{"Authorization": "Bearer " + m.AccessToken}, payload)
+	if err != nil {
+		return err
+	}
+
+	msg.Logger().Info("notification sent", "service", "matrix")
+
+	return nil
+}
+
+// matrixMessageType returns the Matrix message type of the event. Errors and alerts are sent as regular messages so that Matrix clients notify the room members.
+func matrixMessageType(event string) string {
+
+	if event == "errors" || event == "alerts" {
+		return "m.text"
+	}
+
+	return "m.notice"
+}
@@ -0,0 +1,128 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMatrixSetUp(t *testing.T) {
+
+	clearEnvVariables()
+
+	matrix := NewMatrix()
+	matrix.HomeServer = "https://matrix.org"
+	matrix.RoomID = "!abcdefg:matrix.org"
+
+	err := matrix.SetUp()
+	if err == nil {
+		t.Errorf("Expected error due to missing access token but got nil")
+	}
+
+	os.Setenv("NOTIFICATION_MATRIX_ACCESS_TOKEN", "syt_token")
+
+	err = matrix.SetUp()
+	if err != nil {
+		t.Errorf("Error setting up Matrix service: %v", err)
+	}
+
+	matrix.RoomID = ""
+	err = matrix.SetUp()
+	if err == nil {
+		t.Errorf("Expected error due to missing room ID but got nil")
+	}
+
+	clearEnvVariables()
+}
+
+func TestMatrixPublish(t *testing.T) {
+
+	clearEnvVariables()
+
+	tests := []struct {
+		name            string
+		event           string
+		expectedMsgType string
+	}{
+		{
+			name:            "errors",
+			event:           "errors",
+			expectedMsgType: "m.text",
+		},
+		{
+			name:            "success",
+			event:           "success",
+			expectedMsgType: "m.notice",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				paths   []string
+				payload struct {
+					MsgType       string `json:"msgtype"`
+					Body          string `json:"body"`
+					Format        string `json:"format"`
+					FormattedBody string `json:"formatted_body"`
+				}
+			)
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut {
+					t.Errorf("Expected the PUT method, got %s", r.Method)
+				}
+				if r.Header.Get("Authorization") != "Bearer syt_token" {
+					t.Errorf("Expected the access token, got %s", r.Header.Get("Authorization"))
+				}
+				paths = append(paths, r.URL.Path)
+				err := json.NewDecoder(r.Body).Decode(&payload)
+				if err != nil {
+					t.Errorf("Unable to decode the Matrix payload: %v", err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"event_id": "$event"}`))
+			}))
+			defer ts.Close()
+
+			matrix := NewMatrix()
+			matrix.HomeServer = ts.URL
+			matrix.RoomID = "!abcdefg:matrix.org"
+			matrix.AccessToken = "syt_token"
+			matrix.Events = "all"
+
+			for i := 0; i < 2; i++ {
+				err := matrix.Publish(&http.Client{}, []byte("Error running the server job. Additional context below: \n timeout"), test.event)
+				if err != nil {
+					t.Fatalf("Error publishing the Matrix notification: %v", err)
+				}
+			}
+
+			if len(paths) != 2 || paths[0] == paths[1] {
+				t.Errorf("Expected a unique transaction ID per message, got %v", paths)
+			}
+
+			if !strings.HasPrefix(paths[0], "/_matrix/client/v3/rooms/!abcdefg:matrix.org/send/m.room.message/") {
+				t.Errorf("Expected the room send endpoint, got %s", paths[0])
+			}
+
+			if payload.MsgType != test.expectedMsgType {
+				t.Errorf("Expected message type %s, got %s", test.expectedMsgType, payload.MsgType)
+			}
+
+			if payload.Format != "org.matrix.custom.html" || !strings.Contains(payload.FormattedBody, "<pre>timeout</pre>") {
+				t.Errorf("Expected the HTML formatted body, got %q", payload.FormattedBody)
+			}
+
+			if !strings.Contains(payload.Body, "Error context:\ntimeout") {
+				t.Errorf("Expected the plain text body, got %q", payload.Body)
+			}
+		})
+	}
+}
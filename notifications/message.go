@@ -0,0 +1,190 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"html"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// contextMarkers are the phrases MyWhoop uses to separate a notification message from its error context.
+var contextMarkers = []string{
+	"Additional context below:",
+	"Additional error message:",
+	"Additional error context:",
+}
+
+// Message is a structured notification. Notification services that support rich formatting use the message fields to format the notification.
+type Message struct {
+	// Event is the event of the notification, such as errors or success.
+	Event string
+	// Job is the name of the job that sent the notification. Empty if the notification is not sent by a job.
+	Job string
+	// Account is the name of the Whoop account the notification is about. Empty for the default account.
+	Account string
+	// Body is the notification message.
+	Body string
+	// Error is the error context of the notification. Empty if no error occurred.
+	Error string
+	// Timestamp is the time the notification was created.
+	Timestamp time.Time
+	// Link is a URL related to the notification, such as the location of the exported data. Empty if no link is available.
+	Link string
+	// Attachment is the file related to the notification, such as the exported data. Nil if no file is available.
+	Attachment *Attachment
+	// logger is the logger of the notification services publishing the message, such as the logger of a job run. The default logger of the notification services is used if nil.
+	logger *slog.Logger
+}
+
+// Attachment is a file that notification services can attach to a notification.
+type Attachment struct {
+	// Name is the file name of the attachment.
+	Name string
+	// Data is the content of the attachment.
+	Data []byte
+}
+
+// MessagePublisher is implemented by notification services that support structured messages.
+type MessagePublisher interface {
+	// PublishMessage sends the structured message using the notification service.
+	PublishMessage(client *http.Client, msg Message) error
+}
+
+// NewMessage returns a structured message from the data and event passed to the Publish method of a notification service.
+// The error context is extracted from the data when the data contains one of the phrases MyWhoop uses to introduce additional context.
+func NewMessage(data []byte, event string) Message {
+
+	msg := Message{
+		Event:     event,
+		Body:      strings.TrimSpace(string(data)),
+		Timestamp: time.Now(),
+	}
+
+	for _, marker := range contextMarkers {
+		before, after, found := strings.Cut(msg.Body, marker)
+		if found {
+			msg.Body = strings.TrimSpace(before)
+			msg.Error = strings.TrimSpace(after)
+			break
+		}
+	}
+
+	return msg
+}
+
+// WithLogger returns a copy of the message published with the logger, such as the logger of a job run, so that the records of the notification services carry the log attributes of the run.
+func (m Message) WithLogger(logger *slog.Logger) Message {
+	m.logger = logger
+	return m
+}
+
+// Logger returns the logger of the notification services publishing the message.
+func (m Message) Logger() *slog.Logger {
+
+	if m.logger != nil {
+		return m.logger
+	}
+
+	return logger()
+}
+
+// String returns the plain text representation of the message used by notification services without rich formatting.
+func (m Message) String() string {
+
+	var out strings.Builder
+
+	if m.Account != "" {
+		out.WriteString("[" + m.Account + "] ")
+	}
+
+	out.WriteString(m.Body)
+
+	if m.Error != "" {
+		out.WriteString(" Additional context below: \n " + m.Error)
+	}
+
+	return out.String()
+}
+
+// Text returns the plain text representation of the message with the error context, job name, and account on separate lines.
+func (m Message) Text() string {
+
+	var out strings.Builder
+
+	out.WriteString(m.Body)
+
+	if m.Error != "" {
+		out.WriteString("\n\nError context:\n" + m.Error)
+	}
+
+	if m.Job != "" {
+		out.WriteString("\n\nJob: " + m.Job)
+	}
+
+	if m.Account != "" {
+		if m.Job == "" {
+			out.WriteString("\n")
+		}
+		out.WriteString("\nAccount: " + m.Account)
+	}
+
+	return out.String()
+}
+
+// HTML returns the HTML representation of the message used by notification services that support HTML formatting.
+func (m Message) HTML() string {
+	return strings.Join(m.htmlLines(), "\n")
+}
+
+// htmlLines returns the lines of the HTML representation of the message.
+func (m Message) htmlLines() []string {
+
+	lines := []string{
+		"<b>" + html.EscapeString(m.Title()) + "</b>",
+		html.EscapeString(m.Body),
+	}
+
+	if m.Error != "" {
+		lines = append(lines, "<pre>"+html.EscapeString(m.Error)+"</pre>")
+	}
+
+	if m.Job != "" {
+		lines = append(lines, "<i>Job: "+html.EscapeString(m.Job)+"</i>")
+	}
+
+	if m.Account != "" {
+		lines = append(lines, "<i>Account: "+html.EscapeString(m.Account)+"</i>")
+	}
+
+	return lines
+}
+
+// Title returns a short title describing the event of the message.
+func (m Message) Title() string {
+
+	switch m.Event {
+	case "errors":
+		return "🚨 MyWhoop error"
+	case "success":
+		return "🎉 MyWhoop success"
+	case "alerts":
+		return "⚠️ MyWhoop alert"
+	default:
+		return "MyWhoop notification"
+	}
+}
+
+// truncate shortens the value to the maximum number of characters allowed by a notification service.
+func truncate(value string, max int) string {
+
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,121 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewMessage(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		data          string
+		event         string
+		expectedBody  string
+		expectedError string
+	}{
+		{
+			name:         "no error context",
+			data:         "Daily data collection complete.",
+			event:        "success",
+			expectedBody: "Daily data collection complete.",
+		},
+		{
+			name:          "additional context",
+			data:          "Failed to export data. Additional context below: \n access denied",
+			event:         "errors",
+			expectedBody:  "Failed to export data.",
+			expectedError: "access denied",
+		},
+		{
+			name:          "additional error message",
+			data:          "unable to stop jobs. Additional error message: \n scheduler stopped",
+			event:         "errors",
+			expectedBody:  "unable to stop jobs.",
+			expectedError: "scheduler stopped",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			msg := NewMessage([]byte(test.data), test.event)
+
+			if msg.Body != test.expectedBody {
+				t.Errorf("expected body: %q, got: %q", test.expectedBody, msg.Body)
+			}
+
+			if msg.Error != test.expectedError {
+				t.Errorf("expected error: %q, got: %q", test.expectedError, msg.Error)
+			}
+
+			if msg.Event != test.event {
+				t.Errorf("expected event: %s, got: %s", test.event, msg.Event)
+			}
+
+			if msg.Timestamp.IsZero() {
+				t.Errorf("expected a timestamp, got zero")
+			}
+		})
+	}
+}
+
+func TestMessageString(t *testing.T) {
+
+	msg := Message{
+		Account: "alice",
+		Body:    "Failed to export data.",
+		Error:   "access denied",
+	}
+
+	expected := "[alice] Failed to export data. Additional context below: \n access denied"
+	if msg.String() != expected {
+		t.Errorf("expected: %q, got: %q", expected, msg.String())
+	}
+}
+
+func TestMessageLogger(t *testing.T) {
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	runLogger := slog.New(slog.NewTextHandler(&buf, nil)).With("run_id", "1234")
+
+	if (Message{}).Logger() == nil {
+		t.Fatalf("Expected the default logger for a message without a logger")
+	}
+
+	slack := NewSlack()
+	slack.WebhookURL = ts.URL
+	slack.Events = "all"
+
+	err := slack.PublishMessage(&http.Client{}, Message{Event: "success", Body: "Data collection complete."}.WithLogger(runLogger))
+	if err != nil {
+		t.Fatalf("Error publishing the Slack notification: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "notification sent") || !strings.Contains(buf.String(), "run_id=1234") {
+		t.Errorf("Expected the record of the notification to be logged with the logger of the message but got: %s", buf.String())
+	}
+}
+
+func TestTruncate(t *testing.T) {
+
+	if got := truncate("short", 10); got != "short" {
+		t.Errorf("expected: short, got: %s", got)
+	}
+
+	got := truncate("a long message", 6)
+	if got != "a lon…" {
+		t.Errorf("expected: a lon…, got: %s", got)
+	}
+}
@@ -6,14 +6,21 @@ package notifications
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"io"
-	"log/slog"
+	"mime"
 	"net/http"
 	"os"
+	"strings"
 )
 
+// ntfyMaxAttachmentSize is the default attachment size limit of Ntfy servers.
+const ntfyMaxAttachmentSize = 15 * 1024 * 1024
+
 // New returns a new Ntfy struct with default values.
 func NewNtfy() *Ntfy {
 	return &Ntfy{
@@ -23,10 +30,16 @@ func NewNtfy() *Ntfy {
 		UserName:       "",
 		Password:       "",
 		Events:         "errors",
+		Priorities: NtfyPriorities{
+			Errors:  "high",
+			Success: "default",
+			Alerts:  "high",
+		},
 	}
 }
 
 // SetUp sets up the Ntfy service. Environment variables NOTIFICATION_NTFY_PASSWORD and NOTIFICATION_NTFY_AUTH_TOKEN are used to set the password and access token respectively.
+// A custom CA bundle is loaded if one is provided.
 func (n *Ntfy) SetUp() error {
 
 	pwd := os.Getenv("NOTIFICATION_NTFY_PASSWORD")
@@ -40,39 +53,83 @@ func (n *Ntfy) SetUp() error {
 		return err
 	}
 
-	// Set up the Ntfy service
+	if n.Priorities.Errors == "" {
+		n.Priorities.Errors = "high"
+	}
+
+	if n.Priorities.Success == "" {
+		n.Priorities.Success = "default"
+	}
+
+	if n.Priorities.Alerts == "" {
+		n.Priorities.Alerts = "high"
+	}
+
+	transport, err := ntfyTransport(n.CACertFile, n.InsecureSkipVerify)
+	if err != nil {
+		return err
+	}
+	n.transport = transport
+
+	if n.InsecureSkipVerify {
+		logger().Warn("TLS certificate verification of the Ntfy server is disabled")
+	}
+
 	return nil
 }
 
+// Accepts returns true if the events filter of the Ntfy notification accepts the event.
+func (n *Ntfy) Accepts(event string) bool {
+	return canSendMsg(n.Events, event)
+}
+
 // Send sends a notification using the Ntfy service with the provided data.
 func (n *Ntfy) Publish(client *http.Client, data []byte, event string) error {
 
+	if data == nil {
+		return errors.New("no data provided for external notification")
+	}
+
+	return n.PublishMessage(client, NewMessage(data, event))
+}
+
+// PublishMessage sends the structured message using the Ntfy service. The title, priority, tags, and click action are set from the message event.
+// The exported data is sent as a file attachment if attachExport is enabled and the message contains an attachment.
+func (n *Ntfy) PublishMessage(client *http.Client, msg Message) error {
+
 	if client == nil {
-		slog.Info("no http client specified for external notification")
+		msg.Logger().Info("no http client specified for external notification")
 		return errors.New("no http client specified for external notification")
 
 	}
 
-	if data == nil {
-		return errors.New("no data provided for external notification")
-	}
-
-	if event == "" {
+	if msg.Event == "" {
 		return errors.New("no event provided for external notification")
 
 	}
 
-	ok := canSendMsg(n.Events, event)
+	ok := n.Accepts(msg.Event)
 
 	if !ok {
-		slog.Info("event is not eligible for external notification. Notification suppresed.")
+		msg.Logger().Info("event is not eligible for external notification. Notification suppresed.")
 		return nil
 	}
 
-	// convert data to io.Reader
-	payload := bytes.NewReader(data)
+	text := msg.Text()
+	if n.Markdown {
+		text = ntfyMarkdown(msg)
+	}
+
+	method := http.MethodPost
+	var payload io.Reader = strings.NewReader(text)
 
-	req, err := http.NewRequestWithContext(context.Background(), "POST", n.ServerEndpoint+"/"+n.SubscriptionID, payload)
+	attachment := n.attachment(msg)
+	if attachment != nil {
+		method = http.MethodPut
+		payload = bytes.NewReader(attachment.Data)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), method, strings.TrimSuffix(n.ServerEndpoint, "/")+"/"+n.SubscriptionID, payload)
 	if err != nil {
 		return err
 	}
@@ -86,43 +143,186 @@ func (n *Ntfy) Publish(client *http.Client, data []byte, event string) error {
 		req.Header.Set("Authorization", "Basic "+encoded)
 	}
 
-	var emoji string
+	req.Header.Set("Title", encodeNtfyHeader(n.title(msg)))
+	req.Header.Set("Priority", n.priority(msg.Event))
 
-	if event == "errors" {
-		emoji = "rotating_light"
+	if tags := ntfyTags(msg.Event); tags != "" {
+		req.Header.Set("Tags", tags)
 	}
 
-	if event == "success" {
-		emoji = "tada"
+	click := n.Click
+	if click == "" {
+		click = msg.Link
+	}
+	if click != "" {
+		req.Header.Set("Click", click)
+	}
+
+	if n.Markdown {
+		req.Header.Set("Markdown", "yes")
 	}
 
-	req.Header.Set("Tags", emoji)
+	if attachment != nil {
+		// The request body contains the attachment, so the message is sent through the Message header.
+		req.Header.Set("Filename", encodeNtfyHeader(attachment.Name))
+		req.Header.Set("Message", encodeNtfyHeader(strings.ReplaceAll(text, "\n", `\n`)))
+	}
 
-	response, err := client.Do(req)
+	err = doRequest(msg.Logger(), n.httpClient(client), req)
 	if err != nil {
 		return err
 	}
 
-	if response != nil {
+	msg.Logger().Info("notification sent", "service", "ntfy")
+
+	return nil
+}
+
+// title returns the title of the notification. The configured title takes precedence over the title describing the event.
+func (n *Ntfy) title(msg Message) string {
+
+	if n.Title != "" {
+		return n.Title
+	}
+
+	// The emoji of the event is sent as a tag, so the title of the message is used without it.
+	switch msg.Event {
+	case "errors":
+		return "MyWhoop error"
+	case "success":
+		return "MyWhoop success"
+	case "alerts":
+		return "MyWhoop alert"
+	default:
+		return msg.Title()
+	}
+}
+
+// priority returns the configured Ntfy priority of the event.
+func (n *Ntfy) priority(event string) string {
+
+	if event == "errors" {
+		return n.Priorities.Errors
+	}
+
+	if event == "success" {
+		return n.Priorities.Success
+	}
+
+	if event == "alerts" {
+		return n.Priorities.Alerts
+	}
+
+	return "default"
+}
+
+// attachment returns the attachment of the message if attachExport is enabled. Attachments larger than the default attachment size limit of Ntfy servers are not sent.
+func (n *Ntfy) attachment(msg Message) *Attachment {
+
+	if !n.AttachExport || msg.Attachment == nil || len(msg.Attachment.Data) == 0 {
+		return nil
+	}
+
+	if len(msg.Attachment.Data) > ntfyMaxAttachmentSize {
+		msg.Logger().Warn("the exported data exceeds the Ntfy attachment size limit. The notification is sent without the attachment", "size", len(msg.Attachment.Data))
+		return nil
+	}
+
+	return msg.Attachment
+}
+
+// httpClient returns the HTTP client used to send the notification. The client uses the transport configured with the CA bundle and TLS settings of the Ntfy server if one is set.
+func (n *Ntfy) httpClient(client *http.Client) *http.Client {
+
+	if n.transport == nil {
+		return client
+	}
+
+	c := *client
+	c.Transport = n.transport
+
+	return &c
+}
+
+// ntfyTransport returns an HTTP transport that trusts the certificates of the CA bundle and optionally skips the TLS certificate verification.
+// Nil is returned if the default transport can be used.
+func ntfyTransport(caCertFile string, insecureSkipVerify bool) (*http.Transport, error) {
+
+	if caCertFile == "" && !insecureSkipVerify {
+		return nil, nil
+	}
 
-		defer response.Body.Close()
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecureSkipVerify, // #nosec G402 -- opt-in for self-signed Ntfy servers.
+	}
 
-		body, err := io.ReadAll(response.Body)
+	if caCertFile != "" {
+		pem, err := os.ReadFile(caCertFile)
 		if err != nil {
-			slog.Info("unable to read response body", "error", err)
-			return err
+			return nil, fmt.Errorf("unable to read the Ntfy CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
 		}
 
-		if response.StatusCode != http.StatusOK {
-			slog.Info("error sending notification", "status", response.Status, "body", string(body))
-			return errors.New("error sending notification")
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM encoded certificates found in the Ntfy CA bundle %s", caCertFile)
 		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
 
-		slog.Info("notification sent", "status", response.Status)
+// ntfyTags returns the Ntfy tags of the event. Ntfy displays tags matching an emoji short code as the emoji.
+func ntfyTags(event string) string {
 
+	switch event {
+	case "errors":
+		return "rotating_light"
+	case "success":
+		return "tada"
+	case "alerts":
+		return "warning"
+	default:
+		return ""
 	}
+}
 
-	return nil
+// ntfyMarkdown returns the Markdown representation of the message.
+func ntfyMarkdown(msg Message) string {
+
+	var out strings.Builder
+
+	out.WriteString(msg.Body)
+
+	if msg.Error != "" {
+		out.WriteString("\n\n```\n" + msg.Error + "\n```")
+	}
+
+	if msg.Job != "" {
+		out.WriteString("\n\n**Job:** `" + msg.Job + "`")
+	}
+
+	if msg.Account != "" {
+		if msg.Job == "" {
+			out.WriteString("\n")
+		}
+		out.WriteString("\n**Account:** " + msg.Account)
+	}
+
+	return out.String()
+}
+
+// encodeNtfyHeader encodes header values that contain non-ASCII characters, such as emoji, as RFC 2047 encoded words. Ntfy decodes the encoded words.
+func encodeNtfyHeader(value string) string {
+	return mime.BEncoding.Encode("UTF-8", value)
 }
 
 // checkRequiredParams checks if the required parameters are provided. If a required parameter is not provided, it returns an error.
@@ -169,6 +369,10 @@ func canSendMsg(configured string, event string) bool {
 		return true
 	}
 
+	if configured == "alerts" && event == "alerts" {
+		return true
+	}
+
 	return false
 
 }
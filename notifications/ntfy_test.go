@@ -4,10 +4,14 @@
 package notifications
 
 import (
+	"encoding/pem"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -147,6 +151,9 @@ func TestCanSendMsg(t *testing.T) {
 		{"errors", "errors", true},
 		{"errors", "success", false},
 		{"success", "success", true},
+		{"alerts", "alerts", true},
+		{"all", "alerts", true},
+		{"errors", "alerts", false},
 	}
 
 	for _, tc := range test {
@@ -161,6 +168,16 @@ func TestCanSendMsg(t *testing.T) {
 func clearEnvVariables() {
 	os.Unsetenv("NOTIFICATION_NTFY_PASSWORD")
 	os.Unsetenv("NOTIFICATION_NTFY_AUTH_TOKEN")
+	os.Unsetenv("NOTIFICATION_SLACK_WEBHOOK_URL")
+	os.Unsetenv("NOTIFICATION_DISCORD_WEBHOOK_URL")
+	os.Unsetenv("NOTIFICATION_EMAIL_USERNAME")
+	os.Unsetenv("NOTIFICATION_EMAIL_PASSWORD")
+	os.Unsetenv("NOTIFICATION_WEBHOOK_SECRET")
+	os.Unsetenv("NOTIFICATION_GOTIFY_TOKEN")
+	os.Unsetenv("NOTIFICATION_PUSHOVER_TOKEN")
+	os.Unsetenv("NOTIFICATION_PUSHOVER_USER_KEY")
+	os.Unsetenv("NOTIFICATION_TELEGRAM_BOT_TOKEN")
+	os.Unsetenv("NOTIFICATION_MATRIX_ACCESS_TOKEN")
 }
 
 func TestRequiredParams(t *testing.T) {
@@ -248,3 +265,276 @@ func TestRequiredParams(t *testing.T) {
 	}
 
 }
+
+func TestNtfyPublishMessage(t *testing.T) {
+
+	clearEnvVariables()
+
+	tests := []struct {
+		description      string
+		configure        func(n *Ntfy)
+		msg              Message
+		expectedMethod   string
+		expectedHeaders  map[string]string
+		expectedBody     string
+		expectedRequests int
+	}{
+		{
+			description: "Error notification",
+			msg:         Message{Event: "errors", Body: "Failed to export data.", Error: "disk full", Job: "job"},
+			configure:   func(n *Ntfy) {},
+			expectedHeaders: map[string]string{
+				"Title":    "MyWhoop error",
+				"Priority": "high",
+				"Tags":     "rotating_light",
+			},
+			expectedMethod:   http.MethodPost,
+			expectedBody:     "Failed to export data.\n\nError context:\ndisk full\n\nJob: job",
+			expectedRequests: 1,
+		},
+		{
+			description: "Success notification with title, priority, click, and markdown",
+			msg:         Message{Event: "success", Body: "Daily data collection complete.", Job: "job", Link: "https://example.com/user.json"},
+			configure: func(n *Ntfy) {
+				n.Events = "all"
+				n.Title = "Whoop"
+				n.Priorities.Success = "low"
+				n.Markdown = true
+			},
+			expectedHeaders: map[string]string{
+				"Title":    "Whoop",
+				"Priority": "low",
+				"Tags":     "tada",
+				"Click":    "https://example.com/user.json",
+				"Markdown": "yes",
+			},
+			expectedMethod:   http.MethodPost,
+			expectedBody:     "Daily data collection complete.\n\n**Job:** `job`",
+			expectedRequests: 1,
+		},
+		{
+			description: "Configured click URL takes precedence over the message link",
+			msg:         Message{Event: "success", Body: "done", Link: "https://example.com/user.json"},
+			configure: func(n *Ntfy) {
+				n.Events = "success"
+				n.Click = "https://grafana.example.com"
+			},
+			expectedHeaders: map[string]string{
+				"Click": "https://grafana.example.com",
+			},
+			expectedMethod:   http.MethodPost,
+			expectedBody:     "done",
+			expectedRequests: 1,
+		},
+		{
+			description: "Exported data attachment",
+			msg:         Message{Event: "success", Body: "done", Attachment: &Attachment{Name: "user.json", Data: []byte(`{"id":1}`)}},
+			configure: func(n *Ntfy) {
+				n.Events = "all"
+				n.AttachExport = true
+			},
+			expectedHeaders: map[string]string{
+				"Filename": "user.json",
+				"Message":  "done",
+			},
+			expectedMethod:   http.MethodPut,
+			expectedBody:     `{"id":1}`,
+			expectedRequests: 1,
+		},
+		{
+			description: "Attachment is ignored if attachExport is disabled",
+			msg:         Message{Event: "success", Body: "done", Attachment: &Attachment{Name: "user.json", Data: []byte(`{"id":1}`)}},
+			configure: func(n *Ntfy) {
+				n.Events = "all"
+			},
+			expectedHeaders:  map[string]string{},
+			expectedMethod:   http.MethodPost,
+			expectedBody:     "done",
+			expectedRequests: 1,
+		},
+		{
+			description:      "Suppressed event",
+			msg:              Message{Event: "success", Body: "done"},
+			configure:        func(n *Ntfy) {},
+			expectedRequests: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+
+			var (
+				requests int
+				method   string
+				headers  http.Header
+				body     []byte
+			)
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				method = r.Method
+				headers = r.Header
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer ts.Close()
+
+			ntfy := NewNtfy()
+			ntfy.ServerEndpoint = ts.URL
+			ntfy.SubscriptionID = "mywhoop"
+			ntfy.AccessToken = "1234"
+			tc.configure(ntfy)
+
+			err := ntfy.PublishMessage(ts.Client(), tc.msg)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if requests != tc.expectedRequests {
+				t.Fatalf("Expected %d requests, got %d", tc.expectedRequests, requests)
+			}
+
+			if tc.expectedRequests == 0 {
+				return
+			}
+
+			if method != tc.expectedMethod {
+				t.Errorf("Expected method %s, got %s", tc.expectedMethod, method)
+			}
+
+			for key, value := range tc.expectedHeaders {
+				if headers.Get(key) != value {
+					t.Errorf("Expected header %s to be %q, got %q", key, value, headers.Get(key))
+				}
+			}
+
+			if headers.Get("Authorization") != "Bearer 1234" {
+				t.Errorf("Expected the access token to be sent, got %q", headers.Get("Authorization"))
+			}
+
+			if string(body) != tc.expectedBody {
+				t.Errorf("Expected body %q, got %q", tc.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestNtfyPriority(t *testing.T) {
+
+	ntfy := NewNtfy()
+	ntfy.Priorities.Errors = "urgent"
+
+	tests := []struct {
+		event    string
+		expected string
+	}{
+		{"errors", "urgent"},
+		{"success", "default"},
+		{"alerts", "high"},
+		{":tada", "default"},
+	}
+
+	for _, tc := range tests {
+		result := ntfy.priority(tc.event)
+		if result != tc.expected {
+			t.Errorf("Expected %v, got %v", tc.expected, result)
+		}
+	}
+}
+
+func TestEncodeNtfyHeader(t *testing.T) {
+
+	if got := encodeNtfyHeader("MyWhoop error"); got != "MyWhoop error" {
+		t.Errorf("Expected ASCII header values to be unchanged, got %q", got)
+	}
+
+	encoded := encodeNtfyHeader("🚨 MyWhoop error")
+	decoded, err := new(mime.WordDecoder).DecodeHeader(encoded)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if decoded != "🚨 MyWhoop error" {
+		t.Errorf("Expected %q, got %q", "🚨 MyWhoop error", decoded)
+	}
+}
+
+func TestNtfyTLS(t *testing.T) {
+
+	clearEnvVariables()
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0600)
+	if err != nil {
+		t.Fatalf("unable to write the CA bundle: %v", err)
+	}
+
+	invalidFile := filepath.Join(t.TempDir(), "invalid.pem")
+	err = os.WriteFile(invalidFile, []byte("not a certificate"), 0600)
+	if err != nil {
+		t.Fatalf("unable to write the CA bundle: %v", err)
+	}
+
+	tests := []struct {
+		description        string
+		caCertFile         string
+		insecureSkipVerify bool
+		setUpError         bool
+		publishError       bool
+	}{
+		{
+			description:  "Self-signed server without a CA bundle",
+			publishError: true,
+		},
+		{
+			description: "Self-signed server with a CA bundle",
+			caCertFile:  caFile,
+		},
+		{
+			description:        "Self-signed server with TLS verification disabled",
+			insecureSkipVerify: true,
+		},
+		{
+			description: "Missing CA bundle",
+			caCertFile:  filepath.Join(t.TempDir(), "missing.pem"),
+			setUpError:  true,
+		},
+		{
+			description: "CA bundle without certificates",
+			caCertFile:  invalidFile,
+			setUpError:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+
+			t.Setenv("NOTIFICATION_NTFY_AUTH_TOKEN", "1234")
+
+			ntfy := NewNtfy()
+			ntfy.ServerEndpoint = ts.URL
+			ntfy.SubscriptionID = "mywhoop"
+			ntfy.CACertFile = tc.caCertFile
+			ntfy.InsecureSkipVerify = tc.insecureSkipVerify
+
+			err := ntfy.SetUp()
+			if (err != nil) != tc.setUpError {
+				t.Fatalf("Expected setup error %v, got %v", tc.setUpError, err)
+			}
+
+			if tc.setUpError {
+				return
+			}
+
+			err = ntfy.PublishMessage(&http.Client{}, Message{Event: "errors", Body: "test"})
+			if (err != nil) != tc.publishError {
+				t.Errorf("Expected publish error %v, got %v", tc.publishError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// pushoverEndpoint is the endpoint of the Pushover API.
+	pushoverEndpoint = "https://api.pushover.net"
+	// pushoverPriorityErrors is the Pushover priority of error notifications. High priority notifications bypass the quiet hours of the user.
+	pushoverPriorityErrors = 1
+	// pushoverPrioritySuccess is the Pushover priority of success notifications. Low priority notifications do not generate a sound or a vibration.
+	pushoverPrioritySuccess = -1
+	// pushoverMessageLimit is the maximum number of characters of a Pushover message.
+	pushoverMessageLimit = 1024
+)
+
+// NewPushover returns a new Pushover struct with default values.
+func NewPushover() *Pushover {
+	return &Pushover{
+		Token:    "",
+		UserKey:  "",
+		Events:   "errors",
+		endpoint: pushoverEndpoint,
+	}
+}
+
+// SetUp sets up the Pushover service. The environment variables NOTIFICATION_PUSHOVER_TOKEN and NOTIFICATION_PUSHOVER_USER_KEY are used to set the application token and the user key respectively.
+func (p *Pushover) SetUp() error {
+
+	token := os.Getenv("NOTIFICATION_PUSHOVER_TOKEN")
+	if token != "" {
+		p.Token = token
+	}
+
+	userKey := os.Getenv("NOTIFICATION_PUSHOVER_USER_KEY")
+	if userKey != "" {
+		p.UserKey = userKey
+	}
+
+	if p.Token == "" || p.UserKey == "" {
+		return errors.New("no Pushover credentials provided. Provide the application token and the user key through the NOTIFICATION_PUSHOVER_TOKEN and NOTIFICATION_PUSHOVER_USER_KEY environment variables")
+	}
+
+	if p.Events == "" {
+		p.Events = "errors"
+	}
+
+	if p.endpoint == "" {
+		p.endpoint = pushoverEndpoint
+	}
+
+	return nil
+}
+
+// Accepts returns true if the events filter of the Pushover notification accepts the event.
+func (p *Pushover) Accepts(event string) bool {
+	return canSendMsg(p.Events, event)
+}
+
+// Publish sends a notification using the Pushover service with the provided data.
+func (p *Pushover) Publish(client *http.Client, data []byte, event string) error {
+
+	if data == nil {
+		return errors.New("no data provided for external notification")
+	}
+
+	return p.PublishMessage(client, NewMessage(data, event))
+}
+
+// PublishMessage sends the structured message using the Pushover service. The event is mapped to the Pushover priority.
+func (p *Pushover) PublishMessage(client *http.Client, msg Message) error {
+
+	if client == nil {
+		msg.Logger().Info("no http client specified for external notification")
+		return errors.New("no http client specified for external notification")
+	}
+
+	if msg.Event == "" {
+		return errors.New("no event provided for external notification")
+	}
+
+	if !p.Accepts(msg.Event) {
+		msg.Logger().Info("event is not eligible for external notification. Notification suppresed.")
+		return nil
+	}
+
+	form := url.Values{}
+	form.Set("token", p.Token)
+	form.Set("user", p.UserKey)
+	form.Set("title", msg.Title())
+	form.Set("message", truncate(msg.Text(), pushoverMessageLimit))
+	form.Set("priority", strconv.Itoa(pushoverPriority(msg.Event)))
+	if p.Device != "" {
+		form.Set("device", p.Device)
+	}
+	if !msg.Timestamp.IsZero() {
+		form.Set("timestamp", formatUnix(msg.Timestamp))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.endpoint+"/1/messages.json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	err = doRequest(msg.Logger(), client, req)
+	if err != nil {
+		return err
+	}
+
+	msg.Logger().Info("notification sent", "service", "pushover")
+
+	return nil
+}
+
+// pushoverPriority returns the Pushover priority of the event.
+func pushoverPriority(event string) int {
+
+	if event == "errors" || event == "alerts" {
+		return pushoverPriorityErrors
+	}
+
+	return pushoverPrioritySuccess
+}
@@ -0,0 +1,124 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPushoverSetUp(t *testing.T) {
+
+	clearEnvVariables()
+
+	pushover := NewPushover()
+	err := pushover.SetUp()
+	if err == nil {
+		t.Errorf("Expected error due to missing credentials but got nil")
+	}
+
+	os.Setenv("NOTIFICATION_PUSHOVER_TOKEN", "azGDORePK8gMaC0QOYAMyEEuzJnyUi")
+	err = pushover.SetUp()
+	if err == nil {
+		t.Errorf("Expected error due to missing user key but got nil")
+	}
+
+	os.Setenv("NOTIFICATION_PUSHOVER_USER_KEY", "uQiRzpo4DXghDmr9QzzfQu27cmVRsG")
+	err = pushover.SetUp()
+	if err != nil {
+		t.Errorf("Error setting up Pushover service: %v", err)
+	}
+
+	clearEnvVariables()
+}
+
+func TestPushoverPublish(t *testing.T) {
+
+	clearEnvVariables()
+
+	tests := []struct {
+		name             string
+		event            string
+		expectedPriority int
+	}{
+		{
+			name:             "errors",
+			event:            "errors",
+			expectedPriority: pushoverPriorityErrors,
+		},
+		{
+			name:             "success",
+			event:            "success",
+			expectedPriority: pushoverPrioritySuccess,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var form url.Values
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/1/messages.json" {
+					t.Errorf("Expected the /1/messages.json path, got %s", r.URL.Path)
+				}
+				err := r.ParseForm()
+				if err != nil {
+					t.Errorf("Unable to parse the Pushover form: %v", err)
+				}
+				form = r.PostForm
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer ts.Close()
+
+			pushover := NewPushover()
+			pushover.Token = "token"
+			pushover.UserKey = "user"
+			pushover.Device = "phone"
+			pushover.Events = "all"
+			pushover.endpoint = ts.URL
+
+			err := pushover.Publish(&http.Client{}, []byte("Failed to export data. Additional context below: \n access denied"), test.event)
+			if err != nil {
+				t.Fatalf("Error publishing the Pushover notification: %v", err)
+			}
+
+			if form.Get("priority") != strconv.Itoa(test.expectedPriority) {
+				t.Errorf("Expected priority %d, got %s", test.expectedPriority, form.Get("priority"))
+			}
+
+			if form.Get("token") != "token" || form.Get("user") != "user" || form.Get("device") != "phone" {
+				t.Errorf("Expected the credentials and device, got %v", form)
+			}
+
+			if !strings.Contains(form.Get("message"), "access denied") {
+				t.Errorf("Expected the error context in the message, got %q", form.Get("message"))
+			}
+		})
+	}
+}
+
+func TestPushoverPublishRejected(t *testing.T) {
+
+	clearEnvVariables()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer ts.Close()
+
+	pushover := NewPushover()
+	pushover.Token = "invalid"
+	pushover.UserKey = "user"
+	pushover.endpoint = ts.URL
+
+	err := pushover.Publish(&http.Client{}, []byte("test"), "errors")
+	if err == nil {
+		t.Errorf("Expected an error for the rejected message but got nil")
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"errors"
+	"net/http"
+	"os"
+)
+
+const (
+	// slackTextLimit is the maximum number of characters of a Slack section block text.
+	slackTextLimit = 3000
+)
+
+// NewSlack returns a new Slack struct with default values.
+func NewSlack() *Slack {
+	return &Slack{
+		WebhookURL: "",
+		Events:     "errors",
+	}
+}
+
+// SetUp sets up the Slack service. The environment variable NOTIFICATION_SLACK_WEBHOOK_URL is used to set the incoming webhook URL if no webhook URL is configured.
+func (s *Slack) SetUp() error {
+
+	if s.WebhookURL == "" {
+		s.WebhookURL = os.Getenv("NOTIFICATION_SLACK_WEBHOOK_URL")
+	}
+
+	if s.WebhookURL == "" {
+		return errors.New("no Slack webhook URL provided. Provide the incoming webhook URL through the webhookURL field or the NOTIFICATION_SLACK_WEBHOOK_URL environment variable")
+	}
+
+	if s.Events == "" {
+		s.Events = "errors"
+	}
+
+	return nil
+}
+
+// Accepts returns true if the events filter of the Slack notification accepts the event.
+func (s *Slack) Accepts(event string) bool {
+	return canSendMsg(s.Events, event)
+}
+
+// Publish sends a notification using the Slack incoming webhook with the provided data.
+func (s *Slack) Publish(client *http.Client, data []byte, event string) error {
+
+	if data == nil {
+		return errors.New("no data provided for external notification")
+	}
+
+	return s.PublishMessage(client, NewMessage(data, event))
+}
+
+// PublishMessage sends the structured message using the Slack incoming webhook. The message is formatted with Slack blocks.
+func (s *Slack) PublishMessage(client *http.Client, msg Message) error {
+
+	if client == nil {
+		msg.Logger().Info("no http client specified for external notification")
+		return errors.New("no http client specified for external notification")
+	}
+
+	if msg.Event == "" {
+		return errors.New("no event provided for external notification")
+	}
+
+	if !s.Accepts(msg.Event) {
+		msg.Logger().Info("event is not eligible for external notification. Notification suppresed.")
+		return nil
+	}
+
+	err := postJSON(msg.Logger(), client, s.WebhookURL, slackPayload(msg))
+	if err != nil {
+		return err
+	}
+
+	msg.Logger().Info("notification sent", "service", "slack")
+
+	return nil
+}
+
+// slackPayload returns the Slack incoming webhook payload of the message.
+func slackPayload(msg Message) map[string]interface{} {
+
+	text := func(value string) map[string]interface{} {
+		return map[string]interface{}{"type": "mrkdwn", "text": value}
+	}
+
+	fields := []interface{}{
+		text("*Event*\n" + msg.Event),
+	}
+
+	if msg.Job != "" {
+		fields = append(fields, text("*Job*\n"+msg.Job))
+	}
+
+	if msg.Account != "" {
+		fields = append(fields, text("*Account*\n"+msg.Account))
+	}
+
+	blocks := []interface{}{
+		map[string]interface{}{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": msg.Title()},
+		},
+		map[string]interface{}{
+			"type": "section",
+			"text": text(truncate(msg.Body, slackTextLimit)),
+		},
+		map[string]interface{}{
+			"type":   "section",
+			"fields": fields,
+		},
+	}
+
+	if msg.Error != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": text("*Error context*\n```" + truncate(msg.Error, slackTextLimit-30) + "```"),
+		})
+	}
+
+	if !msg.Timestamp.IsZero() {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "context",
+			"elements": []interface{}{
+				text("<!date^" + formatUnix(msg.Timestamp) + "^{date_short_pretty} at {time}|" + msg.Timestamp.Format("2006-01-02 15:04:05 MST") + ">"),
+			},
+		})
+	}
+
+	return map[string]interface{}{
+		"text":   msg.Title() + ": " + msg.String(),
+		"blocks": blocks,
+	}
+}
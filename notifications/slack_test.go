@@ -0,0 +1,160 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSlackSetUp(t *testing.T) {
+
+	clearEnvVariables()
+
+	slack := NewSlack()
+	err := slack.SetUp()
+	if err == nil {
+		t.Errorf("Expected error due to missing webhook URL but got nil")
+	}
+
+	os.Setenv("NOTIFICATION_SLACK_WEBHOOK_URL", "https://hooks.slack.com/services/T000/B000/XXXX")
+
+	err = slack.SetUp()
+	if err != nil {
+		t.Errorf("Error setting up Slack service: %v", err)
+	}
+
+	if slack.WebhookURL != "https://hooks.slack.com/services/T000/B000/XXXX" {
+		t.Errorf("Expected the webhook URL from the environment, got %v", slack.WebhookURL)
+	}
+
+	// A configured webhook URL takes precedence over the environment variable
+	slack = NewSlack()
+	slack.WebhookURL = "https://hooks.slack.com/services/T000/B000/YYYY"
+	err = slack.SetUp()
+	if err != nil {
+		t.Errorf("Error setting up Slack service: %v", err)
+	}
+
+	if slack.WebhookURL != "https://hooks.slack.com/services/T000/B000/YYYY" {
+		t.Errorf("Expected the configured webhook URL, got %v", slack.WebhookURL)
+	}
+
+	clearEnvVariables()
+}
+
+func TestSlackPublish(t *testing.T) {
+
+	clearEnvVariables()
+
+	var payload map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Expected JSON content type, got %v", r.Header.Get("Content-Type"))
+		}
+		err := json.NewDecoder(r.Body).Decode(&payload)
+		if err != nil {
+			t.Errorf("Unable to decode the Slack payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	slack := NewSlack()
+	slack.WebhookURL = ts.URL
+	slack.Events = "all"
+
+	err := slack.PublishMessage(&http.Client{}, Message{
+		Event:   "errors",
+		Job:     "mywhoop_data_collection_job",
+		Account: "alice",
+		Body:    "Failed to export data.",
+		Error:   "access denied",
+	})
+	if err != nil {
+		t.Fatalf("Error publishing the Slack notification: %v", err)
+	}
+
+	raw, _ := json.Marshal(payload)
+	for _, expected := range []string{"header", "mywhoop_data_collection_job", "alice", "access denied", "Failed to export data."} {
+		if !strings.Contains(string(raw), expected) {
+			t.Errorf("Expected the Slack payload to contain %q, got %s", expected, raw)
+		}
+	}
+}
+
+func TestSlackPublishErrors(t *testing.T) {
+
+	clearEnvVariables()
+
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name          string
+		client        *http.Client
+		data          []byte
+		event         string
+		events        string
+		expectedError bool
+		expectedCalls int
+	}{
+		{
+			name:          "no client",
+			data:          []byte("test"),
+			event:         "errors",
+			expectedError: true,
+		},
+		{
+			name:          "no data",
+			client:        &http.Client{},
+			event:         "errors",
+			expectedError: true,
+		},
+		{
+			name:          "event suppressed",
+			client:        &http.Client{},
+			data:          []byte("test"),
+			event:         "success",
+			events:        "errors",
+			expectedError: false,
+		},
+		{
+			name:          "webhook rejects the message",
+			client:        &http.Client{},
+			data:          []byte("test"),
+			event:         "errors",
+			events:        "errors",
+			expectedError: true,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests = 0
+
+			slack := NewSlack()
+			slack.WebhookURL = ts.URL
+			slack.Events = test.events
+
+			err := slack.Publish(test.client, test.data, test.event)
+			if (err != nil) != test.expectedError {
+				t.Errorf("Expected error: %v, got: %v", test.expectedError, err)
+			}
+
+			if requests != test.expectedCalls {
+				t.Errorf("Expected %d requests, got %d", test.expectedCalls, requests)
+			}
+		})
+	}
+}
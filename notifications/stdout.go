@@ -3,17 +3,124 @@
 
 package notifications
 
-import "net/http"
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+)
 
-// Stdout is a struct that contains the configuration for the sending messages to stdout.
+// NewStdout returns a new Stdout struct with default values.
 func NewStdout() *Stdout {
-	return &Stdout{}
+	return &Stdout{
+		Format: "text",
+		Events: "all",
+		writer: os.Stdout,
+	}
 }
 
+// SetUp sets up the stdout notification. The default format and events are used if none are provided.
 func (s *Stdout) SetUp() error {
+
+	if s.Format == "" {
+		s.Format = "text"
+	}
+
+	if s.Format != "text" && s.Format != "json" {
+		return errors.New("invalid stdout notification format. Supported formats are text and json")
+	}
+
+	if s.Events == "" {
+		s.Events = "all"
+	}
+
+	if s.writer == nil {
+		s.writer = os.Stdout
+	}
+
 	return nil
 }
 
+// Accepts returns true if the events filter of the stdout notification accepts the event.
+func (s *Stdout) Accepts(event string) bool {
+	return canSendMsg(s.Events, event)
+}
+
+// Publish writes the notification to stdout. No line is written if no data is provided.
 func (s *Stdout) Publish(client *http.Client, data []byte, event string) error {
-	return nil
+
+	if data == nil {
+		return nil
+	}
+
+	return s.PublishMessage(client, NewMessage(data, event))
+}
+
+// PublishMessage writes the structured message to stdout as a single line containing the event, timestamp and message.
+func (s *Stdout) PublishMessage(client *http.Client, msg Message) error {
+
+	if !s.Accepts(msg.Event) {
+		return nil
+	}
+
+	timestamp := msg.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	record := slog.NewRecord(timestamp, stdoutLevel(msg.Event), msg.Body, 0)
+	record.AddAttrs(slog.String("event", msg.Event))
+
+	optional := []slog.Attr{
+		slog.String("job", msg.Job),
+		slog.String("account", msg.Account),
+		slog.String("error", msg.Error),
+		slog.String("link", msg.Link),
+	}
+	for _, attr := range optional {
+		if attr.Value.String() != "" {
+			record.AddAttrs(attr)
+		}
+	}
+
+	return s.handler().Handle(context.Background(), record)
+}
+
+// handler returns the slog handler writing the notification lines in the configured format.
+func (s *Stdout) handler() slog.Handler {
+
+	writer := s.writer
+	if writer == nil {
+		writer = os.Stdout
+	}
+
+	opts := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.String(slog.TimeKey, a.Value.Time().Format(time.RFC3339))
+			}
+			return a
+		},
+	}
+
+	if s.Format == "json" {
+		return slog.NewJSONHandler(writer, opts)
+	}
+
+	return slog.NewTextHandler(writer, opts)
+}
+
+// stdoutLevel returns the log level of the event. Log collectors use the level to alert on errors.
+func stdoutLevel(event string) slog.Level {
+
+	switch event {
+	case "errors":
+		return slog.LevelError
+	case "alerts":
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
 }
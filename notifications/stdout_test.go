@@ -4,13 +4,23 @@
 package notifications
 
 import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewStdout(t *testing.T) {
-	expect := &Stdout{}
+	expect := &Stdout{
+		Format: "text",
+		Events: "all",
+		writer: os.Stdout,
+	}
 
-	if got := NewStdout(); got != expect {
+	if got := NewStdout(); !reflect.DeepEqual(got, expect) {
 		t.Errorf("NewStdout() = %v, want %v", got, expect)
 	}
 }
@@ -21,6 +31,20 @@ func TestStdout_SetUp(t *testing.T) {
 	if err := s.SetUp(); err != nil {
 		t.Errorf("SetUp() error = %v, want nil", err)
 	}
+
+	s = &Stdout{}
+	if err := s.SetUp(); err != nil {
+		t.Errorf("SetUp() error = %v, want nil", err)
+	}
+
+	if s.Format != "text" || s.Events != "all" || s.writer == nil {
+		t.Errorf("SetUp() did not apply the defaults, got %+v", s)
+	}
+
+	s = &Stdout{Format: "xml"}
+	if err := s.SetUp(); err == nil {
+		t.Error("SetUp() error = nil, want an error for an invalid format")
+	}
 }
 
 func TestStdout_Publish(t *testing.T) {
@@ -30,3 +54,83 @@ func TestStdout_Publish(t *testing.T) {
 		t.Errorf("Publish() error = %v, want nil", err)
 	}
 }
+
+func TestStdout_PublishMessage(t *testing.T) {
+
+	timestamp := time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		format   string
+		events   string
+		msg      Message
+		expected string
+	}{
+		{
+			name:     "text success",
+			format:   "text",
+			events:   "all",
+			msg:      Message{Event: "success", Job: "mywhoop_data_collection_job", Body: "Daily data collection complete.", Timestamp: timestamp},
+			expected: "time=2024-06-01T13:00:00Z level=INFO msg=\"Daily data collection complete.\" event=success job=mywhoop_data_collection_job\n",
+		},
+		{
+			name:     "text error",
+			format:   "text",
+			events:   "errors",
+			msg:      Message{Event: "errors", Account: "alice", Body: "Error running the server job.", Error: "unauthorized", Timestamp: timestamp},
+			expected: "time=2024-06-01T13:00:00Z level=ERROR msg=\"Error running the server job.\" event=errors account=alice error=unauthorized\n",
+		},
+		{
+			name:     "filtered event",
+			format:   "text",
+			events:   "errors",
+			msg:      Message{Event: "success", Body: "Daily data collection complete.", Timestamp: timestamp},
+			expected: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			s := &Stdout{Format: tc.format, Events: tc.events, writer: &out}
+
+			err := s.PublishMessage(nil, tc.msg)
+			if err != nil {
+				t.Fatalf("PublishMessage() error = %v", err)
+			}
+
+			if out.String() != tc.expected {
+				t.Errorf("PublishMessage() wrote %q, want %q", out.String(), tc.expected)
+			}
+		})
+	}
+}
+
+func TestStdout_PublishJSON(t *testing.T) {
+
+	var out bytes.Buffer
+	s := &Stdout{Format: "json", Events: "all", writer: &out}
+
+	err := s.Publish(nil, []byte("Recovery is below the threshold."), "alerts")
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if strings.Count(out.String(), "\n") != 1 {
+		t.Fatalf("expected a single line, got %q", out.String())
+	}
+
+	var line map[string]string
+	err = json.Unmarshal(out.Bytes(), &line)
+	if err != nil {
+		t.Fatalf("unable to decode the line: %v", err)
+	}
+
+	if line["level"] != "WARN" || line["event"] != "alerts" || line["msg"] != "Recovery is below the threshold." || line["time"] == "" {
+		t.Errorf("unexpected line %v", line)
+	}
+
+	if _, ok := line["job"]; ok {
+		t.Errorf("expected empty fields to be omitted, got %v", line)
+	}
+}
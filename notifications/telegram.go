@@ -0,0 +1,109 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"errors"
+	"net/http"
+	"os"
+)
+
+const (
+	// telegramEndpoint is the endpoint of the Telegram bot API.
+	telegramEndpoint = "https://api.telegram.org"
+	// telegramMessageLimit is the maximum number of characters of a Telegram message.
+	telegramMessageLimit = 4096
+)
+
+// NewTelegram returns a new Telegram struct with default values.
+func NewTelegram() *Telegram {
+	return &Telegram{
+		ChatID:   "",
+		BotToken: "",
+		Events:   "errors",
+		endpoint: telegramEndpoint,
+	}
+}
+
+// SetUp sets up the Telegram service. The environment variable NOTIFICATION_TELEGRAM_BOT_TOKEN is used to set the bot token.
+func (t *Telegram) SetUp() error {
+
+	token := os.Getenv("NOTIFICATION_TELEGRAM_BOT_TOKEN")
+	if token != "" {
+		t.BotToken = token
+	}
+
+	if t.ChatID == "" {
+		return errors.New("no Telegram chat ID provided")
+	}
+
+	if t.BotToken == "" {
+		return errors.New("no Telegram bot token provided. Provide the token through the NOTIFICATION_TELEGRAM_BOT_TOKEN environment variable")
+	}
+
+	if t.Events == "" {
+		t.Events = "errors"
+	}
+
+	if t.endpoint == "" {
+		t.endpoint = telegramEndpoint
+	}
+
+	return nil
+}
+
+// Accepts returns true if the events filter of the Telegram notification accepts the event.
+func (t *Telegram) Accepts(event string) bool {
+	return canSendMsg(t.Events, event)
+}
+
+// Publish sends a notification using the Telegram bot with the provided data.
+func (t *Telegram) Publish(client *http.Client, data []byte, event string) error {
+
+	if data == nil {
+		return errors.New("no data provided for external notification")
+	}
+
+	return t.PublishMessage(client, NewMessage(data, event))
+}
+
+// PublishMessage sends the structured message using the Telegram bot. Success notifications are sent silently.
+func (t *Telegram) PublishMessage(client *http.Client, msg Message) error {
+
+	if client == nil {
+		msg.Logger().Info("no http client specified for external notification")
+		return errors.New("no http client specified for external notification")
+	}
+
+	if msg.Event == "" {
+		return errors.New("no event provided for external notification")
+	}
+
+	if !t.Accepts(msg.Event) {
+		msg.Logger().Info("event is not eligible for external notification. Notification suppresed.")
+		return nil
+	}
+
+	payload := map[string]interface{}{
+		"chat_id":              t.ChatID,
+		"text":                 msg.HTML(),
+		"parse_mode":           "HTML",
+		"disable_notification": msg.Event == "success",
+	}
+
+	if len([]rune(msg.HTML())) > telegramMessageLimit {
+		// Truncating the HTML could break the markup, so the plain text is sent instead
+		payload["text"] = truncate(msg.Title()+"\n"+msg.Text(), telegramMessageLimit)
+		delete(payload, "parse_mode")
+	}
+
+	err := postJSON(msg.Logger(), client, t.endpoint+"/bot"+t.BotToken+"/sendMessage", payload)
+	if err != nil {
+		return err
+	}
+
+	msg.Logger().Info("notification sent", "service", "telegram")
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTelegramSetUp(t *testing.T) {
+
+	clearEnvVariables()
+
+	telegram := NewTelegram()
+	telegram.ChatID = "-1001234567890"
+
+	err := telegram.SetUp()
+	if err == nil {
+		t.Errorf("Expected error due to missing bot token but got nil")
+	}
+
+	os.Setenv("NOTIFICATION_TELEGRAM_BOT_TOKEN", "123456:ABC-DEF")
+
+	err = telegram.SetUp()
+	if err != nil {
+		t.Errorf("Error setting up Telegram service: %v", err)
+	}
+
+	telegram.ChatID = ""
+	err = telegram.SetUp()
+	if err == nil {
+		t.Errorf("Expected error due to missing chat ID but got nil")
+	}
+
+	clearEnvVariables()
+}
+
+func TestTelegramPublish(t *testing.T) {
+
+	clearEnvVariables()
+
+	tests := []struct {
+		name           string
+		event          string
+		expectedSilent bool
+	}{
+		{
+			name:           "errors",
+			event:          "errors",
+			expectedSilent: false,
+		},
+		{
+			name:           "success",
+			event:          "success",
+			expectedSilent: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var payload struct {
+				ChatID              string `json:"chat_id"`
+				Text                string `json:"text"`
+				ParseMode           string `json:"parse_mode"`
+				DisableNotification bool   `json:"disable_notification"`
+			}
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/bot123456:ABC-DEF/sendMessage" {
+					t.Errorf("Expected the sendMessage path of the bot, got %s", r.URL.Path)
+				}
+				err := json.NewDecoder(r.Body).Decode(&payload)
+				if err != nil {
+					t.Errorf("Unable to decode the Telegram payload: %v", err)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer ts.Close()
+
+			telegram := NewTelegram()
+			telegram.ChatID = "-1001234567890"
+			telegram.BotToken = "123456:ABC-DEF"
+			telegram.Events = "all"
+			telegram.endpoint = ts.URL
+
+			err := telegram.PublishMessage(&http.Client{}, Message{
+				Event: test.event,
+				Job:   "mywhoop_data_collection_job",
+				Body:  "Data <collection> finished.",
+			})
+			if err != nil {
+				t.Fatalf("Error publishing the Telegram notification: %v", err)
+			}
+
+			if payload.ChatID != "-1001234567890" || payload.ParseMode != "HTML" {
+				t.Errorf("Expected the chat ID and the HTML parse mode, got %+v", payload)
+			}
+
+			if payload.DisableNotification != test.expectedSilent {
+				t.Errorf("Expected disable_notification %v, got %v", test.expectedSilent, payload.DisableNotification)
+			}
+
+			if !strings.Contains(payload.Text, "Data &lt;collection&gt; finished.") || !strings.Contains(payload.Text, "<i>Job: mywhoop_data_collection_job</i>") {
+				t.Errorf("Expected the escaped HTML message, got %q", payload.Text)
+			}
+		})
+	}
+}
@@ -4,23 +4,193 @@
 // Integrate different notification services to send notifications about the application status.
 package notifications
 
+import (
+	"io"
+	"net/http"
+	"text/template"
+	"time"
+)
+
 // Ntfy is a struct that contains the configuration for the Ntfy notification service.
 // Visit https://docs.ntfy.sh/ for more information.
 type Ntfy struct {
 	// AccessToken is the access token for the Ntfy service. Required if the Ntfy service requires authentication using access token. Provide the access token in the environment variable NOTIFICATION_NTFY_AUTH_TOKEN.
-	AccessToken string `yaml:"-"`
+	AccessToken string `yaml:"-" json:"-" secret:"true"`
 	// ServerEndpoint is the endpoint for the Ntfy service.
-	ServerEndpoint string `yaml:"serverEndpoint"`
+	ServerEndpoint string `yaml:"serverEndpoint" json:"serverEndpoint"`
 	// SubscriptionID is the subscription ID for the Ntfy service.
-	SubscriptionID string `yaml:"subscriptionID"`
+	SubscriptionID string `yaml:"subscriptionID" json:"subscriptionID"`
 	// UserName is the username for the Ntfy service. Required if the Ntfy service requires authentication using username and password. Provide the password in the environment variable NOTIFICATION_NTFY_PASSWORD.
-	UserName string `yaml:"userName"`
+	UserName string `yaml:"userName" json:"userName"`
 	// Password is the password for the Ntfy service. Required if the Ntfy service requires authentication using username and password. Provide the password in the environment variable NOTIFICATION_NTFY_PASSWORD.
-	Password string `yaml:"-"`
-	// Events is a list of events that the Ntfy service can send notifications for. Supported events are errors, success, or all. Default is errors.
-	Events string `yaml:"events" validate:"oneof=errors success all '' "`
+	Password string `yaml:"-" json:"-" secret:"true"`
+	// Events is a list of events that the Ntfy service can send notifications for. Supported events are errors, success, alerts, or all. Default is errors.
+	Events string `yaml:"events" json:"events" validate:"oneof=errors success alerts all '' "`
+	// Title is the title of the notifications. Defaults to a title describing the event, such as MyWhoop error.
+	Title string `yaml:"title" json:"title"`
+	// Priorities are the Ntfy priorities of the notifications per event.
+	Priorities NtfyPriorities `yaml:"priorities" json:"priorities"`
+	// Click is the URL opened when the notification is clicked. Defaults to the exported AWS S3 object for the success notifications of the data collection job.
+	Click string `yaml:"click" json:"click"`
+	// Markdown enables the Markdown formatting of the notification message.
+	Markdown bool `yaml:"markdown" json:"markdown"`
+	// AttachExport attaches the exported Whoop data to the success notifications of the data collection job.
+	AttachExport bool `yaml:"attachExport" json:"attachExport"`
+	// CACertFile is the path to a PEM encoded CA bundle used to verify the TLS certificate of a self-hosted Ntfy server.
+	CACertFile string `yaml:"caCertFile" json:"caCertFile"`
+	// InsecureSkipVerify disables the verification of the TLS certificate of the Ntfy server. Only use this option for self-signed servers in a trusted network.
+	InsecureSkipVerify bool `yaml:"insecureSkipVerify" json:"insecureSkipVerify"`
+	// transport is the HTTP transport configured with the CA bundle and TLS settings of the Ntfy server. Nil if the default transport is used.
+	transport *http.Transport
+}
+
+// NtfyPriorities contains the Ntfy priorities of the notifications per event.
+// Allowed values are min, low, default, high, max, urgent, or a number from 1 to 5.
+type NtfyPriorities struct {
+	// Errors is the priority of the error notifications. Default is high.
+	Errors string `yaml:"errors" json:"errors" validate:"oneof=min low default high max urgent 1 2 3 4 5 ''"`
+	// Success is the priority of the success notifications. Default is default.
+	Success string `yaml:"success" json:"success" validate:"oneof=min low default high max urgent 1 2 3 4 5 ''"`
+	// Alerts is the priority of the health alert notifications. Default is high.
+	Alerts string `yaml:"alerts" json:"alerts" validate:"oneof=min low default high max urgent 1 2 3 4 5 ''"`
 }
 
 // Stdout is a struct that contains the configuration for the sending messages to stdout.
-// Stdout is only used to allow for scenarios where no notification method is provided. By default messages are sent to stdout.
-type Stdout struct{}
+// Stdout is used when no notification method is provided. Every notification is written to stdout as a single structured line, so log collectors can alert on it.
+type Stdout struct {
+	// Format is the format of the notification lines. Supported formats are text, a logfmt line, or json. Default is text.
+	Format string `yaml:"format" json:"format" validate:"oneof=text json ''"`
+	// Events is a list of events that are written to stdout. Supported events are errors, success, alerts, or all. Default is all.
+	Events string `yaml:"events" json:"events" validate:"oneof=errors success alerts all ''"`
+	// writer is the destination of the notification lines. Defaults to stdout.
+	writer io.Writer
+}
+
+// Slack is a struct that contains the configuration for the Slack notification service.
+// Notifications are sent through a Slack incoming webhook. Visit https://api.slack.com/messaging/webhooks for more information.
+type Slack struct {
+	// WebhookURL is the incoming webhook URL of the Slack channel. Use an env: or file: reference to keep the secret URL out of the configuration file.
+	// The environment variable NOTIFICATION_SLACK_WEBHOOK_URL is used if no webhook URL is configured.
+	WebhookURL string `yaml:"webhookURL" json:"webhookURL" secret:"true"`
+	// Events is a list of events that the Slack service can send notifications for. Supported events are errors, success, alerts, or all. Default is errors.
+	Events string `yaml:"events" json:"events" validate:"oneof=errors success alerts all '' "`
+}
+
+// Discord is a struct that contains the configuration for the Discord notification service.
+// Notifications are sent through a Discord webhook. Visit https://support.discord.com/hc/en-us/articles/228383668 for more information.
+type Discord struct {
+	// WebhookURL is the webhook URL of the Discord channel. Use an env: or file: reference to keep the secret URL out of the configuration file.
+	// The environment variable NOTIFICATION_DISCORD_WEBHOOK_URL is used if no webhook URL is configured.
+	WebhookURL string `yaml:"webhookURL" json:"webhookURL" secret:"true"`
+	// UserName overrides the name of the webhook user that posts the notifications. Default is MyWhoop.
+	UserName string `yaml:"userName" json:"userName"`
+	// Events is a list of events that the Discord service can send notifications for. Supported events are errors, success, alerts, or all. Default is errors.
+	Events string `yaml:"events" json:"events" validate:"oneof=errors success alerts all '' "`
+}
+
+// Email is a struct that contains the configuration for the email notification service.
+// Notifications are sent through an SMTP server with an HTML and plain text body rendered from Go templates.
+type Email struct {
+	// Host is the host name of the SMTP server.
+	Host string `yaml:"host" json:"host"`
+	// Port is the port of the SMTP server. Default is 587 when TLS is starttls, 465 when TLS is tls, and 25 when TLS is none.
+	Port int `yaml:"port" json:"port"`
+	// TLS is the TLS mode used to connect to the SMTP server. Supported modes are starttls, tls for implicit TLS, or none. Default is starttls.
+	TLS string `yaml:"tls" json:"tls" validate:"oneof=starttls tls none ''"`
+	// From is the email address the notifications are sent from.
+	From string `yaml:"from" json:"from"`
+	// To is the list of email addresses the notifications are sent to.
+	To []string `yaml:"to" json:"to"`
+	// Subject is a Go template used to render the subject of the email. Default is "MyWhoop {{ .Event }} notification".
+	Subject string `yaml:"subject" json:"subject"`
+	// HTMLTemplate is the path to a Go template file used to render the HTML body of the email. A built-in template is used if no file is provided.
+	HTMLTemplate string `yaml:"htmlTemplate" json:"htmlTemplate"`
+	// TextTemplate is the path to a Go template file used to render the plain text body of the email. A built-in template is used if no file is provided.
+	TextTemplate string `yaml:"textTemplate" json:"textTemplate"`
+	// UserName is the user name used to authenticate with the SMTP server. Provide the user name in the environment variable NOTIFICATION_EMAIL_USERNAME.
+	UserName string `yaml:"-" json:"-"`
+	// Password is the password used to authenticate with the SMTP server. Provide the password in the environment variable NOTIFICATION_EMAIL_PASSWORD.
+	Password string `yaml:"-" json:"-" secret:"true"`
+	// Events is a list of events that the email service can send notifications for. Supported events are errors, success, alerts, or all. Default is errors.
+	Events string `yaml:"events" json:"events" validate:"oneof=errors success alerts all '' "`
+	// templates contains the parsed subject and body templates.
+	templates *emailTemplates
+}
+
+// Webhook is a struct that contains the configuration for the generic webhook notification service.
+// The request body is rendered from a Go text/template so that notifications can be sent to any HTTP receiver.
+type Webhook struct {
+	// URL is the URL the notifications are sent to. Use an env: or file: reference to keep a secret URL out of the configuration file.
+	URL string `yaml:"url" json:"url" secret:"true"`
+	// Method is the HTTP method of the request. Supported methods are POST, PUT, or PATCH. Default is POST.
+	Method string `yaml:"method" json:"method" validate:"oneof=POST PUT PATCH ''"`
+	// Headers are the HTTP headers added to the request. Use env: or file: references to provide secret values, such as env:HOME_ASSISTANT_TOKEN.
+	Headers map[string]string `yaml:"headers" json:"headers" secret:"true"`
+	// Body is a Go text/template used to render the request body. A JSON document with every notification field is sent if no template is provided.
+	Body string `yaml:"body" json:"body"`
+	// MaxRetries is the number of times a request is retried when the receiver responds with a 5xx status code or can't be reached. Default is 3. Set to -1 to disable retries.
+	// The value replaces the notificationDelivery maxRetries setting for the channel, and the retries are done by the notification delivery.
+	MaxRetries int `yaml:"maxRetries" json:"maxRetries"`
+	// SignatureHeader is the name of the header containing the HMAC-SHA256 signature of the request body. Default is X-MyWhoop-Signature-256.
+	SignatureHeader string `yaml:"signatureHeader" json:"signatureHeader"`
+	// Secret is the key used to sign the request body with HMAC-SHA256. The body is only signed if a secret is provided in the environment variable NOTIFICATION_WEBHOOK_SECRET.
+	Secret string `yaml:"-" json:"-" secret:"true"`
+	// Events is a list of events that the webhook service can send notifications for. Supported events are errors, success, alerts, or all. Default is errors.
+	Events string `yaml:"events" json:"events" validate:"oneof=errors success alerts all '' "`
+	// template is the parsed body template.
+	template *template.Template
+	// retryInterval is the initial interval between retries.
+	retryInterval time.Duration
+}
+
+// Gotify is a struct that contains the configuration for the Gotify notification service.
+// Visit https://gotify.net/ for more information.
+type Gotify struct {
+	// ServerEndpoint is the endpoint of the Gotify server.
+	ServerEndpoint string `yaml:"serverEndpoint" json:"serverEndpoint"`
+	// Token is the application token of the Gotify server. Provide the token in the environment variable NOTIFICATION_GOTIFY_TOKEN.
+	Token string `yaml:"-" json:"-" secret:"true"`
+	// Events is a list of events that the Gotify service can send notifications for. Supported events are errors, success, alerts, or all. Default is errors.
+	Events string `yaml:"events" json:"events" validate:"oneof=errors success alerts all '' "`
+}
+
+// Pushover is a struct that contains the configuration for the Pushover notification service.
+// Visit https://pushover.net/api for more information.
+type Pushover struct {
+	// Token is the API token of the Pushover application. Provide the token in the environment variable NOTIFICATION_PUSHOVER_TOKEN.
+	Token string `yaml:"-" json:"-" secret:"true"`
+	// UserKey is the user or group key the notifications are sent to. Provide the key in the environment variable NOTIFICATION_PUSHOVER_USER_KEY.
+	UserKey string `yaml:"-" json:"-" secret:"true"`
+	// Device is the name of the device the notifications are sent to. The notifications are sent to all the devices of the user if no device is provided.
+	Device string `yaml:"device" json:"device"`
+	// Events is a list of events that the Pushover service can send notifications for. Supported events are errors, success, alerts, or all. Default is errors.
+	Events string `yaml:"events" json:"events" validate:"oneof=errors success alerts all '' "`
+	// endpoint is the endpoint of the Pushover API.
+	endpoint string
+}
+
+// Telegram is a struct that contains the configuration for the Telegram notification service.
+// Notifications are sent by a Telegram bot. Visit https://core.telegram.org/bots/api for more information.
+type Telegram struct {
+	// ChatID is the identifier of the chat the bot sends the notifications to.
+	ChatID string `yaml:"chatID" json:"chatID"`
+	// BotToken is the token of the Telegram bot. Provide the token in the environment variable NOTIFICATION_TELEGRAM_BOT_TOKEN.
+	BotToken string `yaml:"-" json:"-" secret:"true"`
+	// Events is a list of events that the Telegram service can send notifications for. Supported events are errors, success, alerts, or all. Default is errors.
+	Events string `yaml:"events" json:"events" validate:"oneof=errors success alerts all '' "`
+	// endpoint is the endpoint of the Telegram bot API.
+	endpoint string
+}
+
+// Matrix is a struct that contains the configuration for the Matrix notification service.
+// Notifications are sent to a Matrix room through the client-server API. Visit https://spec.matrix.org/latest/client-server-api/ for more information.
+type Matrix struct {
+	// HomeServer is the URL of the Matrix homeserver, such as https://matrix.org.
+	HomeServer string `yaml:"homeServer" json:"homeServer"`
+	// RoomID is the identifier of the room the notifications are sent to, such as !abcdefg:matrix.org.
+	RoomID string `yaml:"roomID" json:"roomID"`
+	// AccessToken is the access token of the Matrix user that sends the notifications. Provide the token in the environment variable NOTIFICATION_MATRIX_ACCESS_TOKEN.
+	AccessToken string `yaml:"-" json:"-" secret:"true"`
+	// Events is a list of events that the Matrix service can send notifications for. Supported events are errors, success, alerts, or all. Default is errors.
+	Events string `yaml:"events" json:"events" validate:"oneof=errors success alerts all '' "`
+}
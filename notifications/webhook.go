@@ -0,0 +1,268 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+const (
+	// defaultWebhookMaxRetries is the default number of retries of a webhook request.
+	defaultWebhookMaxRetries = 3
+	// defaultWebhookRetryInterval is the default initial interval between the retries of a webhook request.
+	defaultWebhookRetryInterval = 500 * time.Millisecond
+	// defaultWebhookSignatureHeader is the default header containing the HMAC-SHA256 signature of the request body.
+	defaultWebhookSignatureHeader = "X-MyWhoop-Signature-256"
+	// defaultWebhookBody is the default template of the request body.
+	defaultWebhookBody = `{"event":{{ json .Event }},"message":{{ json .Message }},"error":{{ json .Error }},"hostname":{{ json .Hostname }},"job":{{ json .Job }},"account":{{ json .Account }},"timestamp":{{ json .Timestamp }}}`
+)
+
+// webhookData is the data available to the webhook body template.
+type webhookData struct {
+	// Event is the event of the notification, such as errors or success.
+	Event string
+	// Message is the notification message.
+	Message string
+	// Error is the error context of the notification. Empty if no error occurred.
+	Error string
+	// Hostname is the host name of the machine running MyWhoop.
+	Hostname string
+	// Job is the name of the job that sent the notification.
+	Job string
+	// Account is the name of the Whoop account the notification is about.
+	Account string
+	// Timestamp is the time the notification was created.
+	Timestamp time.Time
+}
+
+// NewWebhook returns a new Webhook struct with default values.
+func NewWebhook() *Webhook {
+	return &Webhook{
+		Method:          http.MethodPost,
+		MaxRetries:      defaultWebhookMaxRetries,
+		SignatureHeader: defaultWebhookSignatureHeader,
+		Events:          "errors",
+		retryInterval:   defaultWebhookRetryInterval,
+	}
+}
+
+// SetUp sets up the webhook service. The environment variable NOTIFICATION_WEBHOOK_SECRET is used to set the key that signs the request body.
+// The body template is parsed during the set up so that template errors are reported on startup.
+func (w *Webhook) SetUp() error {
+
+	secret := os.Getenv("NOTIFICATION_WEBHOOK_SECRET")
+	if secret != "" {
+		w.Secret = secret
+	}
+
+	if w.URL == "" {
+		return errors.New("no URL provided for the webhook notification")
+	}
+
+	if w.Method == "" {
+		w.Method = http.MethodPost
+	}
+
+	if w.MaxRetries == 0 {
+		w.MaxRetries = defaultWebhookMaxRetries
+	}
+
+	if w.SignatureHeader == "" {
+		w.SignatureHeader = defaultWebhookSignatureHeader
+	}
+
+	if w.Events == "" {
+		w.Events = "errors"
+	}
+
+	if w.retryInterval == 0 {
+		w.retryInterval = defaultWebhookRetryInterval
+	}
+
+	body := w.Body
+	if body == "" {
+		body = defaultWebhookBody
+	}
+
+	tmpl, err := template.New("webhook").Funcs(template.FuncMap{"json": templateJSON}).Parse(body)
+	if err != nil {
+		return fmt.Errorf("unable to parse the webhook body template: %w", err)
+	}
+	w.template = tmpl
+
+	return nil
+}
+
+// Accepts returns true if the events filter of the webhook notification accepts the event.
+func (w *Webhook) Accepts(event string) bool {
+	return canSendMsg(w.Events, event)
+}
+
+// Publish sends a notification to the webhook with the provided data.
+func (w *Webhook) Publish(client *http.Client, data []byte, event string) error {
+
+	if data == nil {
+		return errors.New("no data provided for external notification")
+	}
+
+	return w.PublishMessage(client, NewMessage(data, event))
+}
+
+// PublishMessage renders the body template with the structured message and sends it to the webhook.
+// The request is retried when the receiver responds with a 5xx status code or can't be reached.
+func (w *Webhook) PublishMessage(client *http.Client, msg Message) error {
+
+	if client == nil {
+		msg.Logger().Info("no http client specified for external notification")
+		return errors.New("no http client specified for external notification")
+	}
+
+	if msg.Event == "" {
+		return errors.New("no event provided for external notification")
+	}
+
+	if !w.Accepts(msg.Event) {
+		msg.Logger().Info("event is not eligible for external notification. Notification suppresed.")
+		return nil
+	}
+
+	if w.template == nil {
+		return errors.New("the webhook notification is not set up")
+	}
+
+	body, err := w.render(msg)
+	if err != nil {
+		return err
+	}
+
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = w.retryInterval
+	bo.MaxElapsedTime = 0
+
+	var retries uint64
+	if w.MaxRetries > 0 {
+		retries = uint64(w.MaxRetries)
+	}
+
+	op := func() error {
+		return w.send(client, body)
+	}
+
+	notify := func(err error, next time.Duration) {
+		msg.Logger().Info("unable to send the webhook notification. Retrying...", "retry in", next, "error", err)
+	}
+
+	err = backoff.RetryNotify(op, backoff.WithMaxRetries(bo, retries), notify)
+	if err != nil {
+		msg.Logger().Error("unable to send external notification", "service", "webhook", "error", err)
+		return err
+	}
+
+	msg.Logger().Info("notification sent", "service", "webhook")
+
+	return nil
+}
+
+// render renders the body template with the structured message.
+func (w *Webhook) render(msg Message) ([]byte, error) {
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		msg.Logger().Debug("unable to determine the host name", "error", err)
+	}
+
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+
+	data := webhookData{
+		Event:     msg.Event,
+		Message:   msg.Body,
+		Error:     msg.Error,
+		Hostname:  hostname,
+		Job:       msg.Job,
+		Account:   msg.Account,
+		Timestamp: msg.Timestamp,
+	}
+
+	var body bytes.Buffer
+	err = w.template.Execute(&body, data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render the webhook body template: %w", err)
+	}
+
+	return body.Bytes(), nil
+}
+
+// send sends a single request to the webhook. Errors that should not be retried are wrapped with backoff.Permanent.
+func (w *Webhook) send(client *http.Client, body []byte) error {
+
+	req, err := http.NewRequestWithContext(context.Background(), w.Method, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return backoff.Permanent(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range w.Headers {
+		req.Header.Set(key, value)
+	}
+
+	if w.Secret != "" {
+		req.Header.Set(w.SignatureHeader, "sha256="+signPayload(w.Secret, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("the webhook responded with the status code %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+
+	if resp.StatusCode >= 500 {
+		return err
+	}
+
+	return backoff.Permanent(err)
+}
+
+// signPayload returns the hex encoded HMAC-SHA256 signature of the payload.
+func signPayload(secret string, payload []byte) string {
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// templateJSON returns the JSON representation of the value. Used by templates to safely embed values in JSON documents.
+func templateJSON(value interface{}) (string, error) {
+
+	out, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
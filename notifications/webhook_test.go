@@ -0,0 +1,254 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+package notifications
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWebhookSetUp(t *testing.T) {
+
+	clearEnvVariables()
+
+	tests := []struct {
+		name          string
+		webhook       *Webhook
+		expectedError bool
+	}{
+		{
+			name:    "defaults",
+			webhook: &Webhook{URL: "http://localhost:8123/api/webhook/mywhoop"},
+		},
+		{
+			name:          "missing URL",
+			webhook:       NewWebhook(),
+			expectedError: true,
+		},
+		{
+			name:          "invalid body template",
+			webhook:       &Webhook{URL: "http://localhost:8123/api/webhook/mywhoop", Body: "{{ .Message "},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.webhook.SetUp()
+			if (err != nil) != test.expectedError {
+				t.Fatalf("Expected error: %v, got: %v", test.expectedError, err)
+			}
+
+			if test.expectedError {
+				return
+			}
+
+			if test.webhook.Method != http.MethodPost {
+				t.Errorf("Expected the POST method, got %s", test.webhook.Method)
+			}
+
+			if test.webhook.MaxRetries != defaultWebhookMaxRetries {
+				t.Errorf("Expected %d retries, got %d", defaultWebhookMaxRetries, test.webhook.MaxRetries)
+			}
+		})
+	}
+}
+
+func TestWebhookPublish(t *testing.T) {
+
+	clearEnvVariables()
+	os.Setenv("NOTIFICATION_WEBHOOK_SECRET", "s3cr3t")
+	defer clearEnvVariables()
+
+	var (
+		method  string
+		headers http.Header
+		body    []byte
+	)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		headers = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	webhook := NewWebhook()
+	webhook.URL = ts.URL
+	webhook.Method = http.MethodPut
+	webhook.Headers = map[string]string{"Authorization": "Bearer token"}
+	webhook.Events = "all"
+
+	err := webhook.SetUp()
+	if err != nil {
+		t.Fatalf("Error setting up the webhook service: %v", err)
+	}
+
+	timestamp := time.Date(2024, 10, 1, 13, 0, 0, 0, time.UTC)
+	err = webhook.PublishMessage(&http.Client{}, Message{
+		Event:     "errors",
+		Job:       "mywhoop_data_collection_job",
+		Body:      `Failed to export "data".`,
+		Error:     "access denied",
+		Timestamp: timestamp,
+	})
+	if err != nil {
+		t.Fatalf("Error publishing the webhook notification: %v", err)
+	}
+
+	if method != http.MethodPut {
+		t.Errorf("Expected the PUT method, got %s", method)
+	}
+
+	if headers.Get("Authorization") != "Bearer token" {
+		t.Errorf("Expected the configured header, got %v", headers)
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write(body)
+	expectedSignature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if headers.Get(defaultWebhookSignatureHeader) != expectedSignature {
+		t.Errorf("Expected the signature %s, got %s", expectedSignature, headers.Get(defaultWebhookSignatureHeader))
+	}
+
+	var payload map[string]string
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+		t.Fatalf("Expected a valid JSON body, got %s: %v", body, err)
+	}
+
+	hostname, _ := os.Hostname()
+	expected := map[string]string{
+		"event":     "errors",
+		"message":   `Failed to export "data".`,
+		"error":     "access denied",
+		"hostname":  hostname,
+		"job":       "mywhoop_data_collection_job",
+		"account":   "",
+		"timestamp": "2024-10-01T13:00:00Z",
+	}
+	for key, value := range expected {
+		if payload[key] != value {
+			t.Errorf("Expected %s to be %q, got %q", key, value, payload[key])
+		}
+	}
+}
+
+func TestWebhookPublishTemplate(t *testing.T) {
+
+	clearEnvVariables()
+
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		if r.Header.Get(defaultWebhookSignatureHeader) != "" {
+			t.Errorf("Expected no signature without a secret")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	webhook := NewWebhook()
+	webhook.URL = ts.URL
+	webhook.Body = `{"title": "MyWhoop {{ .Event }}", "message": {{ json .Message }}}`
+
+	err := webhook.SetUp()
+	if err != nil {
+		t.Fatalf("Error setting up the webhook service: %v", err)
+	}
+
+	err = webhook.Publish(&http.Client{}, []byte("Error running the server job. Additional context below: \n timeout"), "errors")
+	if err != nil {
+		t.Fatalf("Error publishing the webhook notification: %v", err)
+	}
+
+	expected := `{"title": "MyWhoop errors", "message": "Error running the server job."}`
+	if string(body) != expected {
+		t.Errorf("Expected the body %s, got %s", expected, body)
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+
+	clearEnvVariables()
+
+	tests := []struct {
+		name          string
+		statusCodes   []int
+		maxRetries    int
+		expectedError bool
+		expectedCalls int
+	}{
+		{
+			name:          "recovers after server errors",
+			statusCodes:   []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:    3,
+			expectedCalls: 3,
+		},
+		{
+			name:          "client errors are not retried",
+			statusCodes:   []int{http.StatusBadRequest},
+			maxRetries:    3,
+			expectedError: true,
+			expectedCalls: 1,
+		},
+		{
+			name:          "retries exhausted",
+			statusCodes:   []int{http.StatusInternalServerError},
+			maxRetries:    2,
+			expectedError: true,
+			expectedCalls: 3,
+		},
+		{
+			name:          "retries disabled",
+			statusCodes:   []int{http.StatusInternalServerError},
+			maxRetries:    -1,
+			expectedError: true,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := test.statusCodes[len(test.statusCodes)-1]
+				if calls < len(test.statusCodes) {
+					status = test.statusCodes[calls]
+				}
+				calls++
+				w.WriteHeader(status)
+			}))
+			defer ts.Close()
+
+			webhook := NewWebhook()
+			webhook.URL = ts.URL
+			webhook.MaxRetries = test.maxRetries
+			webhook.retryInterval = time.Millisecond
+
+			err := webhook.SetUp()
+			if err != nil {
+				t.Fatalf("Error setting up the webhook service: %v", err)
+			}
+
+			err = webhook.Publish(&http.Client{}, []byte("test"), "errors")
+			if (err != nil) != test.expectedError {
+				t.Errorf("Expected error: %v, got: %v", test.expectedError, err)
+			}
+
+			if calls != test.expectedCalls {
+				t.Errorf("Expected %d requests, got %d", test.expectedCalls, calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (c) karl-cardenas-coding
+// SPDX-License-Identifier: Apache-2.0
+
+// schemagen generates the JSON Schema of the MyWhoop configuration file.
+// Run the command from the root of the repository with make schema.
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/karl-cardenas-coding/mywhoop/internal"
+)
+
+func main() {
+
+	output := "docs/schema/mywhoop.schema.json"
+	if len(os.Args) > 1 {
+		output = os.Args[1]
+	}
+
+	descriptions, err := internal.LoadFieldDescriptions("internal", "export", "notifications")
+	if err != nil {
+		slog.Error("unable to load the field descriptions", "error", err)
+		os.Exit(1)
+	}
+
+	schema, err := internal.GenerateJSONSchema(descriptions)
+	if err != nil {
+		slog.Error("unable to generate the JSON schema", "error", err)
+		os.Exit(1)
+	}
+
+	err = os.WriteFile(output, schema, 0644)
+	if err != nil {
+		slog.Error("unable to write the JSON schema", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("JSON schema generated", "file", output)
+}